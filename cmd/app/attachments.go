@@ -0,0 +1,276 @@
+package main
+
+import (
+	"fintrack/internal/models"
+	"fintrack/internal/storage"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// recentForAttachments is how many of the latest transactions the menu offers.
+const recentForAttachments = 15
+
+func (app *App) attachments(codec storage.Codec) *storage.AttachmentStore {
+	return storage.NewAttachmentStore(filepath.Join(app.profiles.Dir(app.profile), storage.AttachmentsDirName), codec)
+}
+
+func (app *App) cmdAttach(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("использование: attach list ID | add ID ФАЙЛ... | open ID ВЛОЖЕНИЕ | save ID ВЛОЖЕНИЕ ПУТЬ | remove ID ВЛОЖЕНИЕ | cleanup")
+	}
+
+	fs := flag.NewFlagSet("attach "+args[0], flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "вывести вложения в формате JSON")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	rest := fs.Args()
+
+	if args[0] == "cleanup" {
+		return app.cleanupAttachments()
+	}
+	if len(rest) == 0 {
+		return fmt.Errorf("укажите ID транзакции")
+	}
+	transaction, err := app.findTransaction(rest[0])
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "list":
+		if *asJSON {
+			return printJSON(transaction.Attachments)
+		}
+		app.printAttachments(transaction)
+		return nil
+	case "add":
+		if len(rest) < 2 {
+			return fmt.Errorf("использование: attach add ID ФАЙЛ...")
+		}
+		for _, path := range rest[1:] {
+			if err := app.attachFile(transaction.ID, path); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "open":
+		if len(rest) != 2 {
+			return fmt.Errorf("использование: attach open ID ВЛОЖЕНИЕ")
+		}
+		return app.openAttachment(transaction.ID, rest[1])
+	case "save":
+		if len(rest) != 3 {
+			return fmt.Errorf("использование: attach save ID ВЛОЖЕНИЕ ПУТЬ")
+		}
+		return app.saveAttachment(transaction.ID, rest[1], rest[2])
+	case "remove":
+		if len(rest) != 2 {
+			return fmt.Errorf("использование: attach remove ID ВЛОЖЕНИЕ")
+		}
+		return app.detachFile(transaction.ID, rest[1])
+	default:
+		return fmt.Errorf("неизвестное действие: %s (доступно list, add, open, save, remove, cleanup)", args[0])
+	}
+}
+
+func (app *App) showAttachments() error {
+	clearScreen()
+	fmt.Println(ColorBlue.Render("=================== Вложения ==================="))
+
+	transactions, err := app.transactionService.GetAllTransactions()
+	if err != nil {
+		return err
+	}
+	if len(transactions) == 0 {
+		fmt.Println(ColorYellow.Render("Нет транзакций."))
+		return nil
+	}
+	recent := transactions
+	if len(recent) > recentForAttachments {
+		recent = recent[len(recent)-recentForAttachments:]
+	}
+	for i, t := range recent {
+		fmt.Printf("%2d. %s  %-25s %10.2f  вложений: %d\n",
+			i+1, t.Date.Local().Format(dayLayout), t.Description, t.Amount, len(t.Attachments))
+	}
+
+	choice, err := app.readLine("\nНомер транзакции или ее ID, cleanup — удалить файлы без транзакций (Enter — назад): ")
+	if err != nil || choice == "" {
+		return err
+	}
+	if choice == "cleanup" {
+		return app.cleanupAttachments()
+	}
+	var transaction models.Transaction
+	if n, convErr := strconv.Atoi(choice); convErr == nil {
+		if n < 1 || n > len(recent) {
+			return fmt.Errorf("неверный номер транзакции")
+		}
+		transaction = recent[n-1]
+	} else if transaction, err = app.findTransaction(choice); err != nil {
+		return err
+	}
+
+	for {
+		fmt.Println()
+		app.printAttachments(transaction)
+		action, err := app.readLine("\nФАЙЛ — приложить, o N — открыть, d N — открепить (Enter — назад): ")
+		if err != nil || action == "" {
+			return err
+		}
+
+		switch {
+		case strings.HasPrefix(action, "o "):
+			err = app.openAttachment(transaction.ID, strings.TrimSpace(action[2:]))
+		case strings.HasPrefix(action, "d "):
+			err = app.detachFile(transaction.ID, strings.TrimSpace(action[2:]))
+		default:
+			err = app.attachFile(transaction.ID, action)
+		}
+		if err != nil {
+			fmt.Println(ColorRed.Render("Ошибка: " + err.Error()))
+		}
+		if transaction, err = app.transactionService.GetTransactionByID(transaction.ID); err != nil {
+			return err
+		}
+	}
+}
+
+func (app *App) printAttachments(t models.Transaction) {
+	fmt.Println(ColorCyan.Render(fmt.Sprintf("%s, %s, %s", t.Description, app.formatMoney(t.Amount), t.Date.Local().Format(dayLayout))))
+	if len(t.Attachments) == 0 {
+		fmt.Println(ColorYellow.Render("Вложений нет."))
+		return
+	}
+	for i, a := range t.Attachments {
+		fmt.Printf("%2d. %-35s %10s  %s\n", i+1, a.Name, formatSize(a.Size), a.AddedAt.Local().Format(dayLayout))
+	}
+}
+
+func (app *App) attachFile(transactionID, path string) error {
+	attachment, err := app.attachmentService.Attach(transactionID, strings.Trim(path, `"'`))
+	if err != nil {
+		return err
+	}
+	fmt.Println(ColorGreen.Render(fmt.Sprintf("Приложен файл %s (%s).", attachment.Name, formatSize(attachment.Size))))
+	return nil
+}
+
+func (app *App) detachFile(transactionID, ref string) error {
+	attachment, err := app.attachmentService.Detach(transactionID, ref)
+	if err != nil {
+		return err
+	}
+	fmt.Println(ColorGreen.Render("Откреплен файл " + attachment.Name + "."))
+	return nil
+}
+
+func (app *App) saveAttachment(transactionID, ref, path string) error {
+	attachment, err := app.attachmentService.Find(transactionID, ref)
+	if err != nil {
+		return err
+	}
+	content, err := app.attachmentService.Content(attachment)
+	if err != nil {
+		return err
+	}
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		path = filepath.Join(path, attachment.Name)
+	}
+	if err := os.WriteFile(path, content, 0600); err != nil {
+		return err
+	}
+	fmt.Println(ColorGreen.Render("Сохранено в " + path))
+	return nil
+}
+
+// openAttachment writes the file to a temporary directory, decrypted when
+// the profile is encrypted, and opens it with the system viewer.
+func (app *App) openAttachment(transactionID, ref string) error {
+	attachment, err := app.attachmentService.Find(transactionID, ref)
+	if err != nil {
+		return err
+	}
+	content, err := app.attachmentService.Content(attachment)
+	if err != nil {
+		return err
+	}
+
+	dir, err := os.MkdirTemp("", "fintrack-")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, filepath.Base(attachment.Name))
+	if err := os.WriteFile(path, content, 0600); err != nil {
+		return err
+	}
+
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "windows":
+		cmd = exec.Command("cmd", "/c", "start", "", path)
+	case "darwin":
+		cmd = exec.Command("open", path)
+	default:
+		cmd = exec.Command("xdg-open", path)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("не удалось открыть файл, он сохранен в %s: %w", path, err)
+	}
+	fmt.Println(ColorGreen.Render("Открыт файл " + path))
+	return nil
+}
+
+// cleanupAttachments drops the files of deleted transactions that can no
+// longer be undone. It only runs on request, so a backup can be taken first.
+func (app *App) cleanupAttachments() error {
+	removed, err := app.attachmentService.Cleanup()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Удалено файлов без транзакций: %d\n", len(removed))
+	return nil
+}
+
+// findTransaction looks a transaction up by its ID or a unique ID prefix.
+func (app *App) findTransaction(ref string) (models.Transaction, error) {
+	transactions, err := app.transactionService.GetAllTransactions()
+	if err != nil {
+		return models.Transaction{}, err
+	}
+	var found []models.Transaction
+	for _, t := range transactions {
+		if t.ID == ref {
+			return t, nil
+		}
+		if strings.HasPrefix(t.ID, ref) {
+			found = append(found, t)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return models.Transaction{}, fmt.Errorf("транзакция %s не найдена", ref)
+	case 1:
+		return found[0], nil
+	default:
+		return models.Transaction{}, fmt.Errorf("ID %s подходит к %d транзакциям, уточните его", ref, len(found))
+	}
+}
+
+func formatSize(size int64) string {
+	switch {
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f МБ", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1f КБ", float64(size)/(1<<10))
+	default:
+		return fmt.Sprintf("%d Б", size)
+	}
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fintrack/internal/models"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const anomaliesFileName = "anomalies.json"
+
+func (app *App) anomaliesFile() string {
+	return filepath.Join(app.profiles.Dir(app.profile), anomaliesFileName)
+}
+
+func (app *App) cmdAttention(args []string) error {
+	action := ""
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		action, args = args[0], args[1:]
+	}
+
+	fs := flag.NewFlagSet("attention", flag.ContinueOnError)
+	all := fs.Bool("all", false, "показать и просмотренные")
+	asJSON := fs.Bool("json", false, "вывести отчет в формате JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	switch action {
+	case "", "list":
+		anomalies, err := app.anomalyService.Attention(*all)
+		if err != nil {
+			return err
+		}
+		if *asJSON {
+			return printJSON(anomalies)
+		}
+		app.printAnomalies(anomalies)
+		return nil
+	case "scan":
+		found, err := app.anomalyService.Analyze()
+		if err != nil {
+			return err
+		}
+		if *asJSON {
+			return printJSON(found)
+		}
+		fmt.Printf("Найдено новых отклонений: %d\n", len(found))
+		app.printAnomalies(found)
+		return nil
+	case "dismiss":
+		if fs.NArg() != 1 {
+			return fmt.Errorf("использование: attention dismiss НОМЕРА|all")
+		}
+		anomalies, err := app.anomalyService.Attention(false)
+		if err != nil {
+			return err
+		}
+		return app.dismissAnomalies(anomalies, fs.Arg(0))
+	default:
+		return fmt.Errorf("неизвестное действие: %s (доступно list, scan, dismiss)", action)
+	}
+}
+
+func (app *App) showAttention() error {
+	clearScreen()
+	fmt.Println(ColorBlue.Render("=================== Требует внимания ==================="))
+
+	anomalies, err := app.anomalyService.Attention(false)
+	if err != nil {
+		return err
+	}
+	app.printAnomalies(anomalies)
+
+	prompt := "\nНомера просмотренных через запятую, all — все, scan — проверить всю историю (Enter — назад): "
+	choice, err := app.readLine(prompt)
+	if err != nil || choice == "" {
+		return err
+	}
+	if strings.EqualFold(choice, "scan") {
+		found, err := app.anomalyService.Analyze()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Найдено новых отклонений: %d\n", len(found))
+		app.printAnomalies(found)
+		return nil
+	}
+	return app.dismissAnomalies(anomalies, choice)
+}
+
+func (app *App) printAnomalies(anomalies []models.Anomaly) {
+	if len(anomalies) == 0 {
+		fmt.Println(ColorGreen.Render("Ничего подозрительного."))
+		return
+	}
+	for i, a := range anomalies {
+		line := fmt.Sprintf("%2d. %s  %-25s %-15s %10.2f  %s",
+			i+1, a.Date.Local().Format(dayLayout), a.Description, a.Category, a.Amount, app.anomalyReason(a))
+		if a.Dismissed {
+			fmt.Println(ColorWhite.Render(line + "  (просмотрено)"))
+			continue
+		}
+		fmt.Println(ColorYellow.Render(line))
+	}
+}
+
+func (app *App) anomalyReason(a models.Anomaly) string {
+	switch a.Kind {
+	case models.AnomalyCategorySpike:
+		return fmt.Sprintf("за 30 дней на «%s» потрачено %s, обычно %s", a.Category, app.formatMoney(a.Observed), app.formatMoney(a.Expected))
+	case models.AnomalyLargeExpense:
+		return fmt.Sprintf("крупный расход, обычно %s", app.formatMoney(a.Expected))
+	case models.AnomalyNewMerchant:
+		return "первая покупка с таким описанием"
+	default:
+		return string(a.Kind)
+	}
+}
+
+// warnAnomalies prints the anomalies flagged for a just added transaction.
+func (app *App) warnAnomalies(transactionID string) {
+	anomalies, err := app.anomalyService.Attention(false)
+	if err != nil {
+		return
+	}
+	for _, a := range anomalies {
+		if a.TransactionID == transactionID {
+			fmt.Println(ColorYellow.Render("Обратите внимание: " + app.anomalyReason(a)))
+		}
+	}
+}
+
+// dismissAnomalies marks the anomalies with the given 1-based numbers, or
+// all of them, as reviewed.
+func (app *App) dismissAnomalies(anomalies []models.Anomaly, choice string) error {
+	var ids []string
+	if strings.EqualFold(strings.TrimSpace(choice), "all") {
+		for _, a := range anomalies {
+			ids = append(ids, a.ID)
+		}
+	} else {
+		for _, field := range strings.Split(choice, ",") {
+			n, err := strconv.Atoi(strings.TrimSpace(field))
+			if err != nil || n < 1 || n > len(anomalies) {
+				return fmt.Errorf("некорректный номер: %q", strings.TrimSpace(field))
+			}
+			ids = append(ids, anomalies[n-1].ID)
+		}
+	}
+
+	if len(ids) == 0 {
+		fmt.Println(ColorYellow.Render("Нечего отмечать."))
+		return nil
+	}
+	if err := app.anomalyService.Dismiss(ids...); err != nil {
+		return err
+	}
+	fmt.Println(ColorGreen.Render(fmt.Sprintf("Отмечено как просмотренное: %d", len(ids))))
+	return nil
+}
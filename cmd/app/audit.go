@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fintrack/internal/audit"
+	"fintrack/internal/models"
+	"flag"
+	"fmt"
+	"os/user"
+	"path/filepath"
+)
+
+func (app *App) auditFile() string {
+	return filepath.Join(app.profiles.Dir(app.profile), audit.FileName)
+}
+
+// actor names the author of audit entries: the OS user and where the change came from.
+func actor(source string) string {
+	name := "unknown"
+	if u, err := user.Current(); err == nil {
+		name = u.Username
+	}
+	return fmt.Sprintf("%s (%s)", name, source)
+}
+
+func (app *App) cmdAudit(args []string) error {
+	fs := flag.NewFlagSet("audit", flag.ContinueOnError)
+	last := fs.Int("last", 20, "показать последние N записей (0 — все)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	entries, err := app.auditLog.Entries()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		fmt.Println(ColorYellow.Render("Журнал аудита пуст."))
+		return nil
+	}
+	if *last > 0 && len(entries) > *last {
+		entries = entries[len(entries)-*last:]
+	}
+
+	for _, e := range entries {
+		fmt.Printf("%s %s %s\n",
+			ColorCyan.Render(fmt.Sprintf("#%d", e.Seq)),
+			e.Time.Local().Format(app.cfg.DateLayout()),
+			e.Actor)
+		fmt.Printf("  %s %s %s\n", auditActionName(e.Action), e.Entity, e.EntityID)
+		if len(e.Before) > 0 {
+			fmt.Println(ColorRed.Render("  - " + string(e.Before)))
+		}
+		if len(e.After) > 0 {
+			fmt.Println(ColorGreen.Render("  + " + string(e.After)))
+		}
+	}
+	return nil
+}
+
+// cmdVerify checks the hash chain of the audit log.
+func (app *App) cmdVerify() error {
+	result, err := app.auditLog.Verify()
+	if err != nil {
+		return err
+	}
+
+	if !result.OK() {
+		return fmt.Errorf("журнал аудита поврежден в записи #%d: %s", result.Broken, result.Reason)
+	}
+
+	fmt.Println(ColorGreen.Render(fmt.Sprintf("Журнал аудита не изменен: записей %d.", result.Entries)))
+	if result.Head != "" {
+		fmt.Println("Хеш последней записи: " + result.Head)
+		fmt.Println(ColorYellow.Render("Сохраните его отдельно, чтобы позже обнаружить и удаление последних записей."))
+	}
+	return nil
+}
+
+func auditActionName(action models.AuditAction) string {
+	switch action {
+	case models.AuditCreate:
+		return "создание"
+	case models.AuditUpdate:
+		return "изменение"
+	case models.AuditDelete:
+		return "удаление"
+	default:
+		return string(action)
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fintrack/internal/backup"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func (app *App) cmdBackup(args []string) error {
+	action := "create"
+	if len(args) > 0 {
+		action = args[0]
+	}
+
+	switch action {
+	case "create":
+		path, err := app.backups.Create("вручную", true)
+		if err != nil {
+			return err
+		}
+		fmt.Println(ColorGreen.Render("Резервная копия создана: " + path))
+		return app.pruneBackups()
+	case "list":
+		list, err := app.backups.List()
+		if err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			fmt.Println(ColorYellow.Render("Резервных копий нет."))
+			return nil
+		}
+		fmt.Println(ColorCyan.Render("Резервные копии в " + app.backups.Dir() + ":"))
+		for _, b := range list {
+			fmt.Printf("  %s  %-45s %8.1f КБ\n", b.Created.Format(app.cfg.DateLayout()), filepath.Base(b.Path), float64(b.Size)/1024)
+		}
+		return nil
+	case "verify":
+		if len(args) != 2 {
+			return fmt.Errorf("использование: backup verify ФАЙЛ")
+		}
+		manifest, err := backup.Verify(app.backupPath(args[1]))
+		if err != nil {
+			return fmt.Errorf("архив поврежден: %w", err)
+		}
+		fmt.Println(ColorGreen.Render(fmt.Sprintf("Архив в порядке: файлов %d, создан %s (%s).",
+			len(manifest.Files), manifest.Created.Local().Format(app.cfg.DateLayout()), manifest.Reason)))
+		return nil
+	case "prune":
+		return app.pruneBackups()
+	default:
+		return fmt.Errorf("неизвестное действие: %s (доступны create, list, verify, prune)", action)
+	}
+}
+
+func (app *App) pruneBackups() error {
+	removed, err := app.backups.Prune()
+	if err != nil {
+		return err
+	}
+	if len(removed) > 0 {
+		fmt.Println(ColorYellow.Render(fmt.Sprintf("Удалено старых копий: %d", len(removed))))
+	}
+	return nil
+}
+
+func (app *App) cmdRestore(args []string) error {
+	fs := flag.NewFlagSet("restore", flag.ContinueOnError)
+	yes := fs.Bool("y", false, "не спрашивать подтверждение")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("использование: restore [-y] ФАЙЛ")
+	}
+
+	path := app.backupPath(fs.Arg(0))
+	manifest, err := backup.Verify(path)
+	if err != nil {
+		return fmt.Errorf("архив не прошел проверку, данные не изменены: %w", err)
+	}
+
+	fmt.Println(ColorYellow.Render(fmt.Sprintf("Все данные в %s будут заменены копией от %s (файлов: %d).",
+		app.cfg.DataDir, manifest.Created.Local().Format(app.cfg.DateLayout()), len(manifest.Files))))
+	if !*yes {
+		fmt.Print(ColorCyan.Render("Продолжить? (д/н): "))
+		if !app.scanner.Scan() {
+			return fmt.Errorf("ошибка чтения ответа")
+		}
+		if answer := strings.ToLower(strings.TrimSpace(app.scanner.Text())); answer != "д" && answer != "y" {
+			fmt.Println("Восстановление отменено.")
+			return nil
+		}
+	}
+
+	if err := app.backups.Restore(path); err != nil {
+		return err
+	}
+	fmt.Println(ColorGreen.Render("Данные восстановлены. Текущее состояние до восстановления сохранено в " + app.backups.Dir()))
+	return nil
+}
+
+// backupPath accepts either a path or the name of an archive in the backups directory.
+func (app *App) backupPath(name string) string {
+	if _, err := os.Stat(name); err == nil {
+		return name
+	}
+	return filepath.Join(app.backups.Dir(), name)
+}
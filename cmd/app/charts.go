@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fintrack/internal/charts"
+	"fintrack/internal/models"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultSparklineDays = 30
+
+func (app *App) showCharts() error {
+	clearScreen()
+	fmt.Println(ColorBlue.Render("======================= Графики ======================="))
+	fmt.Println(ColorWhite.Render("1. Расходы по категориям"))
+	fmt.Println(ColorWhite.Render("2. Расходы по дням"))
+	fmt.Println(ColorWhite.Render("3. Доходы и расходы по месяцам"))
+	fmt.Print(ColorCyan.Render("\nВыберите график: "))
+
+	if !app.scanner.Scan() {
+		return fmt.Errorf("ошибка чтения выбора")
+	}
+
+	opts := charts.DetectOptions()
+
+	switch strings.TrimSpace(app.scanner.Text()) {
+	case "1":
+		return app.printCategoryChart(opts)
+	case "2":
+		fmt.Print(ColorCyan.Render(fmt.Sprintf("Количество дней (по умолчанию %d): ", defaultSparklineDays)))
+		if !app.scanner.Scan() {
+			return fmt.Errorf("ошибка чтения количества дней")
+		}
+
+		days := defaultSparklineDays
+		if s := strings.TrimSpace(app.scanner.Text()); s != "" {
+			n, err := strconv.Atoi(s)
+			if err != nil || n <= 0 {
+				return fmt.Errorf("количество дней должно быть положительным числом")
+			}
+			days = n
+		}
+		return app.printDailyChart(days, opts)
+	case "3":
+		return app.printMonthlyChart(0, opts)
+	default:
+		return fmt.Errorf("неверный выбор графика. Выберите 1, 2 или 3")
+	}
+}
+
+func (app *App) printCategoryChart(opts charts.Options) error {
+	totals, err := app.reportService.ExpensesByCategory(time.Time{}, time.Time{})
+	if err != nil {
+		return fmt.Errorf("ошибка построения графика: %v", err)
+	}
+
+	if len(totals) == 0 {
+		fmt.Println(ColorYellow.Render("Нет расходов для отображения."))
+		return nil
+	}
+
+	bars := make([]charts.Bar, 0, len(totals))
+	for _, t := range totals {
+		bars = append(bars, charts.Bar{Label: t.Category, Value: t.Total})
+	}
+
+	fmt.Println(ColorCyan.Render("Расходы по категориям:"))
+	fmt.Print(charts.BarChart(bars, opts))
+	return nil
+}
+
+func (app *App) printDailyChart(days int, opts charts.Options) error {
+	totals, err := app.reportService.DailyExpenses(days)
+	if err != nil {
+		return fmt.Errorf("ошибка построения графика: %v", err)
+	}
+
+	values := make([]float64, len(totals))
+	sum, peak := 0.0, models.DailyTotal{}
+	for i, t := range totals {
+		values[i] = t.Expense
+		sum += t.Expense
+		if t.Expense > peak.Expense {
+			peak = t
+		}
+	}
+
+	fmt.Println(ColorCyan.Render(fmt.Sprintf("Расходы за последние %d дн.:", days)))
+	fmt.Println(charts.Sparkline(values, opts))
+	fmt.Printf("%s — %s\n", totals[0].Date.Format("02.01"), totals[len(totals)-1].Date.Format("02.01"))
+	fmt.Printf("Всего: %.2f | В среднем за день: %.2f", sum, sum/float64(len(totals)))
+	if peak.Expense > 0 {
+		fmt.Printf(" | Максимум: %.2f (%s)", peak.Expense, peak.Date.Format("02.01.2006"))
+	}
+	fmt.Println()
+	return nil
+}
+
+// printMonthlyChart prints the stacked income/expense chart for the last `limit` months; limit <= 0 prints all.
+func (app *App) printMonthlyChart(limit int, opts charts.Options) error {
+	summaries, err := app.reportService.Summaries(models.PeriodMonth)
+	if err != nil {
+		return fmt.Errorf("ошибка построения графика: %v", err)
+	}
+
+	if len(summaries) == 0 {
+		fmt.Println(ColorYellow.Render("Нет доступных транзакций для отображения."))
+		return nil
+	}
+
+	if limit > 0 && len(summaries) > limit {
+		summaries = summaries[len(summaries)-limit:]
+	}
+
+	rows := make([]charts.StackedBar, 0, len(summaries))
+	for _, s := range summaries {
+		rows = append(rows, charts.StackedBar{Label: s.Label, Income: s.Income, Expense: s.Expense})
+	}
+
+	fmt.Println(ColorCyan.Render("Доходы и расходы по месяцам:"))
+	fmt.Print(charts.StackedChart(rows, opts))
+	return nil
+}
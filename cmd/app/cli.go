@@ -0,0 +1,273 @@
+package main
+
+import (
+	"encoding/json"
+	"fintrack/internal/api"
+	"fintrack/internal/charts"
+	"fintrack/internal/config"
+	"fintrack/internal/tui"
+	"fintrack/internal/web"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+)
+
+// runCommand executes a non-interactive command given on the command line.
+func runCommand(app *App, args []string) error {
+	switch args[0] {
+	case "add":
+		return app.cmdAdd(args[1:])
+	case "receipt":
+		return app.cmdReceipt(args[1:])
+	case "attach":
+		return app.cmdAttach(args[1:])
+	case "report":
+		return app.cmdReport(args[1:])
+	case "chart":
+		return app.cmdChart(args[1:])
+	case "heatmap":
+		return app.cmdHeatmap(args[1:])
+	case "config":
+		return app.cmdConfig(args[1:])
+	case "encrypt":
+		return app.enableEncryption()
+	case "decrypt":
+		return app.disableEncryption()
+	case "passphrase":
+		return app.changePassphrase()
+	case "backup":
+		return app.cmdBackup(args[1:])
+	case "restore":
+		return app.cmdRestore(args[1:])
+	case "category":
+		return app.cmdCategory(args[1:])
+	case "asof":
+		return app.cmdAsOf(args[1:])
+	case "goal":
+		return app.cmdGoal(args[1:])
+	case "debt":
+		return app.cmdDebt(args[1:])
+	case "networth":
+		return app.cmdNetWorth(args[1:])
+	case "forecast":
+		return app.cmdForecast(args[1:])
+	case "recurring":
+		return app.cmdRecurring(args[1:])
+	case "envelope":
+		return app.cmdEnvelope(args[1:])
+	case "subscriptions":
+		return app.cmdSubscriptions(args[1:])
+	case "attention":
+		return app.cmdAttention(args[1:])
+	case "payee":
+		return app.cmdPayee(args[1:])
+	case "undo":
+		return app.undo()
+	case "redo":
+		return app.redo()
+	case "history":
+		return app.showHistory()
+	case "audit":
+		return app.cmdAudit(args[1:])
+	case "verify":
+		return app.cmdVerify()
+	case "profile":
+		return app.cmdProfile(args[1:])
+	case "serve":
+		return app.cmdServe(args[1:])
+	case "tui":
+		app.auditLog.SetActor(actor("tui"))
+		return tui.Run(app.transactionService, app.categoryService, app.reportService, app.history)
+	case "help", "-h", "--help":
+		printUsage()
+		return nil
+	default:
+		printUsage()
+		return fmt.Errorf("неизвестная команда: %s", args[0])
+	}
+}
+
+// commandHelp lists the commands shown by printUsage.
+var commandHelp = []struct {
+	name        string
+	description string
+}{
+	{"add", "быстрый ввод: add [-category КАТЕГОРИЯ] [-y] кофе 250 вчера #работа | +50000 зарплата 05.10"},
+	{"receipt", "чек по QR-коду: receipt [-file ФАЙЛ] [-category КАТЕГОРИЯ] [-description ТЕКСТ] [-y] [СТРОКА] | list (-json)"},
+	{"attach", "вложения: list ID (-json) | add ID ФАЙЛ... | open ID N | save ID N ПУТЬ | remove ID N | cleanup"},
+	{"report", "отчет по периодам (-period month|quarter|year, -last N, -json)"},
+	{"chart", "графики (-kind category|daily|monthly, -days N, -last N, -width N, -ascii)"},
+	{"heatmap", "календарь расходов (-year ГГГГ, -month 1-12, -day ДД.ММ.ГГГГ, -ascii)"},
+	{"config", "настройки: path | show | init | set КЛЮЧ ЗНАЧЕНИЕ"},
+	{"encrypt", "зашифровать данные текущего профиля"},
+	{"passphrase", "сменить парольную фразу и перешифровать данные"},
+	{"decrypt", "отключить шифрование"},
+	{"backup", "резервные копии: create | list | verify ФАЙЛ | prune"},
+	{"restore", "восстановить данные из копии (-y без подтверждения)"},
+	{"category", "категории: list | rename СТАРОЕ НОВОЕ"},
+	{"asof", "учет на конец дня ДД.ММ.ГГГГ (-json), нужен storage_backend = events"},
+	{"goal", "цели накоплений: list (-json) | add -target СУММА НАЗВАНИЕ | allocate ЦЕЛЬ СУММА | show ЦЕЛЬ | delete ЦЕЛЬ"},
+	{"debt", "долги и кредиты: list (-json) | add | pay ДОЛГ СУММА | link/unlink ДОЛГ ID | schedule ДОЛГ | show ДОЛГ | delete ДОЛГ"},
+	{"networth", "капитал (-date ДД.ММ.ГГГГ, -json) | asset add|value|delete | assets | snapshot (-backfill) | trend (-last N)"},
+	{"forecast", "прогноз баланса по дням (-days N, -lookback N, -all, -json)"},
+	{"recurring", "регулярные платежи: list | add -category КАТЕГОРИЯ СУММА ОПИСАНИЕ | delete ОПИСАНИЕ"},
+	{"envelope", "бюджет по конвертам (-month ММ.ГГГГ, -json) | assign КАТЕГОРИЯ СУММА | move ИЗ В СУММА"},
+	{"subscriptions", "найденные подписки (-json) | track НОМЕРА|all — добавить в регулярные платежи"},
+	{"attention", "отклонения в расходах (-all, -json) | scan — проверить всю историю | dismiss НОМЕРА|all"},
+	{"payee", "получатели: list (-json) | add [-category К] [-aliases А,Б] ИМЯ | alias П ПСЕВДОНИМ | category П [К] | delete П | top (-by spend|count, -days N, -last N, -json)"},
+	{"undo", "отменить последнее действие"},
+	{"redo", "повторить отмененное действие"},
+	{"history", "список действий, которые можно отменить или повторить"},
+	{"audit", "журнал изменений (-last N)"},
+	{"verify", "проверить целостность журнала изменений"},
+	{"profile", "профили: list | create ИМЯ | use ИМЯ | passwd ИМЯ"},
+	{"serve", "веб-интерфейс и HTTP API (-addr 127.0.0.1:8080, -no-web), описание API: /api/openapi.yaml"},
+	{"tui", "полноэкранный интерфейс"},
+	{"help", "показать эту справку"},
+}
+
+func printUsage() {
+	fmt.Println("Использование: fintrack [глобальные флаги] [команда] [флаги]")
+	fmt.Println()
+	fmt.Println("Глобальные флаги:")
+	fmt.Println("  -profile ИМЯ       профиль (учетная книга)")
+	fmt.Println("  -config ПУТЬ       файл настроек (по умолчанию " + config.Path() + ")")
+	fmt.Println("  -data-dir ПУТЬ     каталог с данными")
+	fmt.Println("  -currency, -locale, -theme, -date-format, -storage, -history-depth  переопределить настройки")
+	fmt.Println()
+	fmt.Println("Переменные окружения FINTRACK_CONFIG, FINTRACK_DATA_DIR, FINTRACK_CURRENCY, FINTRACK_LOCALE,")
+	fmt.Println("FINTRACK_THEME, FINTRACK_DATE_FORMAT, FINTRACK_STORAGE и FINTRACK_HISTORY_DEPTH переопределяют файл настроек.")
+	fmt.Println("FINTRACK_PASSPHRASE задает парольную фразу зашифрованного профиля.")
+	fmt.Println()
+	fmt.Println("Без команды запускается интерактивное меню.")
+	fmt.Println()
+	fmt.Println("Команды:")
+	for _, c := range commandHelp {
+		fmt.Printf("  %-14s %s\n", c.name, c.description)
+	}
+}
+
+func (app *App) cmdReport(args []string) error {
+	fs := flag.NewFlagSet("report", flag.ContinueOnError)
+	periodFlag := fs.String("period", "month", "период группировки: month, quarter или year")
+	last := fs.Int("last", 0, "показать только последние N периодов")
+	asJSON := fs.Bool("json", false, "вывести отчет в формате JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	period, err := parsePeriodFlag(*periodFlag)
+	if err != nil {
+		return err
+	}
+
+	if !*asJSON {
+		return app.printReport(period, *last)
+	}
+
+	summaries, err := app.reportService.Summaries(period)
+	if err != nil {
+		return err
+	}
+	if *last > 0 && len(summaries) > *last {
+		summaries = summaries[len(summaries)-*last:]
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(summaries)
+}
+
+func (app *App) cmdChart(args []string) error {
+	fs := flag.NewFlagSet("chart", flag.ContinueOnError)
+	kind := fs.String("kind", "category", "вид графика: category, daily или monthly")
+	days := fs.Int("days", defaultSparklineDays, "количество дней для графика daily")
+	last := fs.Int("last", 0, "показать только последние N месяцев для графика monthly")
+	width := fs.Int("width", 0, "ширина графика (по умолчанию ширина терминала)")
+	ascii := fs.Bool("ascii", false, "рисовать без цвета символами ASCII")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	opts := charts.DetectOptions()
+	if *width > 0 {
+		opts.Width = *width
+	}
+	if *ascii {
+		opts.Color = false
+	}
+
+	switch *kind {
+	case "category":
+		return app.printCategoryChart(opts)
+	case "daily":
+		return app.printDailyChart(*days, opts)
+	case "monthly":
+		return app.printMonthlyChart(*last, opts)
+	default:
+		return fmt.Errorf("неизвестный вид графика: %s", *kind)
+	}
+}
+
+func (app *App) cmdHeatmap(args []string) error {
+	fs := flag.NewFlagSet("heatmap", flag.ContinueOnError)
+	year := fs.Int("year", time.Now().Year(), "год календаря")
+	month := fs.Int("month", 0, "месяц 1-12 (0 — весь год)")
+	dayFlag := fs.String("day", "", "показать транзакции за день ДД.ММ.ГГГГ")
+	ascii := fs.Bool("ascii", false, "рисовать без цвета символами ASCII")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *month < 0 || *month > 12 {
+		return fmt.Errorf("месяц должен быть от 1 до 12")
+	}
+
+	var day time.Time
+	if *dayFlag != "" {
+		d, err := time.ParseInLocation(dayLayout, *dayFlag, time.Local)
+		if err != nil {
+			return fmt.Errorf("некорректная дата %q, используйте формат ДД.ММ.ГГГГ", *dayFlag)
+		}
+		day = d
+	}
+
+	opts := charts.DetectOptions()
+	if *ascii {
+		opts.Color = false
+	}
+
+	if err := app.printHeatmap(*year, *month, day, opts); err != nil {
+		return err
+	}
+	if !day.IsZero() {
+		return app.printDayTransactions(day)
+	}
+	return nil
+}
+
+func (app *App) cmdServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := fs.String("addr", "127.0.0.1:8080", "адрес для прослушивания (0.0.0.0:8080 — доступ из домашней сети)")
+	noWeb := fs.Bool("no-web", false, "не отдавать веб-интерфейс, только API")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	app.auditLog.SetActor(actor("api " + *addr))
+	server := api.NewServer(app.transactionService, app.categoryService, app.reportService)
+	if app.history != nil {
+		server.EnableHistory(app.history)
+	}
+	if app.profile.HasPassword() {
+		server.RequireBasicAuth("FinTrack: "+app.profile.Name, app.checkProfileAuth)
+		fmt.Println(ColorYellow.Render(fmt.Sprintf("Профиль %s защищен паролем: пользователь %q", app.profile.Name, app.profile.Name)))
+	}
+	if !*noWeb {
+		server.Mux().Handle("GET /", web.Handler())
+		fmt.Println(ColorGreen.Render(fmt.Sprintf("Веб-интерфейс FinTrack доступен на http://%s/", *addr)))
+	}
+	fmt.Println(ColorGreen.Render(fmt.Sprintf("FinTrack API доступен на http://%s/api/ (Ctrl+C для остановки)", *addr)))
+	return server.ListenAndServe(*addr)
+}
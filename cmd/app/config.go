@@ -0,0 +1,190 @@
+package main
+
+import (
+	"fintrack/internal/config"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// legacyDataDir is where FinTrack kept its files before the data directory became configurable.
+const legacyDataDir = "internal/data"
+
+// applyTheme replaces the shared color styles according to the configured theme.
+func applyTheme(theme string) {
+	switch theme {
+	case config.ThemeMono:
+		plain := lipgloss.NewStyle()
+		ColorRed, ColorGreen, ColorYellow = plain, plain, plain
+		ColorBlue, ColorCyan, ColorWhite = plain, plain, plain
+	case config.ThemeLight:
+		ColorRed = lipgloss.NewStyle().Foreground(lipgloss.Color("#b00000"))
+		ColorGreen = lipgloss.NewStyle().Foreground(lipgloss.Color("#007a08"))
+		ColorYellow = lipgloss.NewStyle().Foreground(lipgloss.Color("#8a6d00"))
+		ColorBlue = lipgloss.NewStyle().Foreground(lipgloss.Color("#00008b"))
+		ColorCyan = lipgloss.NewStyle().Foreground(lipgloss.Color("#006b6b"))
+		ColorWhite = lipgloss.NewStyle().Foreground(lipgloss.Color("#000000"))
+	}
+}
+
+// formatMoney formats an amount with thousands separators of the configured locale and the currency sign.
+func (app *App) formatMoney(amount float64) string {
+	thousands, decimal := " ", ","
+	if app.cfg.Locale == "en" {
+		thousands, decimal = ",", "."
+	}
+
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	cents := int64(math.Round(amount * 100))
+	whole := fmt.Sprintf("%d", cents/100)
+
+	var grouped strings.Builder
+	for i, r := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			grouped.WriteString(thousands)
+		}
+		grouped.WriteRune(r)
+	}
+
+	result := fmt.Sprintf("%s%s%s%02d", sign, grouped.String(), decimal, cents%100)
+	if app.cfg.Currency != "" {
+		result += " " + app.cfg.Currency
+	}
+	return result
+}
+
+// migrateLegacyData copies ./internal/data into an empty data directory once, so
+// users upgrading from the working-directory layout keep their ledgers.
+func migrateLegacyData(dataDir string) error {
+	legacy, err := filepath.Abs(legacyDataDir)
+	if err != nil {
+		return nil
+	}
+	target, err := filepath.Abs(dataDir)
+	if err != nil || legacy == target {
+		return nil
+	}
+
+	if _, err := os.Stat(filepath.Join(legacy, "transactions.json")); err != nil {
+		return nil
+	}
+	if entries, err := os.ReadDir(target); err == nil && len(entries) > 0 {
+		return nil
+	}
+
+	err = filepath.WalkDir(legacy, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(legacy, path)
+		if err != nil {
+			return err
+		}
+		dst := filepath.Join(target, rel)
+		if d.IsDir() {
+			return os.MkdirAll(dst, 0755)
+		}
+		return copyFile(path, dst)
+	})
+	if err != nil {
+		return fmt.Errorf("ошибка переноса данных из %s: %w", legacy, err)
+	}
+
+	fmt.Println(ColorYellow.Render(fmt.Sprintf("Данные перенесены из %s в %s", legacy, target)))
+	return nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+func (app *App) cmdConfig(args []string) error {
+	action := "show"
+	if len(args) > 0 {
+		action = args[0]
+	}
+
+	switch action {
+	case "path":
+		fmt.Println(app.cfgPath)
+		return nil
+	case "show":
+		fmt.Printf("Файл настроек: %s\n\n", app.cfgPath)
+		fmt.Printf("data_dir            = %s\n", app.cfg.DataDir)
+		fmt.Printf("currency            = %s\n", app.cfg.Currency)
+		fmt.Printf("locale              = %s\n", app.cfg.Locale)
+		fmt.Printf("theme               = %s\n", app.cfg.Theme)
+		fmt.Printf("date_format         = %s\n", app.cfg.DateFormat)
+		fmt.Printf("storage_backend     = %s\n", app.cfg.StorageBackend)
+		fmt.Printf("history_depth       = %d\n", app.cfg.HistoryDepth)
+		fmt.Printf("auto_backup         = %t\n", app.cfg.AutoBackup)
+		fmt.Printf("backup_keep_daily   = %d\n", app.cfg.BackupDaily)
+		fmt.Printf("backup_keep_monthly = %d\n", app.cfg.BackupMonthly)
+		fmt.Printf("envelope_budget     = %t\n", app.cfg.EnvelopeBudget)
+		return nil
+	case "init":
+		if _, err := os.Stat(app.cfgPath); err == nil {
+			return fmt.Errorf("файл настроек уже существует: %s", app.cfgPath)
+		}
+		if err := config.Save(app.cfgPath, app.cfg); err != nil {
+			return err
+		}
+		fmt.Println(ColorGreen.Render("Создан файл настроек " + app.cfgPath))
+		return nil
+	case "set":
+		if len(args) != 3 {
+			return fmt.Errorf("использование: config set КЛЮЧ ЗНАЧЕНИЕ")
+		}
+
+		// only the file is changed here, environment and flag overrides are not persisted
+		cfg, err := config.ReadFile(app.cfgPath)
+		if err != nil {
+			return err
+		}
+		if err := cfg.Set(args[1], args[2]); err != nil {
+			return err
+		}
+		if err := cfg.Validate(); err != nil {
+			return err
+		}
+		if cfg.StorageBackend == config.BackendJSON {
+			for _, p := range app.profiles.List() {
+				if app.keptInEvents(p) {
+					return fmt.Errorf("профиль %s ведется в журнале событий, вернуться к storage_backend = json нельзя", p.Name)
+				}
+			}
+		}
+		if err := config.Save(app.cfgPath, cfg); err != nil {
+			return err
+		}
+		fmt.Println(ColorGreen.Render(fmt.Sprintf("%s = %s", args[1], args[2])))
+		return nil
+	default:
+		return fmt.Errorf("использование: config path | show | init | set КЛЮЧ ЗНАЧЕНИЕ")
+	}
+}
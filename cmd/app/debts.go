@@ -0,0 +1,427 @@
+package main
+
+import (
+	"encoding/json"
+	"fintrack/internal/models"
+	"fintrack/internal/services"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const debtsFileName = "debts.json"
+
+func (app *App) debtsFile() string {
+	return filepath.Join(app.profiles.Dir(app.profile), debtsFileName)
+}
+
+func (app *App) cmdDebt(args []string) error {
+	if len(args) == 0 {
+		return app.printDebts()
+	}
+
+	switch args[0] {
+	case "list":
+		fs := flag.NewFlagSet("debt list", flag.ContinueOnError)
+		asJSON := fs.Bool("json", false, "вывести долги в формате JSON")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if *asJSON {
+			statuses, err := app.debtService.AllStatus()
+			if err != nil {
+				return err
+			}
+			return printJSON(statuses)
+		}
+		return app.printDebts()
+
+	case "add":
+		fs := flag.NewFlagSet("debt add", flag.ContinueOnError)
+		direction := fs.String("direction", string(models.DebtBorrowed), "lent — дали в долг, borrowed — взяли в долг")
+		principal := fs.Float64("principal", 0, "сумма долга")
+		rate := fs.Float64("rate", 0, "годовая ставка, %")
+		term := fs.Int("term", 0, "срок в месяцах (0 — без графика платежей)")
+		start := fs.String("start", "", "дата выдачи ДД.ММ.ГГГГ (по умолчанию сегодня)")
+		counterparty := fs.String("counterparty", "", "кто кому должен: банк, друг и т.п.")
+		category := fs.String("category", "", "категория транзакций-платежей")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() == 0 && *counterparty == "" {
+			return fmt.Errorf("использование: debt add -direction lent|borrowed -principal СУММА -category КАТЕГОРИЯ [-rate %%] [-term МЕСЯЦЕВ] [-start ДД.ММ.ГГГГ] [-counterparty КТО] НАЗВАНИЕ")
+		}
+		startDate, err := parseOptionalDay(*start)
+		if err != nil {
+			return err
+		}
+		debt, err := app.debtService.CreateDebt(models.Debt{
+			Name:         strings.Join(fs.Args(), " "),
+			Counterparty: *counterparty,
+			Direction:    models.DebtDirection(*direction),
+			Principal:    *principal,
+			Rate:         *rate,
+			TermMonths:   *term,
+			Start:        startDate,
+			Category:     *category,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Println(ColorGreen.Render(fmt.Sprintf("Долг %s добавлен.", debt.Name)))
+		return nil
+
+	case "pay":
+		fs := flag.NewFlagSet("debt pay", flag.ContinueOnError)
+		date := fs.String("date", "", "дата платежа ДД.ММ.ГГГГ (по умолчанию сегодня)")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() != 2 {
+			return fmt.Errorf("использование: debt pay [-date ДД.ММ.ГГГГ] ДОЛГ СУММА")
+		}
+		amount, err := strconv.ParseFloat(fs.Arg(1), 64)
+		if err != nil {
+			return fmt.Errorf("некорректная сумма %q", fs.Arg(1))
+		}
+		day, err := parseOptionalDay(*date)
+		if err != nil {
+			return err
+		}
+		if day.IsZero() {
+			day = time.Now()
+		}
+		return app.payDebt(fs.Arg(0), amount, day)
+
+	case "link", "unlink":
+		if len(args) != 3 {
+			return fmt.Errorf("использование: debt %s ДОЛГ ID_ТРАНЗАКЦИИ", args[0])
+		}
+		if args[0] == "link" {
+			if err := app.debtService.LinkPayment(args[1], args[2]); err != nil {
+				return err
+			}
+			fmt.Println(ColorGreen.Render(fmt.Sprintf("Транзакция %s привязана к долгу %s.", args[2], args[1])))
+			return nil
+		}
+		if err := app.debtService.UnlinkPayment(args[1], args[2]); err != nil {
+			return err
+		}
+		fmt.Println(ColorGreen.Render(fmt.Sprintf("Транзакция %s отвязана от долга %s.", args[2], args[1])))
+		return nil
+
+	case "schedule":
+		fs := flag.NewFlagSet("debt schedule", flag.ContinueOnError)
+		asJSON := fs.Bool("json", false, "вывести график в формате JSON")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() != 1 {
+			return fmt.Errorf("использование: debt schedule [-json] ДОЛГ")
+		}
+		debt, err := app.debtService.FindDebt(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		if *asJSON {
+			return printJSON(services.AmortizationSchedule(debt))
+		}
+		return app.printSchedule(debt)
+
+	case "show":
+		if len(args) != 2 {
+			return fmt.Errorf("использование: debt show ДОЛГ")
+		}
+		return app.printDebtDetails(args[1])
+
+	case "delete":
+		if len(args) != 2 {
+			return fmt.Errorf("использование: debt delete ДОЛГ")
+		}
+		if err := app.debtService.DeleteDebt(args[1]); err != nil {
+			return err
+		}
+		fmt.Println(ColorGreen.Render(fmt.Sprintf("Долг %s удален, платежи сохранены.", args[1])))
+		return nil
+
+	default:
+		return fmt.Errorf("неизвестное действие: %s (доступны list, add, pay, link, unlink, schedule, show, delete)", args[0])
+	}
+}
+
+func (app *App) manageDebts() error {
+	clearScreen()
+	fmt.Println(ColorBlue.Render("=================== Долги и кредиты ==================="))
+	if err := app.printDebts(); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	fmt.Println(ColorWhite.Render("1. Новый долг"))
+	fmt.Println(ColorWhite.Render("2. Внести платеж"))
+	fmt.Println(ColorWhite.Render("3. Платежи по долгу"))
+	fmt.Println(ColorWhite.Render("4. График платежей"))
+	fmt.Println(ColorWhite.Render("5. Удалить долг"))
+
+	choice, err := app.readLine("\nВыберите действие (Enter — назад): ")
+	if err != nil {
+		return err
+	}
+
+	switch choice {
+	case "":
+		return nil
+	case "1":
+		return app.addDebt()
+	case "2":
+		ref, err := app.readLine("Название долга: ")
+		if err != nil {
+			return err
+		}
+		s, err := app.readLine("Сумма платежа: ")
+		if err != nil {
+			return err
+		}
+		amount, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("ошибка при вводе суммы")
+		}
+		return app.payDebt(ref, amount, time.Now())
+	case "3", "4", "5":
+		ref, err := app.readLine("Название долга: ")
+		if err != nil {
+			return err
+		}
+		switch choice {
+		case "3":
+			return app.printDebtDetails(ref)
+		case "4":
+			debt, err := app.debtService.FindDebt(ref)
+			if err != nil {
+				return err
+			}
+			return app.printSchedule(debt)
+		}
+		if err := app.debtService.DeleteDebt(ref); err != nil {
+			return err
+		}
+		fmt.Println(ColorGreen.Render(fmt.Sprintf("Долг %s удален, платежи сохранены.", ref)))
+		return nil
+	default:
+		return fmt.Errorf("неверный выбор. Выберите от 1 до 5")
+	}
+}
+
+func (app *App) addDebt() error {
+	s, err := app.readLine("Направление (1 — дал в долг, 2 — взял в долг/кредит): ")
+	if err != nil {
+		return err
+	}
+	var direction models.DebtDirection
+	switch s {
+	case "1":
+		direction = models.DebtLent
+	case "2":
+		direction = models.DebtBorrowed
+	default:
+		return fmt.Errorf("неверный выбор направления. Выберите 1 или 2")
+	}
+
+	name, err := app.readLine("Название (например, Ипотека): ")
+	if err != nil {
+		return err
+	}
+	counterparty, err := app.readLine("Контрагент: ")
+	if err != nil {
+		return err
+	}
+	s, err = app.readLine("Сумма долга: ")
+	if err != nil {
+		return err
+	}
+	principal, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("ошибка при вводе суммы")
+	}
+	s, err = app.readLine("Годовая ставка, % (Enter — 0): ")
+	if err != nil {
+		return err
+	}
+	rate, err := parseOptionalFloat(s)
+	if err != nil {
+		return err
+	}
+	s, err = app.readLine("Срок в месяцах (Enter — без графика): ")
+	if err != nil {
+		return err
+	}
+	term := 0
+	if s != "" {
+		if term, err = strconv.Atoi(s); err != nil {
+			return fmt.Errorf("срок должен быть целым числом месяцев")
+		}
+	}
+	s, err = app.readLine("Дата выдачи ДД.ММ.ГГГГ (Enter — сегодня): ")
+	if err != nil {
+		return err
+	}
+	start, err := parseOptionalDay(s)
+	if err != nil {
+		return err
+	}
+
+	kind := "расходов"
+	if direction == models.DebtLent {
+		kind = "доходов"
+	}
+	category, err := app.readLine(fmt.Sprintf("Категория %s для платежей: ", kind))
+	if err != nil {
+		return err
+	}
+
+	debt, err := app.debtService.CreateDebt(models.Debt{
+		Name:         name,
+		Counterparty: counterparty,
+		Direction:    direction,
+		Principal:    principal,
+		Rate:         rate,
+		TermMonths:   term,
+		Start:        start,
+		Category:     category,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Println(ColorGreen.Render(fmt.Sprintf("Долг %s добавлен.", debt.Name)))
+	return nil
+}
+
+func (app *App) payDebt(ref string, amount float64, date time.Time) error {
+	transaction, err := app.debtService.RecordPayment(ref, amount, date)
+	if err != nil {
+		return err
+	}
+	status, err := app.debtService.Status(ref)
+	if err != nil {
+		return err
+	}
+	fmt.Println(ColorGreen.Render(fmt.Sprintf("Платеж %s записан (%s). Остаток долга: %s.", app.formatMoney(amount), transaction.ID, app.formatMoney(status.Balance))))
+	return nil
+}
+
+func (app *App) printDebts() error {
+	statuses, err := app.debtService.AllStatus()
+	if err != nil {
+		return err
+	}
+	if len(statuses) == 0 {
+		fmt.Println(ColorYellow.Render("Долгов нет."))
+		return nil
+	}
+
+	var owedToUs, weOwe float64
+	for _, s := range statuses {
+		app.printDebtSummary(s)
+		if s.Debt.Direction == models.DebtLent {
+			owedToUs += s.Balance
+		} else {
+			weOwe += s.Balance
+		}
+	}
+	fmt.Printf("\nНам должны: %s | Мы должны: %s\n", app.formatMoney(owedToUs), app.formatMoney(weOwe))
+	return nil
+}
+
+func (app *App) printDebtSummary(s models.DebtStatus) {
+	d := s.Debt
+	header := d.Name + " — мы должны"
+	if d.Direction == models.DebtLent {
+		header = d.Name + " — должны нам"
+	}
+	if d.Counterparty != "" && !strings.EqualFold(d.Counterparty, d.Name) {
+		header += " (" + d.Counterparty + ")"
+	}
+	if s.PaidOff {
+		fmt.Println(ColorGreen.Render(header + " (погашен)"))
+	} else {
+		fmt.Println(ColorCyan.Render(header))
+	}
+
+	terms := fmt.Sprintf("  Сумма %s с %s", app.formatMoney(d.Principal), d.Start.Format(dayLayout))
+	if d.Rate > 0 {
+		terms += fmt.Sprintf(", %.2f%% годовых", d.Rate)
+	}
+	if d.TermMonths > 0 {
+		terms += fmt.Sprintf(", %d мес., платеж %s", d.TermMonths, app.formatMoney(s.MonthlyPayment))
+	}
+	fmt.Println(terms)
+	fmt.Printf("  Остаток: %s | Погашено: %s | Уплачено процентов: %s\n", app.formatMoney(s.Balance), app.formatMoney(s.PrincipalPaid), app.formatMoney(s.InterestPaid))
+
+	if !s.NextDue.IsZero() {
+		line := "  Следующий платеж: " + s.NextDue.Format(dayLayout)
+		if s.NextDue.Before(time.Now()) {
+			fmt.Println(ColorRed.Render(line + " (просрочен)"))
+		} else {
+			fmt.Println(line)
+		}
+	}
+}
+
+func (app *App) printDebtDetails(ref string) error {
+	s, err := app.debtService.Status(ref)
+	if err != nil {
+		return err
+	}
+	app.printDebtSummary(s)
+
+	if len(s.Payments) == 0 {
+		fmt.Println(ColorYellow.Render("\nПлатежей пока нет."))
+		return nil
+	}
+
+	fmt.Println(ColorCyan.Render("\nПлатежи:"))
+	fmt.Printf("  %-10s %12s %12s %12s %14s  %s\n", "Дата", "Сумма", "Проценты", "Долг", "Остаток", "Транзакция")
+	for _, p := range s.Payments {
+		fmt.Printf("  %-10s %12.2f %12.2f %12.2f %14.2f  %s\n", p.Date.Local().Format(dayLayout), p.Amount, p.Interest, p.Principal, p.Balance, p.TransactionID)
+	}
+	return nil
+}
+
+func (app *App) printSchedule(debt models.Debt) error {
+	schedule := services.AmortizationSchedule(debt)
+	if len(schedule) == 0 {
+		return fmt.Errorf("у долга %s нет срока, график платежей не строится", debt.Name)
+	}
+
+	fmt.Println(ColorCyan.Render(fmt.Sprintf("График платежей: %s", debt.Name)))
+	fmt.Printf("  %4s  %-10s %12s %12s %12s %14s\n", "№", "Дата", "Платеж", "Проценты", "Долг", "Остаток")
+
+	var interest float64
+	for _, row := range schedule {
+		interest += row.Interest
+		fmt.Printf("  %4d  %-10s %12.2f %12.2f %12.2f %14.2f\n", row.N, row.Date.Format(dayLayout), row.Payment, row.Interest, row.Principal, row.Balance)
+	}
+	fmt.Printf("\nПереплата по процентам: %s | Всего выплат: %s\n", app.formatMoney(interest), app.formatMoney(interest+debt.Principal))
+	return nil
+}
+
+func parseOptionalFloat(s string) (float64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("некорректное число %q", s)
+	}
+	return v, nil
+}
+
+func printJSON(v interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
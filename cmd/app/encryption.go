@@ -0,0 +1,202 @@
+package main
+
+import (
+	"errors"
+	"fintrack/internal/storage"
+	"fintrack/internal/vault"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const maxPassphraseAttempts = 3
+
+// unlockVault asks for the passphrase of an encrypted profile. FINTRACK_PASSPHRASE
+// can be used for non-interactive runs such as the server.
+func (app *App) unlockVault(profileName, dir string) (*vault.Cipher, error) {
+	if passphrase, ok := os.LookupEnv("FINTRACK_PASSPHRASE"); ok {
+		return vault.Open(dir, passphrase)
+	}
+
+	for attempt := 1; attempt <= maxPassphraseAttempts; attempt++ {
+		passphrase, err := app.readPassword(fmt.Sprintf("Парольная фраза профиля %s: ", profileName))
+		if err != nil {
+			return nil, err
+		}
+
+		cipher, err := vault.Open(dir, passphrase)
+		if err == nil {
+			return cipher, nil
+		}
+		if !errors.Is(err, vault.ErrWrongPassphrase) {
+			return nil, err
+		}
+		fmt.Println(ColorRed.Render("Неверная парольная фраза."))
+	}
+
+	return nil, fmt.Errorf("%w: профиль %s не открыт, данные не изменены", vault.ErrWrongPassphrase, profileName)
+}
+
+// profileFiles lists the data files of the current profile, attached files included.
+func (app *App) profileFiles() ([]string, error) {
+	transactionsFile, categoriesFile := app.profiles.Files(app.profile)
+	files := []string{
+		transactionsFile,
+		categoriesFile,
+		app.auditFile(),
+		app.historyFile(),
+		app.goalsFile(),
+		app.debtsFile(),
+		app.networthFile(),
+		app.recurringFile(),
+		app.envelopesFile(),
+		app.anomaliesFile(),
+		app.receiptsFile(),
+		app.payeesFile(),
+		filepath.Join(app.profiles.Dir(app.profile), storage.EventsFileName),
+	}
+	attached, err := app.attachments(storage.PlainCodec{}).Files()
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения вложений: %w", err)
+	}
+	return append(files, attached...), nil
+}
+
+func (app *App) readNewPassphrase() (string, error) {
+	passphrase, err := app.readPassword("Новая парольная фраза: ")
+	if err != nil {
+		return "", err
+	}
+	if passphrase == "" {
+		return "", vault.ErrEmptyPassphrase
+	}
+
+	confirm, err := app.readPassword("Повторите парольную фразу: ")
+	if err != nil {
+		return "", err
+	}
+	if confirm != passphrase {
+		return "", fmt.Errorf("парольные фразы не совпадают")
+	}
+	return passphrase, nil
+}
+
+// enableEncryption encrypts the current profile's files in place.
+func (app *App) enableEncryption() error {
+	dir := app.profiles.Dir(app.profile)
+	if vault.Exists(dir) {
+		return fmt.Errorf("данные профиля %s уже зашифрованы", app.profile.Name)
+	}
+
+	passphrase, err := app.readNewPassphrase()
+	if err != nil {
+		return err
+	}
+
+	files, err := app.profileFiles()
+	if err != nil {
+		return err
+	}
+	cipher, err := vault.Enable(dir, passphrase, files)
+	if err != nil {
+		return fmt.Errorf("ошибка шифрования: %w", err)
+	}
+
+	if err := app.openProfile(app.profile, cipher); err != nil {
+		return err
+	}
+	fmt.Println(ColorGreen.Render("Данные профиля зашифрованы. Без парольной фразы восстановить их невозможно."))
+	if list, err := app.backups.List(); err == nil && len(list) > 0 {
+		fmt.Println(ColorYellow.Render(fmt.Sprintf("Резервные копии в %s сделаны до шифрования и хранят данные открыто, удалите их при необходимости.", app.backups.Dir())))
+	}
+	return nil
+}
+
+func (app *App) changePassphrase() error {
+	dir := app.profiles.Dir(app.profile)
+	if !vault.Exists(dir) {
+		return fmt.Errorf("данные профиля %s не зашифрованы", app.profile.Name)
+	}
+
+	old, err := app.readPassword("Текущая парольная фраза: ")
+	if err != nil {
+		return err
+	}
+	passphrase, err := app.readNewPassphrase()
+	if err != nil {
+		return err
+	}
+
+	files, err := app.profileFiles()
+	if err != nil {
+		return err
+	}
+	cipher, err := vault.Rotate(dir, old, passphrase, files)
+	if err != nil {
+		return fmt.Errorf("ошибка смены парольной фразы: %w", err)
+	}
+
+	if err := app.openProfile(app.profile, cipher); err != nil {
+		return err
+	}
+	fmt.Println(ColorGreen.Render("Парольная фраза изменена, данные перешифрованы новым ключом."))
+	return nil
+}
+
+func (app *App) disableEncryption() error {
+	dir := app.profiles.Dir(app.profile)
+	if !vault.Exists(dir) {
+		return fmt.Errorf("данные профиля %s не зашифрованы", app.profile.Name)
+	}
+
+	passphrase, err := app.readPassword("Текущая парольная фраза: ")
+	if err != nil {
+		return err
+	}
+
+	files, err := app.profileFiles()
+	if err != nil {
+		return err
+	}
+	if err := vault.Disable(dir, passphrase, files); err != nil {
+		return fmt.Errorf("ошибка расшифровки: %w", err)
+	}
+
+	return app.useProfile(app.profile.Name)
+}
+
+func (app *App) manageEncryption() error {
+	encrypted := vault.Exists(app.profiles.Dir(app.profile))
+
+	if !encrypted {
+		fmt.Println(ColorYellow.Render("Данные профиля хранятся без шифрования."))
+		fmt.Print(ColorCyan.Render("Зашифровать их? (д/н): "))
+		if !app.scanner.Scan() {
+			return fmt.Errorf("ошибка чтения ответа")
+		}
+		if answer := strings.ToLower(strings.TrimSpace(app.scanner.Text())); answer != "д" && answer != "y" {
+			return nil
+		}
+		return app.enableEncryption()
+	}
+
+	fmt.Println(ColorGreen.Render("Данные профиля зашифрованы."))
+	fmt.Println(ColorWhite.Render("1. Сменить парольную фразу"))
+	fmt.Println(ColorWhite.Render("2. Отключить шифрование"))
+	fmt.Print(ColorCyan.Render("\nВыберите действие (Enter — назад): "))
+	if !app.scanner.Scan() {
+		return fmt.Errorf("ошибка чтения выбора")
+	}
+
+	switch strings.TrimSpace(app.scanner.Text()) {
+	case "":
+		return nil
+	case "1":
+		return app.changePassphrase()
+	case "2":
+		return app.disableEncryption()
+	default:
+		return fmt.Errorf("неверный выбор. Выберите 1 или 2")
+	}
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	envelopesFileName = "envelopes.json"
+	monthInputLayout  = "01.2006"
+)
+
+func (app *App) envelopesFile() string {
+	return filepath.Join(app.profiles.Dir(app.profile), envelopesFileName)
+}
+
+func (app *App) requireEnvelopes() error {
+	if !app.cfg.EnvelopeBudget {
+		return fmt.Errorf("бюджет по конвертам выключен, включите его: config set envelope_budget true")
+	}
+	return nil
+}
+
+func (app *App) cmdEnvelope(args []string) error {
+	if err := app.requireEnvelopes(); err != nil {
+		return err
+	}
+
+	action := ""
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		action, args = args[0], args[1:]
+	}
+
+	fs := flag.NewFlagSet("envelope", flag.ContinueOnError)
+	monthFlag := fs.String("month", "", "месяц ММ.ГГГГ (по умолчанию текущий)")
+	asJSON := fs.Bool("json", false, "вывести конверты в формате JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	month, err := parseMonth(*monthFlag)
+	if err != nil {
+		return err
+	}
+
+	switch action {
+	case "", "show":
+		if *asJSON {
+			m, err := app.envelopeService.Month(month)
+			if err != nil {
+				return err
+			}
+			return printJSON(m)
+		}
+		return app.printEnvelopes(month)
+	case "assign":
+		if fs.NArg() != 2 {
+			return fmt.Errorf("использование: envelope assign [-month ММ.ГГГГ] КАТЕГОРИЯ СУММА")
+		}
+		amount, err := strconv.ParseFloat(fs.Arg(1), 64)
+		if err != nil {
+			return fmt.Errorf("некорректная сумма %q", fs.Arg(1))
+		}
+		return app.assignEnvelope(month, fs.Arg(0), amount)
+	case "move":
+		if fs.NArg() != 3 {
+			return fmt.Errorf("использование: envelope move [-month ММ.ГГГГ] ИЗ В СУММА")
+		}
+		amount, err := strconv.ParseFloat(fs.Arg(2), 64)
+		if err != nil {
+			return fmt.Errorf("некорректная сумма %q", fs.Arg(2))
+		}
+		return app.moveEnvelope(month, fs.Arg(0), fs.Arg(1), amount)
+	default:
+		return fmt.Errorf("неизвестное действие: %s (доступны show, assign, move)", action)
+	}
+}
+
+func (app *App) manageEnvelopes() error {
+	if err := app.requireEnvelopes(); err != nil {
+		return err
+	}
+
+	clearScreen()
+	fmt.Println(ColorBlue.Render("======================= Конверты ======================="))
+	month := time.Now()
+	if err := app.printEnvelopes(month); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	fmt.Println(ColorWhite.Render("1. Распределить в конверт"))
+	fmt.Println(ColorWhite.Render("2. Переложить между конвертами"))
+
+	choice, err := app.readLine("\nВыберите действие (Enter — назад): ")
+	if err != nil {
+		return err
+	}
+
+	switch choice {
+	case "":
+		return nil
+	case "1":
+		category, err := app.readLine("Категория: ")
+		if err != nil {
+			return err
+		}
+		s, err := app.readLine("Сумма (отрицательная — вернуть в нераспределенные): ")
+		if err != nil {
+			return err
+		}
+		amount, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("ошибка при вводе суммы")
+		}
+		return app.assignEnvelope(month, category, amount)
+	case "2":
+		from, err := app.readLine("Из конверта: ")
+		if err != nil {
+			return err
+		}
+		to, err := app.readLine("В конверт: ")
+		if err != nil {
+			return err
+		}
+		s, err := app.readLine("Сумма: ")
+		if err != nil {
+			return err
+		}
+		amount, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("ошибка при вводе суммы")
+		}
+		return app.moveEnvelope(month, from, to, amount)
+	default:
+		return fmt.Errorf("неверный выбор. Выберите 1 или 2")
+	}
+}
+
+func (app *App) assignEnvelope(month time.Time, category string, amount float64) error {
+	allocation, err := app.envelopeService.Assign(month, category, amount, "")
+	if err != nil {
+		return err
+	}
+	m, err := app.envelopeService.Month(month)
+	if err != nil {
+		return err
+	}
+	fmt.Println(ColorGreen.Render(fmt.Sprintf("В конверт %s распределено %s. Осталось распределить: %s.",
+		allocation.Category, app.formatMoney(amount), app.formatMoney(m.ToBeAssigned))))
+	return nil
+}
+
+func (app *App) moveEnvelope(month time.Time, from, to string, amount float64) error {
+	if err := app.envelopeService.Move(month, from, to, amount); err != nil {
+		return err
+	}
+	fmt.Println(ColorGreen.Render(fmt.Sprintf("Переложено %s из %s в %s.", app.formatMoney(amount), from, to)))
+	return nil
+}
+
+func (app *App) printEnvelopes(month time.Time) error {
+	m, err := app.envelopeService.Month(month)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(ColorCyan.Render(fmt.Sprintf("Конверты за %s, доход за месяц: %s", month.Format(monthInputLayout), app.formatMoney(m.Income))))
+	fmt.Printf("  %-20s %12s %12s %12s %12s\n", "Конверт", "Остаток", "Выделено", "Потрачено", "Доступно")
+	for _, e := range m.Envelopes {
+		line := fmt.Sprintf("  %-20s %12.2f %12.2f %12.2f %12.2f", e.Category, e.Rollover, e.Assigned, e.Spent, e.Available)
+		if e.Available < 0 {
+			fmt.Println(ColorRed.Render(line + "  перерасход"))
+		} else {
+			fmt.Println(line)
+		}
+	}
+	fmt.Printf("  %-20s %12s %12.2f %12.2f %12.2f\n", "Итого", "", m.Assigned, m.Spent, m.Available)
+
+	tba := "Осталось распределить: " + app.formatMoney(m.ToBeAssigned)
+	switch {
+	case m.ToBeAssigned < 0:
+		fmt.Println(ColorRed.Render("\n" + tba + " — распределено больше, чем получено"))
+	case m.ToBeAssigned > 0:
+		fmt.Println(ColorYellow.Render("\n" + tba))
+	default:
+		fmt.Println(ColorGreen.Render("\n" + tba + " — все деньги распределены"))
+	}
+	return nil
+}
+
+// parseMonth parses ММ.ГГГГ; an empty string gives the current month.
+func parseMonth(s string) (time.Time, error) {
+	if strings.TrimSpace(s) == "" {
+		return time.Now(), nil
+	}
+	t, err := time.ParseInLocation(monthInputLayout, strings.TrimSpace(s), time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("некорректный месяц %q, используйте формат ММ.ГГГГ", s)
+	}
+	return t, nil
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"fintrack/internal/models"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+func (app *App) cmdCategory(args []string) error {
+	if len(args) == 0 {
+		return app.showCategories()
+	}
+
+	switch args[0] {
+	case "list":
+		return app.showCategories()
+	case "rename":
+		if len(args) != 3 {
+			return fmt.Errorf("использование: category rename СТАРОЕ НОВОЕ")
+		}
+		category, err := app.categoryService.RenameCategory(args[1], args[2])
+		if err != nil {
+			return err
+		}
+		fmt.Println(ColorGreen.Render(fmt.Sprintf("Категория %s переименована в %s.", args[1], category.Name)))
+		return nil
+	default:
+		return fmt.Errorf("неизвестное действие: %s (доступны list, rename)", args[0])
+	}
+}
+
+// cmdAsOf prints the ledger as it was recorded by the end of the given day.
+func (app *App) cmdAsOf(args []string) error {
+	fs := flag.NewFlagSet("asof", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "вывести транзакции в формате JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("использование: asof [-json] ДД.ММ.ГГГГ")
+	}
+
+	day, err := time.ParseInLocation(dayLayout, fs.Arg(0), time.Local)
+	if err != nil {
+		return fmt.Errorf("некорректная дата %q, используйте формат ДД.ММ.ГГГГ", fs.Arg(0))
+	}
+	at := day.AddDate(0, 0, 1).Add(-time.Nanosecond)
+
+	transactions, categories, err := app.transactionService.LedgerAt(at)
+	if err != nil {
+		return err
+	}
+	sort.Slice(transactions, func(i, j int) bool {
+		return transactions[i].Date.Before(transactions[j].Date)
+	})
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(transactions)
+	}
+
+	fmt.Println(ColorCyan.Render(fmt.Sprintf("Состояние учета на конец дня %s: транзакций %d, категорий %d", day.Format(dayLayout), len(transactions), len(categories))))
+
+	var income, expense float64
+	for _, t := range transactions {
+		sign := "+"
+		if t.Type == models.TransactionExpense {
+			sign = "-"
+			expense += t.Amount
+		} else {
+			income += t.Amount
+		}
+		fmt.Printf("  %s  %s%-12.2f %-15s %s\n", t.Date.Local().Format(app.cfg.DateLayout()), sign, t.Amount, t.Category, t.Description)
+	}
+
+	fmt.Printf("\nДоход: %s | Расход: %s | Баланс: %s\n", app.formatMoney(income), app.formatMoney(expense), app.formatMoney(income-expense))
+	return nil
+}
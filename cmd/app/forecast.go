@@ -0,0 +1,318 @@
+package main
+
+import (
+	"fintrack/internal/charts"
+	"fintrack/internal/models"
+	"fintrack/internal/services"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	recurringFileName       = "recurring.json"
+	defaultForecastDays     = 30
+	defaultForecastLookback = 90
+)
+
+func (app *App) recurringFile() string {
+	return filepath.Join(app.profiles.Dir(app.profile), recurringFileName)
+}
+
+func (app *App) cmdForecast(args []string) error {
+	fs := flag.NewFlagSet("forecast", flag.ContinueOnError)
+	days := fs.Int("days", defaultForecastDays, "на сколько дней вперед")
+	lookback := fs.Int("lookback", defaultForecastLookback, "за сколько последних дней усреднять обычные траты")
+	all := fs.Bool("all", false, "показать все дни, а не только дни с регулярными платежами и минусом")
+	asJSON := fs.Bool("json", false, "вывести прогноз в формате JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *asJSON {
+		forecast, err := app.forecastService.Forecast(time.Now(), *days, *lookback)
+		if err != nil {
+			return err
+		}
+		return printJSON(forecast)
+	}
+	return app.printForecast(*days, *lookback, *all, charts.DetectOptions())
+}
+
+func (app *App) cmdRecurring(args []string) error {
+	if len(args) == 0 {
+		return app.printRecurring()
+	}
+
+	switch args[0] {
+	case "list":
+		return app.printRecurring()
+	case "add":
+		fs := flag.NewFlagSet("recurring add", flag.ContinueOnError)
+		kind := fs.String("type", string(models.TransactionExpense), "income или expense")
+		category := fs.String("category", "", "категория")
+		every := fs.String("every", string(models.FrequencyMonthly), "weekly, monthly или yearly")
+		start := fs.String("start", "", "дата первого платежа ДД.ММ.ГГГГ (по умолчанию сегодня)")
+		until := fs.String("until", "", "дата последнего платежа ДД.ММ.ГГГГ")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() < 2 {
+			return fmt.Errorf("использование: recurring add -category КАТЕГОРИЯ [-type income|expense] [-every weekly|monthly|yearly] [-start ДД.ММ.ГГГГ] [-until ДД.ММ.ГГГГ] СУММА ОПИСАНИЕ")
+		}
+		amount, err := strconv.ParseFloat(fs.Arg(0), 64)
+		if err != nil {
+			return fmt.Errorf("некорректная сумма %q", fs.Arg(0))
+		}
+		startDate, err := parseOptionalDay(*start)
+		if err != nil {
+			return err
+		}
+		untilDate, err := parseOptionalDay(*until)
+		if err != nil {
+			return err
+		}
+		item, err := app.recurringService.AddItem(models.RecurringItem{
+			Description: strings.Join(fs.Args()[1:], " "),
+			Amount:      amount,
+			Type:        models.TransactionType(*kind),
+			Category:    *category,
+			Frequency:   models.Frequency(*every),
+			Start:       startDate,
+			Until:       untilDate,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Println(ColorGreen.Render(fmt.Sprintf("Регулярный платеж «%s» добавлен.", item.Description)))
+		return nil
+	case "delete":
+		if len(args) < 2 {
+			return fmt.Errorf("использование: recurring delete ОПИСАНИЕ|ID")
+		}
+		ref := strings.Join(args[1:], " ")
+		if err := app.recurringService.DeleteItem(ref); err != nil {
+			return err
+		}
+		fmt.Println(ColorGreen.Render(fmt.Sprintf("Регулярный платеж «%s» удален.", ref)))
+		return nil
+	default:
+		return fmt.Errorf("неизвестное действие: %s (доступны list, add, delete)", args[0])
+	}
+}
+
+func (app *App) showForecast() error {
+	clearScreen()
+	fmt.Println(ColorBlue.Render("================== Прогноз денежного потока =================="))
+
+	s, err := app.readLine(fmt.Sprintf("На сколько дней (по умолчанию %d): ", defaultForecastDays))
+	if err != nil {
+		return err
+	}
+	days := defaultForecastDays
+	if s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 {
+			return fmt.Errorf("количество дней должно быть положительным числом")
+		}
+		days = n
+	}
+	if err := app.printForecast(days, defaultForecastLookback, false, charts.DetectOptions()); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	if err := app.printRecurring(); err != nil {
+		return err
+	}
+	fmt.Println()
+	fmt.Println(ColorWhite.Render("1. Добавить регулярный платеж"))
+	fmt.Println(ColorWhite.Render("2. Удалить регулярный платеж"))
+
+	choice, err := app.readLine("\nВыберите действие (Enter — назад): ")
+	if err != nil {
+		return err
+	}
+	switch choice {
+	case "":
+		return nil
+	case "1":
+		return app.addRecurring()
+	case "2":
+		ref, err := app.readLine("Описание платежа: ")
+		if err != nil {
+			return err
+		}
+		if err := app.recurringService.DeleteItem(ref); err != nil {
+			return err
+		}
+		fmt.Println(ColorGreen.Render(fmt.Sprintf("Регулярный платеж «%s» удален.", ref)))
+		return nil
+	default:
+		return fmt.Errorf("неверный выбор. Выберите 1 или 2")
+	}
+}
+
+func (app *App) addRecurring() error {
+	description, err := app.readLine("Описание (например, Аренда): ")
+	if err != nil {
+		return err
+	}
+	s, err := app.readLine("Сумма: ")
+	if err != nil {
+		return err
+	}
+	amount, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("ошибка при вводе суммы")
+	}
+	s, err = app.readLine("Тип (1-доход 2-расход): ")
+	if err != nil {
+		return err
+	}
+	kind := models.TransactionExpense
+	switch s {
+	case "1":
+		kind = models.TransactionIncome
+	case "2":
+	default:
+		return fmt.Errorf("неверный выбор типа транзакции. Выберите 1 или 2")
+	}
+	category, err := app.readLine("Категория: ")
+	if err != nil {
+		return err
+	}
+	s, err = app.readLine("Периодичность (1 — еженедельно, 2 — ежемесячно, 3 — ежегодно): ")
+	if err != nil {
+		return err
+	}
+	frequency := map[string]models.Frequency{"1": models.FrequencyWeekly, "2": models.FrequencyMonthly, "3": models.FrequencyYearly}[s]
+	if frequency == "" {
+		return fmt.Errorf("неверный выбор периодичности. Выберите 1, 2 или 3")
+	}
+	s, err = app.readLine("Дата ближайшего платежа ДД.ММ.ГГГГ (Enter — сегодня): ")
+	if err != nil {
+		return err
+	}
+	start, err := parseOptionalDay(s)
+	if err != nil {
+		return err
+	}
+
+	item, err := app.recurringService.AddItem(models.RecurringItem{
+		Description: description,
+		Amount:      amount,
+		Type:        kind,
+		Category:    category,
+		Frequency:   frequency,
+		Start:       start,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Println(ColorGreen.Render(fmt.Sprintf("Регулярный платеж «%s» добавлен.", item.Description)))
+	return nil
+}
+
+func (app *App) printForecast(days, lookback int, all bool, opts charts.Options) error {
+	forecast, err := app.forecastService.Forecast(time.Now(), days, lookback)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(ColorCyan.Render(fmt.Sprintf("Прогноз на %d дн. от баланса %s", days, app.formatMoney(forecast.Balance))))
+
+	var daily float64
+	for _, r := range forecast.Discretionary {
+		daily += r.Daily
+	}
+	if len(forecast.Discretionary) > 0 {
+		parts := make([]string, 0, len(forecast.Discretionary))
+		for _, r := range forecast.Discretionary {
+			parts = append(parts, fmt.Sprintf("%s %.2f", r.Category, r.Daily))
+		}
+		fmt.Printf("Обычные траты за последние %d дн.: %s в день (%s)\n", forecast.LookbackDays, app.formatMoney(daily), strings.Join(parts, ", "))
+	}
+
+	balances := make([]float64, len(forecast.Days))
+	for i, d := range forecast.Days {
+		balances[i] = d.Balance
+	}
+	fmt.Println("Баланс по дням: " + charts.Sparkline(balances, opts))
+	fmt.Println()
+
+	for _, d := range forecast.Days {
+		var recurring []string
+		for _, e := range d.Entries {
+			if !e.Recurring {
+				continue
+			}
+			sign := "-"
+			if e.Type == models.TransactionIncome {
+				sign = "+"
+			}
+			recurring = append(recurring, fmt.Sprintf("%s %s%.2f", e.Description, sign, e.Amount))
+		}
+		if !all && len(recurring) == 0 && !d.Negative {
+			continue
+		}
+
+		line := fmt.Sprintf("  %s  %+12.2f  баланс %14.2f  %s", d.Date.Format(dayLayout), d.Income-d.Expense, d.Balance, strings.Join(recurring, ", "))
+		if d.Negative {
+			fmt.Println(ColorRed.Render(line))
+		} else {
+			fmt.Println(line)
+		}
+	}
+
+	fmt.Println()
+	fmt.Printf("Минимальный баланс: %s (%s)\n", app.formatMoney(forecast.Lowest.Balance), forecast.Lowest.Date.Format(dayLayout))
+	if forecast.NegativeDays > 0 {
+		fmt.Println(ColorRed.Render(fmt.Sprintf("Дней с отрицательным балансом: %d", forecast.NegativeDays)))
+	} else {
+		fmt.Println(ColorGreen.Render("Баланс остается положительным весь период."))
+	}
+	return nil
+}
+
+func (app *App) printRecurring() error {
+	items, err := app.recurringService.Items()
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		fmt.Println(ColorYellow.Render("Регулярных платежей нет."))
+		return nil
+	}
+
+	now := time.Now()
+	fmt.Println(ColorCyan.Render("Регулярные платежи:"))
+	for _, item := range items {
+		sign := "-"
+		if item.Type == models.TransactionIncome {
+			sign = "+"
+		}
+		next := "—"
+		if dates := services.Occurrences(item, now, now.AddDate(1, 0, 1)); len(dates) > 0 {
+			next = dates[0].Format(dayLayout)
+		}
+		fmt.Printf("  %-25s %s%-12.2f %-15s %-8s следующий: %s\n", item.Description, sign, item.Amount, item.Category, frequencyName(item.Frequency), next)
+	}
+	return nil
+}
+
+func frequencyName(f models.Frequency) string {
+	switch f {
+	case models.FrequencyWeekly:
+		return "неделя"
+	case models.FrequencyMonthly:
+		return "месяц"
+	case models.FrequencyYearly:
+		return "год"
+	default:
+		return string(f)
+	}
+}
@@ -0,0 +1,322 @@
+package main
+
+import (
+	"encoding/json"
+	"fintrack/internal/charts"
+	"fintrack/internal/models"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	goalsFileName  = "goals.json"
+	goalsBarWidth  = 30
+	goalsShowLimit = 10
+)
+
+func (app *App) goalsFile() string {
+	return filepath.Join(app.profiles.Dir(app.profile), goalsFileName)
+}
+
+func (app *App) cmdGoal(args []string) error {
+	if len(args) == 0 {
+		return app.printGoals(time.Now())
+	}
+
+	switch args[0] {
+	case "list":
+		fs := flag.NewFlagSet("goal list", flag.ContinueOnError)
+		asJSON := fs.Bool("json", false, "вывести цели в формате JSON")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if *asJSON {
+			progress, err := app.goalService.AllProgress(time.Now())
+			if err != nil {
+				return err
+			}
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(progress)
+		}
+		return app.printGoals(time.Now())
+
+	case "add":
+		fs := flag.NewFlagSet("goal add", flag.ContinueOnError)
+		target := fs.Float64("target", 0, "сумма, которую нужно накопить")
+		deadline := fs.String("deadline", "", "срок в формате ДД.ММ.ГГГГ")
+		category := fs.String("category", "", "категория, расходы в которой пополняют цель")
+		tag := fs.String("tag", "", "метка #тег в описании транзакций, пополняющих цель")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() == 0 {
+			return fmt.Errorf("использование: goal add -target СУММА [-deadline ДД.ММ.ГГГГ] [-category КАТЕГОРИЯ] [-tag ТЕГ] НАЗВАНИЕ")
+		}
+		due, err := parseOptionalDay(*deadline)
+		if err != nil {
+			return err
+		}
+		goal, err := app.goalService.CreateGoal(strings.Join(fs.Args(), " "), *target, due, *category, *tag)
+		if err != nil {
+			return err
+		}
+		fmt.Println(ColorGreen.Render(fmt.Sprintf("Цель %s создана.", goal.Name)))
+		return nil
+
+	case "allocate":
+		fs := flag.NewFlagSet("goal allocate", flag.ContinueOnError)
+		date := fs.String("date", "", "дата в формате ДД.ММ.ГГГГ (по умолчанию сегодня)")
+		note := fs.String("note", "", "комментарий")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() != 2 {
+			return fmt.Errorf("использование: goal allocate [-date ДД.ММ.ГГГГ] [-note ТЕКСТ] ЦЕЛЬ СУММА")
+		}
+		amount, err := strconv.ParseFloat(fs.Arg(1), 64)
+		if err != nil {
+			return fmt.Errorf("некорректная сумма %q", fs.Arg(1))
+		}
+		day, err := parseOptionalDay(*date)
+		if err != nil {
+			return err
+		}
+		if day.IsZero() {
+			day = time.Now()
+		}
+		return app.allocateToGoal(fs.Arg(0), amount, *note, day)
+
+	case "show":
+		if len(args) != 2 {
+			return fmt.Errorf("использование: goal show ЦЕЛЬ")
+		}
+		return app.printGoalDetails(args[1], time.Now())
+
+	case "delete":
+		if len(args) != 2 {
+			return fmt.Errorf("использование: goal delete ЦЕЛЬ")
+		}
+		if err := app.goalService.DeleteGoal(args[1]); err != nil {
+			return err
+		}
+		fmt.Println(ColorGreen.Render(fmt.Sprintf("Цель %s удалена.", args[1])))
+		return nil
+
+	default:
+		return fmt.Errorf("неизвестное действие: %s (доступны list, add, allocate, show, delete)", args[0])
+	}
+}
+
+func (app *App) manageGoals() error {
+	clearScreen()
+	fmt.Println(ColorBlue.Render("=================== Цели накоплений ==================="))
+	if err := app.printGoals(time.Now()); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	fmt.Println(ColorWhite.Render("1. Новая цель"))
+	fmt.Println(ColorWhite.Render("2. Отложить деньги на цель"))
+	fmt.Println(ColorWhite.Render("3. Подробности по цели"))
+	fmt.Println(ColorWhite.Render("4. Удалить цель"))
+
+	choice, err := app.readLine("\nВыберите действие (Enter — назад): ")
+	if err != nil {
+		return err
+	}
+
+	switch choice {
+	case "":
+		return nil
+	case "1":
+		return app.addGoal()
+	case "2":
+		ref, err := app.readLine("Название цели: ")
+		if err != nil {
+			return err
+		}
+		s, err := app.readLine("Сумма (отрицательная — забрать): ")
+		if err != nil {
+			return err
+		}
+		amount, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("ошибка при вводе суммы")
+		}
+		note, err := app.readLine("Комментарий (необязательно): ")
+		if err != nil {
+			return err
+		}
+		return app.allocateToGoal(ref, amount, note, time.Now())
+	case "3":
+		ref, err := app.readLine("Название цели: ")
+		if err != nil {
+			return err
+		}
+		return app.printGoalDetails(ref, time.Now())
+	case "4":
+		ref, err := app.readLine("Название цели: ")
+		if err != nil {
+			return err
+		}
+		if err := app.goalService.DeleteGoal(ref); err != nil {
+			return err
+		}
+		fmt.Println(ColorGreen.Render(fmt.Sprintf("Цель %s удалена.", ref)))
+		return nil
+	default:
+		return fmt.Errorf("неверный выбор. Выберите от 1 до 4")
+	}
+}
+
+func (app *App) addGoal() error {
+	name, err := app.readLine("Название цели: ")
+	if err != nil {
+		return err
+	}
+	s, err := app.readLine("Сколько нужно накопить: ")
+	if err != nil {
+		return err
+	}
+	target, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("ошибка при вводе суммы")
+	}
+	s, err = app.readLine("Срок ДД.ММ.ГГГГ (Enter — без срока): ")
+	if err != nil {
+		return err
+	}
+	deadline, err := parseOptionalDay(s)
+	if err != nil {
+		return err
+	}
+	category, err := app.readLine("Категория расходов, пополняющих цель (Enter — нет): ")
+	if err != nil {
+		return err
+	}
+	tag, err := app.readLine("Тег в описании, например #отпуск (Enter — нет): ")
+	if err != nil {
+		return err
+	}
+
+	goal, err := app.goalService.CreateGoal(name, target, deadline, category, tag)
+	if err != nil {
+		return err
+	}
+	fmt.Println(ColorGreen.Render(fmt.Sprintf("Цель %s создана.", goal.Name)))
+	return nil
+}
+
+func (app *App) allocateToGoal(ref string, amount float64, note string, date time.Time) error {
+	goal, err := app.goalService.Allocate(ref, amount, note, date)
+	if err != nil {
+		return err
+	}
+	if amount > 0 {
+		fmt.Println(ColorGreen.Render(fmt.Sprintf("На цель %s отложено %s.", goal.Name, app.formatMoney(amount))))
+	} else {
+		fmt.Println(ColorGreen.Render(fmt.Sprintf("С цели %s снято %s.", goal.Name, app.formatMoney(-amount))))
+	}
+	return nil
+}
+
+func (app *App) printGoals(now time.Time) error {
+	progress, err := app.goalService.AllProgress(now)
+	if err != nil {
+		return err
+	}
+	if len(progress) == 0 {
+		fmt.Println(ColorYellow.Render("Целей пока нет."))
+		return nil
+	}
+
+	opts := charts.DetectOptions()
+	for i, p := range progress {
+		if i > 0 {
+			fmt.Println()
+		}
+		app.printGoalSummary(p, opts)
+	}
+	return nil
+}
+
+func (app *App) printGoalSummary(p models.GoalProgress, opts charts.Options) {
+	layout := dayLayout
+
+	title := p.Goal.Name
+	var links []string
+	if p.Goal.Category != "" {
+		links = append(links, "категория "+p.Goal.Category)
+	}
+	if p.Goal.Tag != "" {
+		links = append(links, "#"+p.Goal.Tag)
+	}
+	if len(links) > 0 {
+		title += " (" + strings.Join(links, ", ") + ")"
+	}
+	fmt.Println(ColorCyan.Render(title))
+	fmt.Printf("  %s  %s из %s\n", charts.ProgressBar(p.Fraction, goalsBarWidth, opts), app.formatMoney(p.Saved), app.formatMoney(p.Goal.Target))
+
+	if p.Remaining == 0 {
+		fmt.Println(ColorGreen.Render("  Цель достигнута!"))
+		return
+	}
+
+	fmt.Printf("  Осталось: %s, в среднем откладывается %s в месяц\n", app.formatMoney(p.Remaining), app.formatMoney(p.MonthlyRate))
+	if !p.Goal.Deadline.IsZero() {
+		fmt.Printf("  Срок: %s, нужно откладывать %s в месяц\n", p.Goal.Deadline.Format(layout), app.formatMoney(p.MonthlyNeeded))
+	}
+
+	status := ColorGreen
+	if !p.OnTrack {
+		status = ColorRed
+	}
+	if p.Projected.IsZero() {
+		fmt.Println(status.Render("  Прогноз: при текущем темпе цель не будет достигнута"))
+	} else {
+		fmt.Println(status.Render("  Прогноз достижения: " + p.Projected.Format(layout)))
+	}
+}
+
+func (app *App) printGoalDetails(ref string, now time.Time) error {
+	p, err := app.goalService.Progress(ref, now)
+	if err != nil {
+		return err
+	}
+	app.printGoalSummary(p, charts.DetectOptions())
+
+	if len(p.Contributions) == 0 {
+		fmt.Println(ColorYellow.Render("\nПополнений пока нет."))
+		return nil
+	}
+
+	contributions := p.Contributions
+	fmt.Println(ColorCyan.Render("\nПоследние пополнения:"))
+	if len(contributions) > goalsShowLimit {
+		contributions = contributions[len(contributions)-goalsShowLimit:]
+	}
+	for i := len(contributions) - 1; i >= 0; i-- {
+		c := contributions[i]
+		fmt.Printf("  %s  %+12.2f  %s\n", c.Date.Local().Format(app.cfg.DateLayout()), c.Amount, c.Description)
+	}
+	return nil
+}
+
+// parseOptionalDay parses a ДД.ММ.ГГГГ date; an empty string gives the zero time.
+func parseOptionalDay(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	day, err := time.ParseInLocation(dayLayout, s, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("некорректная дата %q, используйте формат ДД.ММ.ГГГГ", s)
+	}
+	return day, nil
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fintrack/internal/charts"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const dayLayout = "02.01.2006"
+
+func (app *App) showHeatmap() error {
+	clearScreen()
+	fmt.Println(ColorBlue.Render("================ Календарь расходов ================="))
+
+	now := time.Now()
+	fmt.Print(ColorCyan.Render(fmt.Sprintf("Год (по умолчанию %d): ", now.Year())))
+	if !app.scanner.Scan() {
+		return fmt.Errorf("ошибка чтения года")
+	}
+
+	year := now.Year()
+	if s := strings.TrimSpace(app.scanner.Text()); s != "" {
+		y, err := strconv.Atoi(s)
+		if err != nil || y < 1 {
+			return fmt.Errorf("некорректный год")
+		}
+		year = y
+	}
+
+	fmt.Print(ColorCyan.Render("Месяц 1-12 (Enter — весь год): "))
+	if !app.scanner.Scan() {
+		return fmt.Errorf("ошибка чтения месяца")
+	}
+
+	month := 0
+	if s := strings.TrimSpace(app.scanner.Text()); s != "" {
+		m, err := strconv.Atoi(s)
+		if err != nil || m < 1 || m > 12 {
+			return fmt.Errorf("месяц должен быть от 1 до 12")
+		}
+		month = m
+	}
+
+	opts := charts.DetectOptions()
+	if err := app.printHeatmap(year, month, time.Time{}, opts); err != nil {
+		return err
+	}
+
+	for {
+		fmt.Print(ColorCyan.Render("\nДата для просмотра транзакций (ДД.ММ.ГГГГ, Enter — выход): "))
+		if !app.scanner.Scan() {
+			return nil
+		}
+
+		s := strings.TrimSpace(app.scanner.Text())
+		if s == "" {
+			return nil
+		}
+
+		day, err := time.ParseInLocation(dayLayout, s, time.Local)
+		if err != nil {
+			fmt.Println(ColorRed.Render("Некорректная дата. Используйте формат ДД.ММ.ГГГГ"))
+			continue
+		}
+
+		clearScreen()
+		if err := app.printHeatmap(year, month, day, opts); err != nil {
+			return err
+		}
+		if err := app.printDayTransactions(day); err != nil {
+			return err
+		}
+	}
+}
+
+// printHeatmap prints the heatmap of the year, or of one month when month is 1-12.
+func (app *App) printHeatmap(year, month int, selected time.Time, opts charts.Options) error {
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.Local)
+	to := from.AddDate(1, 0, -1)
+	if month != 0 {
+		from = time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.Local)
+		to = from.AddDate(0, 1, -1)
+	}
+
+	totals, err := app.reportService.DailyTotals(from, to)
+	if err != nil {
+		return fmt.Errorf("ошибка построения календаря: %v", err)
+	}
+
+	days := make([]charts.HeatmapDay, len(totals))
+	for i, t := range totals {
+		days[i] = charts.HeatmapDay{Date: t.Date, Value: t.Expense}
+	}
+
+	if month != 0 {
+		fmt.Print(charts.MonthHeatmap(days, selected, opts))
+	} else {
+		fmt.Println(ColorCyan.Render(fmt.Sprintf("Расходы за %d год:", year)))
+		fmt.Print(charts.YearHeatmap(days, selected, opts))
+	}
+	return nil
+}
+
+func (app *App) printDayTransactions(day time.Time) error {
+	transactions, err := app.reportService.TransactionsOnDay(day)
+	if err != nil {
+		return fmt.Errorf("ошибка при получении транзакций: %v", err)
+	}
+
+	fmt.Println("\n" + ColorCyan.Render(fmt.Sprintf("Транзакции за %s:", day.Format(dayLayout))))
+	if len(transactions) == 0 {
+		fmt.Println(ColorYellow.Render("Нет транзакций за этот день."))
+		return nil
+	}
+
+	total := 0.0
+	for _, t := range transactions {
+		sign, style := "+", ColorGreen
+		if t.Type == "expense" {
+			sign, style = "-", ColorRed
+			total += t.Amount
+		}
+		fmt.Printf("%s %s %-15s %s\n",
+			t.Date.Local().Format("15:04"),
+			style.Render(fmt.Sprintf("%s%10.2f", sign, t.Amount)),
+			t.Category,
+			t.Description)
+	}
+	fmt.Printf("Расход за день: %.2f\n", total)
+	return nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fintrack/internal/models"
+	"fmt"
+	"path/filepath"
+)
+
+const historyFileName = "history.json"
+
+func (app *App) historyFile() string {
+	return filepath.Join(app.profiles.Dir(app.profile), historyFileName)
+}
+
+func (app *App) requireHistory() error {
+	if app.history == nil {
+		return fmt.Errorf("история действий отключена (history_depth = 0)")
+	}
+	return nil
+}
+
+func (app *App) undo() error {
+	if err := app.requireHistory(); err != nil {
+		return err
+	}
+	entry, err := app.history.Undo()
+	if err != nil {
+		return err
+	}
+	fmt.Println(ColorGreen.Render("Отменено: " + entry.Label))
+	return nil
+}
+
+func (app *App) redo() error {
+	if err := app.requireHistory(); err != nil {
+		return err
+	}
+	entry, err := app.history.Redo()
+	if err != nil {
+		return err
+	}
+	fmt.Println(ColorGreen.Render("Повторено: " + entry.Label))
+	return nil
+}
+
+func (app *App) showHistory() error {
+	if err := app.requireHistory(); err != nil {
+		return err
+	}
+	history, err := app.history.Entries()
+	if err != nil {
+		return err
+	}
+
+	if len(history.Undo) == 0 && len(history.Redo) == 0 {
+		fmt.Println(ColorYellow.Render("История действий пуста."))
+		return nil
+	}
+
+	if len(history.Redo) > 0 {
+		fmt.Println(ColorCyan.Render("Можно повторить:"))
+		for _, e := range history.Redo {
+			app.printHistoryEntry(e)
+		}
+	}
+	if len(history.Undo) > 0 {
+		fmt.Println(ColorCyan.Render(fmt.Sprintf("Можно отменить (до %d шагов):", app.cfg.HistoryDepth)))
+		for i := len(history.Undo) - 1; i >= 0; i-- {
+			app.printHistoryEntry(history.Undo[i])
+		}
+	}
+	return nil
+}
+
+func (app *App) printHistoryEntry(e models.HistoryEntry) {
+	suffix := ""
+	if len(e.Changes) > 1 {
+		suffix = fmt.Sprintf(" (изменений: %d)", len(e.Changes))
+	}
+	fmt.Printf("  %s  %s%s\n", e.Time.Local().Format(app.cfg.DateLayout()), e.Label, suffix)
+}
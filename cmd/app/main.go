@@ -2,12 +2,21 @@ package main
 
 import (
 	"bufio"
+	"fintrack/internal/audit"
+	"fintrack/internal/backup"
+	"fintrack/internal/config"
+	"fintrack/internal/export"
 	"fintrack/internal/models"
+	"fintrack/internal/profiles"
 	"fintrack/internal/services"
 	"fintrack/internal/storage"
+	"fintrack/internal/tui"
+	"fintrack/internal/vault"
+	"flag"
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
 	"strconv"
 	"strings"
@@ -36,24 +45,43 @@ var (
 )
 
 type App struct {
-	transactionService *services.TransactionService
-	categoryService    *services.CategoryService
-	scanner            *bufio.Scanner
+	cfg                 config.Config
+	cfgPath             string
+	profiles            *profiles.Registry
+	profile             models.Profile
+	transactionService  *services.TransactionService
+	categoryService     *services.CategoryService
+	reportService       *services.ReportService
+	goalService         *services.GoalService
+	debtService         *services.DebtService
+	netWorthService     *services.NetWorthService
+	recurringService    *services.RecurringService
+	forecastService     *services.ForecastService
+	envelopeService     *services.EnvelopeService
+	subscriptionService *services.SubscriptionService
+	anomalyService      *services.AnomalyService
+	receiptService      *services.ReceiptService
+	attachmentService   *services.AttachmentService
+	payeeService        *services.PayeeService
+	auditLog            *audit.Log
+	history             *services.History
+	backups             *backup.Manager
+	scanner             *bufio.Scanner
 }
 
-func generateUniqueID() string {
-	timestamp := time.Now().UnixNano()
-	return fmt.Sprintf("tx_%d", timestamp%10000000000)
-}
-
-func NewApp() *App {
-	// use concrete files under internal/data for transactions and categories
-	fileStorage := storage.NewFileStorage("internal/data/transactions.json", "internal/data/categories.json")
+// NewApp opens the named profile, or the last used one when profileName is empty.
+func NewApp(cfg config.Config, cfgPath string, profileName string) *App {
+	if err := migrateLegacyData(cfg.DataDir); err != nil {
+		fmt.Println(ColorYellow.Render(err.Error()))
+	}
 
-	transactionService := services.NewTransactionService(fileStorage)
-	categoryService := services.NewCategoryService(fileStorage)
+	registry, err := profiles.Open(cfg.DataDir)
+	if err != nil {
+		fmt.Println(ColorRed.Render(err.Error()))
+		return nil
+	}
 
-	_, err := models.GetDefaultCategories()
+	_, err = models.GetDefaultCategories()
 
 	if err != nil {
 		fmt.Printf("%s %s", ColorYellow.Render("Предупреждение при загрузке категорий: "), ColorYellow.Render(fmt.Sprintf("%v", err)))
@@ -61,12 +89,141 @@ func NewApp() *App {
 
 	scanner := bufio.NewScanner(os.Stdin)
 
-	return &App{
-		transactionService: transactionService,
-		categoryService:    categoryService,
-		scanner:            scanner,
+	app := &App{
+		cfg:      cfg,
+		cfgPath:  cfgPath,
+		profiles: registry,
+		backups:  backup.NewManager(cfg.DataDir, cfg.BackupDaily, cfg.BackupMonthly),
+		scanner:  scanner,
+	}
+
+	if cfg.AutoBackup {
+		if err := app.backups.Snapshot("запуск"); err != nil {
+			fmt.Println(ColorYellow.Render(err.Error()))
+		}
+	}
+
+	if profileName == "" {
+		profileName = registry.Current().Name
+	}
+	if err := app.useProfile(profileName); err != nil {
+		fmt.Println(ColorRed.Render(err.Error()))
+		return nil
+	}
+
+	return app
+}
+
+// useProfile rebuilds the services on top of the storage of the given profile,
+// asking for the passphrase first when the profile is encrypted.
+func (app *App) useProfile(name string) error {
+	profile, err := app.profiles.Get(name)
+	if err != nil {
+		return err
+	}
+
+	dir := app.profiles.Dir(profile)
+	if err := vault.Recover(dir); err != nil {
+		return fmt.Errorf("не удалось завершить перешифрование профиля %s: %w", profile.Name, err)
 	}
 
+	var codec storage.Codec = storage.PlainCodec{}
+	if vault.Exists(dir) {
+		cipher, err := app.unlockVault(profile.Name, dir)
+		if err != nil {
+			return err
+		}
+		codec = cipher
+	}
+
+	return app.openProfile(profile, codec)
+}
+
+func (app *App) eventsFile(profile models.Profile) string {
+	return filepath.Join(app.profiles.Dir(profile), storage.EventsFileName)
+}
+
+// keptInEvents reports whether the profile has been switched to the event stream.
+func (app *App) keptInEvents(profile models.Profile) bool {
+	_, err := os.Stat(app.eventsFile(profile))
+	return err == nil
+}
+
+// openProfile builds the storage selected by storage_backend and the services on top of it.
+func (app *App) openProfile(profile models.Profile, codec storage.Codec) error {
+	transactionsFile, categoriesFile := app.profiles.Files(profile)
+	fileStorage := storage.NewFileStorageWithCodec(transactionsFile, categoriesFile, codec)
+	models.Filename = categoriesFile
+
+	var ledger storage.Storage = fileStorage
+	if app.cfg.StorageBackend == config.BackendEvents {
+		eventStorage, err := storage.NewEventStorage(app.eventsFile(profile), codec, fileStorage)
+		if err != nil {
+			return err
+		}
+		ledger = eventStorage
+	} else if app.keptInEvents(profile) {
+		// the event stream is not written back to the json files, they stopped at the switch
+		return fmt.Errorf("профиль %s ведется в журнале событий, его файлы json устарели; верните хранилище: fintrack -storage events config set storage_backend events", profile.Name)
+	}
+
+	app.profile = profile
+	app.auditLog = audit.NewLog(app.auditFile(), codec, actor("cli"))
+	app.transactionService = services.NewTransactionService(ledger)
+	app.transactionService.SetAuditor(app.auditLog)
+	app.categoryService = services.NewCategoryService(ledger)
+	app.categoryService.SetAuditor(app.auditLog)
+	app.history = nil
+	if app.cfg.HistoryDepth > 0 {
+		app.history = services.NewHistory(ledger, storage.NewHistoryFile(app.historyFile(), codec), app.cfg.HistoryDepth)
+		app.history.SetAuditor(app.auditLog)
+		app.transactionService.SetHistory(app.history)
+		app.categoryService.SetHistory(app.history)
+	}
+	if app.cfg.AutoBackup {
+		app.transactionService.SetSnapshotter(app.backups)
+		app.categoryService.SetSnapshotter(app.backups)
+		if app.history != nil {
+			app.history.SetSnapshotter(app.backups)
+		}
+	}
+	app.reportService = services.NewReportService(app.transactionService)
+	app.goalService = services.NewGoalService(storage.NewGoalFile(app.goalsFile(), codec), app.transactionService)
+	app.goalService.SetAuditor(app.auditLog)
+	app.debtService = services.NewDebtService(storage.NewDebtFile(app.debtsFile(), codec), app.transactionService)
+	app.debtService.SetAuditor(app.auditLog)
+	app.netWorthService = services.NewNetWorthService(storage.NewNetWorthFile(app.networthFile(), codec), app.transactionService, app.debtService)
+	app.netWorthService.SetAuditor(app.auditLog)
+	app.recurringService = services.NewRecurringService(storage.NewRecurringFile(app.recurringFile(), codec), app.transactionService)
+	app.recurringService.SetAuditor(app.auditLog)
+	app.forecastService = services.NewForecastService(app.transactionService, app.recurringService)
+	app.subscriptionService = services.NewSubscriptionService(app.transactionService, app.recurringService)
+	app.anomalyService = services.NewAnomalyService(storage.NewAnomalyFile(app.anomaliesFile(), codec), app.transactionService)
+	app.transactionService.Observe(app.anomalyService)
+	app.receiptService = services.NewReceiptService(storage.NewReceiptFile(app.receiptsFile(), codec), app.transactionService)
+	app.receiptService.SetAuditor(app.auditLog)
+	app.attachmentService = services.NewAttachmentService(app.attachments(codec), app.transactionService, storage.NewHistoryFile(app.historyFile(), codec))
+	app.payeeService = services.NewPayeeService(storage.NewPayeeFile(app.payeesFile(), codec), app.transactionService)
+	app.payeeService.SetAuditor(app.auditLog)
+	app.envelopeService = services.NewEnvelopeService(storage.NewEnvelopeFile(app.envelopesFile(), codec), app.transactionService)
+	app.envelopeService.SetAuditor(app.auditLog)
+	if app.history != nil {
+		app.envelopeService.SetHistory(app.history)
+	}
+	for _, o := range []services.CategoryObserver{app.goalService, app.debtService, app.recurringService, app.envelopeService, app.payeeService} {
+		app.categoryService.Observe(o)
+		if app.history != nil {
+			app.history.ObserveCategories(o)
+		}
+	}
+	if app.cfg.AutoBackup {
+		app.goalService.SetSnapshotter(app.backups)
+		app.debtService.SetSnapshotter(app.backups)
+		app.netWorthService.SetSnapshotter(app.backups)
+		app.recurringService.SetSnapshotter(app.backups)
+		app.payeeService.SetSnapshotter(app.backups)
+	}
+	return nil
 }
 
 func clearScreen() {
@@ -87,9 +244,35 @@ func (app *App) DisplayMainMenu() {
 	clearScreen()
 
 	fmt.Printf("%s\n", ColorCyan.Render("=====================FinTrack====================="))
+	fmt.Printf("%s\n", ColorYellow.Render("Профиль: "+app.profile.Name))
 	fmt.Printf("%s\n", ColorWhite.Render("1. Добавить транзакции"))
 	fmt.Printf("%s\n", ColorWhite.Render("2.Показать транзакции"))
 	fmt.Printf("%s\n", ColorWhite.Render("3.Показать категории"))
+	fmt.Printf("%s\n", ColorWhite.Render("4.Экспорт в Excel"))
+	fmt.Printf("%s\n", ColorWhite.Render("5.Отчеты по периодам"))
+	fmt.Printf("%s\n", ColorWhite.Render("6.Графики"))
+	fmt.Printf("%s\n", ColorWhite.Render("7.Календарь расходов"))
+	fmt.Printf("%s\n", ColorWhite.Render("8.Полноэкранный режим"))
+	fmt.Printf("%s\n", ColorWhite.Render("9.Профили"))
+	fmt.Printf("%s\n", ColorWhite.Render("10.Отменить последнее действие"))
+	fmt.Printf("%s\n", ColorWhite.Render("11.Повторить отмененное действие"))
+	fmt.Printf("%s\n", ColorWhite.Render("12.Цели накоплений"))
+	fmt.Printf("%s\n", ColorWhite.Render("13.Долги и кредиты"))
+	fmt.Printf("%s\n", ColorWhite.Render("14.Капитал"))
+	fmt.Printf("%s\n", ColorWhite.Render("15.Прогноз денежного потока"))
+	if app.cfg.EnvelopeBudget {
+		fmt.Printf("%s\n", ColorWhite.Render("16.Конверты"))
+	}
+	fmt.Printf("%s\n", ColorWhite.Render("17.Подписки"))
+	attention := "18.Требует внимания"
+	if anomalies, err := app.anomalyService.Attention(false); err == nil && len(anomalies) > 0 {
+		attention += fmt.Sprintf(" (%d)", len(anomalies))
+	}
+	fmt.Printf("%s\n", ColorWhite.Render(attention))
+	fmt.Printf("%s\n", ColorWhite.Render("19.Быстрый ввод"))
+	fmt.Printf("%s\n", ColorWhite.Render("20.Чек по QR-коду"))
+	fmt.Printf("%s\n", ColorWhite.Render("21.Вложения"))
+	fmt.Printf("%s\n", ColorWhite.Render("22.Получатели"))
 	fmt.Printf("%s\n", ColorWhite.Render("0.Выход"))
 	fmt.Printf("%s\n", ColorCyan.Render("=================================================="))
 
@@ -140,27 +323,47 @@ func (app *App) addTransaction() error {
 		return fmt.Errorf("нет доступных категорий для выбранного типа")
 	}
 
-	fmt.Println(ColorCyan.Render("Доступные категории: "))
+	payee, err := app.choosePayee()
+	if err != nil {
+		return err
+	}
 
-	for i, category := range categories {
-		fmt.Printf("\n%d.%s\n", i+1, category.Name)
+	// the payee's default category is used when it fits the chosen type
+	selectedCategory := ""
+	for _, category := range categories {
+		if payee.Category != "" && category.Name == payee.Category {
+			selectedCategory = category.Name
+			fmt.Println(ColorCyan.Render("Категория: " + selectedCategory))
+		}
 	}
 
-	fmt.Print(ColorCyan.Render("\nВыберите категорию(номер): "))
+	if selectedCategory == "" {
+		fmt.Println(ColorCyan.Render("Доступные категории: "))
 
-	if !app.scanner.Scan() {
-		return fmt.Errorf("ошибка чтения категории")
-	}
+		for i, category := range categories {
+			fmt.Printf("\n%d.%s\n", i+1, category.Name)
+		}
 
-	categoryindex, err := strconv.Atoi(strings.TrimSpace(app.scanner.Text()))
+		fmt.Print(ColorCyan.Render("\nВыберите категорию(номер): "))
 
-	if err != nil || categoryindex < 1 || categoryindex > len(categories) {
-		return fmt.Errorf("неверный номер категории. Выберите от 1 до %d", len(categories))
-	}
+		if !app.scanner.Scan() {
+			return fmt.Errorf("ошибка чтения категории")
+		}
 
-	selectedCategory := categories[categoryindex-1].Name
+		categoryindex, err := strconv.Atoi(strings.TrimSpace(app.scanner.Text()))
 
-	fmt.Print(ColorCyan.Render("\nВведите описание: "))
+		if err != nil || categoryindex < 1 || categoryindex > len(categories) {
+			return fmt.Errorf("неверный номер категории. Выберите от 1 до %d", len(categories))
+		}
+
+		selectedCategory = categories[categoryindex-1].Name
+	}
+
+	prompt := "\nВведите описание: "
+	if payee.Name != "" {
+		prompt = "\nВведите описание (Enter — «" + payee.Name + "»): "
+	}
+	fmt.Print(ColorCyan.Render(prompt))
 
 	if !app.scanner.Scan() {
 		return fmt.Errorf("ошибка чтения описания")
@@ -168,7 +371,9 @@ func (app *App) addTransaction() error {
 
 	descripyion := strings.TrimSpace(app.scanner.Text())
 
-	if descripyion == "" {
+	if payee.Name != "" {
+		descripyion = services.Describe(payee, descripyion)
+	} else if descripyion == "" {
 		descripyion = "\nБез описания"
 	}
 
@@ -177,7 +382,8 @@ func (app *App) addTransaction() error {
 		transactionType = "expense"
 	}
 
-	if err := app.transactionService.AddTransaction(amount, selectedCategory, descripyion, transactionType); err != nil {
+	transaction, err := app.transactionService.AddTransactionAt(amount, selectedCategory, descripyion, transactionType, time.Now())
+	if err != nil {
 		return fmt.Errorf("ошибка при добавлении транзакции: %v", err)
 	}
 
@@ -188,13 +394,14 @@ func (app *App) addTransaction() error {
 
 	fmt.Println(ColorGreen.Render("\n Транзакция успешно добавлена!\n"))
 	fmt.Printf("ID: %s\nСумма: %.2f\nТип: %s\nКатегория: %s\nОписание: %s\nДата: %s\n",
-		generateUniqueID(),
+		transaction.ID,
 		amount,
 		transactionTypeDisplay,
 		selectedCategory,
 		descripyion,
-		time.Now().Format("02.01.2006 15:04:05"),
+		transaction.Date.Format(app.cfg.DateLayout()),
 	)
+	app.warnAnomalies(transaction.ID)
 
 	return nil
 }
@@ -240,7 +447,7 @@ func (app *App) showTransactions() error {
 			displayID,
 			t.Amount, t.Category,
 			transactionType,
-			t.Date.Format("02.01.2006 15:04"),
+			t.Date.Local().Format(app.cfg.DateLayout()),
 			t.Description)
 
 	}
@@ -253,9 +460,9 @@ func (app *App) showTransactions() error {
 		balanceColor = lipgloss.NewStyle().Foreground(Red)
 	}
 
-	fmt.Printf("%s %s", ColorCyan.Render("\nИтоговый доход: "), ColorCyan.Render(fmt.Sprintf("%.2f", totalIncome)))
-	fmt.Printf("%s %s", ColorCyan.Render("\nИтоговый расход: "), ColorCyan.Render(fmt.Sprintf("%.2f", totalExpense)))
-	fmt.Printf("%s %s", balanceColor.Render("\nБаланс: "), ColorCyan.Render(fmt.Sprintf("%.2f", balance)))
+	fmt.Printf("%s %s", ColorCyan.Render("\nИтоговый доход: "), ColorCyan.Render(app.formatMoney(totalIncome)))
+	fmt.Printf("%s %s", ColorCyan.Render("\nИтоговый расход: "), ColorCyan.Render(app.formatMoney(totalExpense)))
+	fmt.Printf("%s %s", balanceColor.Render("\nБаланс: "), ColorCyan.Render(app.formatMoney(balance)))
 
 	return nil
 
@@ -303,11 +510,97 @@ func (app *App) showCategories() error {
 
 }
 
+func (app *App) exportXLSX() error {
+	clearScreen()
+	fmt.Println(ColorBlue.Render("================== Экспорт в Excel ================="))
+
+	transactions, err := app.transactionService.GetAllTransactions()
+	if err != nil {
+		return fmt.Errorf("ошибка при получении транзакций: %v", err)
+	}
+
+	if len(transactions) == 0 {
+		fmt.Println(ColorYellow.Render("Нет доступных транзакций для экспорта."))
+		return nil
+	}
+
+	fmt.Print(ColorCyan.Render("Имя файла (по умолчанию fintrack.xlsx): "))
+	if !app.scanner.Scan() {
+		return fmt.Errorf("ошибка чтения имени файла")
+	}
+
+	path := strings.TrimSpace(app.scanner.Text())
+	if path == "" {
+		path = "fintrack.xlsx"
+	}
+	if !strings.HasSuffix(strings.ToLower(path), ".xlsx") {
+		path += ".xlsx"
+	}
+
+	if err := export.WriteXLSX(path, transactions); err != nil {
+		return err
+	}
+	files, err := export.WriteAttachments(path, transactions, app.attachmentService.Content)
+	if err != nil {
+		return fmt.Errorf("ошибка при экспорте вложений: %w", err)
+	}
+
+	fmt.Println(ColorGreen.Render(fmt.Sprintf("\nЭкспортировано транзакций: %d в файл %s", len(transactions), path)))
+	if files > 0 {
+		fmt.Println(ColorGreen.Render(fmt.Sprintf("Вложений: %d в папке %s", files, export.AttachmentsDir(path))))
+	}
+	return nil
+}
+
 func main() {
-	app := NewApp()
+	flags := flag.NewFlagSet("fintrack", flag.ContinueOnError)
+	profileName := flags.String("profile", "", "профиль (учетная книга), по умолчанию — последний выбранный")
+	cfgPath := flags.String("config", config.Path(), "путь к файлу настроек")
+	overrides := map[string]*string{
+		"data_dir":        flags.String("data-dir", "", "каталог с данными"),
+		"currency":        flags.String("currency", "", "символ валюты"),
+		"locale":          flags.String("locale", "", "локаль форматирования чисел: ru или en"),
+		"theme":           flags.String("theme", "", "цветовая тема: default, light или mono"),
+		"date_format":     flags.String("date-format", "", "формат даты, например DD.MM.YYYY HH:mm"),
+		"storage_backend": flags.String("storage", "", "хранилище данных"),
+		"history_depth":   flags.String("history-depth", "", "сколько действий можно отменить (0 — без истории)"),
+	}
+	flags.Usage = printUsage
+	if err := flags.Parse(os.Args[1:]); err != nil {
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load(*cfgPath)
+	if err != nil {
+		fmt.Println(ColorRed.Render(err.Error()))
+		os.Exit(1)
+	}
+	for key, value := range overrides {
+		if *value != "" {
+			if err := cfg.Set(key, *value); err != nil {
+				fmt.Println(ColorRed.Render("Ошибка в настройках: " + err.Error()))
+				os.Exit(1)
+			}
+		}
+	}
+	if err := cfg.Validate(); err != nil {
+		fmt.Println(ColorRed.Render("Ошибка в настройках: " + err.Error()))
+		os.Exit(1)
+	}
+	applyTheme(cfg.Theme)
+
+	app := NewApp(cfg, *cfgPath, *profileName)
 
 	if app == nil {
 		fmt.Println(ColorRed.Render("Ошибка при инициализации приложения."))
+		os.Exit(1)
+	}
+
+	if args := flags.Args(); len(args) > 0 {
+		if err := runCommand(app, args); err != nil {
+			fmt.Println(ColorRed.Render("Ошибка: " + err.Error()))
+			os.Exit(1)
+		}
 		return
 	}
 
@@ -357,6 +650,104 @@ func main() {
 			if err != nil {
 				fmt.Println(ColorRed.Render("Ошибка при отображении категорий: " + err.Error()))
 			}
+		case 4:
+			err := app.exportXLSX()
+			if err != nil {
+				fmt.Println(ColorRed.Render("Ошибка при экспорте: " + err.Error()))
+			}
+		case 5:
+			err := app.showReports()
+			if err != nil {
+				fmt.Println(ColorRed.Render("Ошибка при построении отчета: " + err.Error()))
+			}
+		case 6:
+			err := app.showCharts()
+			if err != nil {
+				fmt.Println(ColorRed.Render("Ошибка при построении графика: " + err.Error()))
+			}
+		case 7:
+			err := app.showHeatmap()
+			if err != nil {
+				fmt.Println(ColorRed.Render("Ошибка при построении календаря: " + err.Error()))
+			}
+		case 8:
+			app.auditLog.SetActor(actor("tui"))
+			err := tui.Run(app.transactionService, app.categoryService, app.reportService, app.history)
+			app.auditLog.SetActor(actor("cli"))
+			if err != nil {
+				fmt.Println(ColorRed.Render("Ошибка полноэкранного режима: " + err.Error()))
+			}
+			continue
+		case 9:
+			err := app.manageProfiles()
+			if err != nil {
+				fmt.Println(ColorRed.Render("Ошибка при работе с профилями: " + err.Error()))
+			}
+		case 10:
+			err := app.undo()
+			if err != nil {
+				fmt.Println(ColorRed.Render("Ошибка при отмене: " + err.Error()))
+			}
+		case 11:
+			err := app.redo()
+			if err != nil {
+				fmt.Println(ColorRed.Render("Ошибка при повторе: " + err.Error()))
+			}
+		case 12:
+			err := app.manageGoals()
+			if err != nil {
+				fmt.Println(ColorRed.Render("Ошибка при работе с целями: " + err.Error()))
+			}
+		case 13:
+			err := app.manageDebts()
+			if err != nil {
+				fmt.Println(ColorRed.Render("Ошибка при работе с долгами: " + err.Error()))
+			}
+		case 14:
+			err := app.manageNetWorth()
+			if err != nil {
+				fmt.Println(ColorRed.Render("Ошибка при расчете капитала: " + err.Error()))
+			}
+		case 15:
+			err := app.showForecast()
+			if err != nil {
+				fmt.Println(ColorRed.Render("Ошибка при построении прогноза: " + err.Error()))
+			}
+		case 16:
+			err := app.manageEnvelopes()
+			if err != nil {
+				fmt.Println(ColorRed.Render("Ошибка при работе с конвертами: " + err.Error()))
+			}
+		case 17:
+			err := app.showSubscriptions()
+			if err != nil {
+				fmt.Println(ColorRed.Render("Ошибка при поиске подписок: " + err.Error()))
+			}
+		case 18:
+			err := app.showAttention()
+			if err != nil {
+				fmt.Println(ColorRed.Render("Ошибка отчета об отклонениях: " + err.Error()))
+			}
+		case 19:
+			err := app.quickAdd()
+			if err != nil {
+				fmt.Println(ColorRed.Render("Ошибка при добавлении транзакции: " + err.Error()))
+			}
+		case 20:
+			err := app.showReceipt()
+			if err != nil {
+				fmt.Println(ColorRed.Render("Ошибка при вводе чека: " + err.Error()))
+			}
+		case 21:
+			err := app.showAttachments()
+			if err != nil {
+				fmt.Println(ColorRed.Render("Ошибка при работе с вложениями: " + err.Error()))
+			}
+		case 22:
+			err := app.showPayees()
+			if err != nil {
+				fmt.Println(ColorRed.Render("Ошибка при работе с получателями: " + err.Error()))
+			}
 		case 0:
 			clearScreen()
 			fmt.Println(ColorGreen.Render("╔════════════════════════════════════════════════════════╗"))
@@ -366,7 +757,7 @@ func main() {
 			time.NewTimer(3 * time.Second)
 			return
 		default:
-			fmt.Println(ColorRed.Render("\nНекорректный выбор. Пожалуйста, выберите опцию от 1 до 4."))
+			fmt.Println(ColorRed.Render("\nНекорректный выбор. Пожалуйста, выберите опцию от 0 до 22."))
 		}
 
 		waitForEnter(app.scanner)
@@ -379,3 +770,12 @@ func waitForEnter(scanner *bufio.Scanner) {
 	fmt.Print(ColorWhite.Render("\nНажмите Enter для продолжения..."))
 	scanner.Scan()
 }
+
+// readLine prints prompt and returns the trimmed line the user entered.
+func (app *App) readLine(prompt string) (string, error) {
+	fmt.Print(ColorCyan.Render(prompt))
+	if !app.scanner.Scan() {
+		return "", fmt.Errorf("ошибка чтения ввода")
+	}
+	return strings.TrimSpace(app.scanner.Text()), nil
+}
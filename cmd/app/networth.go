@@ -0,0 +1,342 @@
+package main
+
+import (
+	"fintrack/internal/charts"
+	"fintrack/internal/models"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const networthFileName = "networth.json"
+
+func (app *App) networthFile() string {
+	return filepath.Join(app.profiles.Dir(app.profile), networthFileName)
+}
+
+// recordNetWorth updates the snapshot of the current month; it runs whenever
+// net worth is shown, so the trend fills in without any action from the user.
+// Warnings go to stderr to keep -json output intact.
+func (app *App) recordNetWorth() {
+	if _, err := app.netWorthService.RecordSnapshot(time.Now()); err != nil {
+		fmt.Fprintln(os.Stderr, ColorYellow.Render("Не удалось сохранить снимок капитала: "+err.Error()))
+	}
+}
+
+func (app *App) cmdNetWorth(args []string) error {
+	if len(args) > 0 {
+		switch args[0] {
+		case "asset":
+			return app.cmdAsset(args[1:])
+		case "assets":
+			return app.printAssets()
+		case "snapshot":
+			fs := flag.NewFlagSet("networth snapshot", flag.ContinueOnError)
+			backfill := fs.Bool("backfill", false, "посчитать снимки за прошлые месяцы, где их нет")
+			if err := fs.Parse(args[1:]); err != nil {
+				return err
+			}
+			return app.takeNetWorthSnapshot(*backfill)
+		case "trend":
+			fs := flag.NewFlagSet("networth trend", flag.ContinueOnError)
+			last := fs.Int("last", 0, "показать только последние N месяцев")
+			asJSON := fs.Bool("json", false, "вывести снимки в формате JSON")
+			if err := fs.Parse(args[1:]); err != nil {
+				return err
+			}
+			app.recordNetWorth()
+			if *asJSON {
+				snapshots, err := app.netWorthService.Snapshots()
+				if err != nil {
+					return err
+				}
+				return printJSON(snapshots)
+			}
+			return app.printNetWorthTrend(*last, charts.DetectOptions())
+		}
+	}
+
+	fs := flag.NewFlagSet("networth", flag.ContinueOnError)
+	date := fs.String("date", "", "на конец дня ДД.ММ.ГГГГ (по умолчанию сейчас)")
+	asJSON := fs.Bool("json", false, "вывести в формате JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("неизвестное действие: %s (доступны asset, assets, snapshot, trend)", fs.Arg(0))
+	}
+	app.recordNetWorth()
+
+	at := time.Now()
+	if *date != "" {
+		day, err := parseOptionalDay(*date)
+		if err != nil {
+			return err
+		}
+		at = day.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+
+	if *asJSON {
+		nw, err := app.netWorthService.At(at)
+		if err != nil {
+			return err
+		}
+		return printJSON(nw)
+	}
+	return app.printNetWorth(at)
+}
+
+func (app *App) cmdAsset(args []string) error {
+	if len(args) == 0 {
+		return app.printAssets()
+	}
+
+	switch args[0] {
+	case "add", "value":
+		fs := flag.NewFlagSet("networth asset "+args[0], flag.ContinueOnError)
+		kind := fs.String("kind", string(models.AssetKindAsset), "asset — актив, liability — обязательство")
+		date := fs.String("date", "", "дата оценки ДД.ММ.ГГГГ (по умолчанию сегодня)")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() < 2 {
+			return fmt.Errorf("использование: networth asset %s [-date ДД.ММ.ГГГГ] НАЗВАНИЕ СТОИМОСТЬ", args[0])
+		}
+		name := strings.Join(fs.Args()[:fs.NArg()-1], " ")
+		value, err := strconv.ParseFloat(fs.Arg(fs.NArg()-1), 64)
+		if err != nil {
+			return fmt.Errorf("некорректная стоимость %q", fs.Arg(fs.NArg()-1))
+		}
+		day, err := parseOptionalDay(*date)
+		if err != nil {
+			return err
+		}
+		if day.IsZero() {
+			day = time.Now()
+		}
+
+		if args[0] == "add" {
+			asset, err := app.netWorthService.AddAsset(name, models.AssetKind(*kind), value, day)
+			if err != nil {
+				return err
+			}
+			fmt.Println(ColorGreen.Render(fmt.Sprintf("%s добавлен(о): %s.", asset.Name, app.formatMoney(value))))
+			return nil
+		}
+		asset, err := app.netWorthService.Revalue(name, value, day)
+		if err != nil {
+			return err
+		}
+		fmt.Println(ColorGreen.Render(fmt.Sprintf("Новая оценка %s на %s: %s.", asset.Name, day.Format(dayLayout), app.formatMoney(value))))
+		return nil
+
+	case "delete":
+		if len(args) != 2 {
+			return fmt.Errorf("использование: networth asset delete НАЗВАНИЕ")
+		}
+		if err := app.netWorthService.DeleteAsset(args[1]); err != nil {
+			return err
+		}
+		fmt.Println(ColorGreen.Render(fmt.Sprintf("%s удален(о) из списка.", args[1])))
+		return nil
+
+	default:
+		return fmt.Errorf("неизвестное действие: %s (доступны add, value, delete)", args[0])
+	}
+}
+
+func (app *App) manageNetWorth() error {
+	clearScreen()
+	fmt.Println(ColorBlue.Render("======================= Капитал ======================="))
+	app.recordNetWorth()
+	if err := app.printNetWorth(time.Now()); err != nil {
+		return err
+	}
+	fmt.Println()
+	if err := app.printNetWorthTrend(12, charts.DetectOptions()); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	fmt.Println(ColorWhite.Render("1. Добавить актив или обязательство"))
+	fmt.Println(ColorWhite.Render("2. Обновить оценку"))
+	fmt.Println(ColorWhite.Render("3. Список активов и оценок"))
+	fmt.Println(ColorWhite.Render("4. Удалить актив"))
+
+	choice, err := app.readLine("\nВыберите действие (Enter — назад): ")
+	if err != nil {
+		return err
+	}
+
+	switch choice {
+	case "":
+		return nil
+	case "1":
+		s, err := app.readLine("Вид (1 — актив, 2 — обязательство): ")
+		if err != nil {
+			return err
+		}
+		kind := models.AssetKindAsset
+		switch s {
+		case "1":
+		case "2":
+			kind = models.AssetKindLiability
+		default:
+			return fmt.Errorf("неверный выбор вида. Выберите 1 или 2")
+		}
+		name, err := app.readLine("Название (например, Автомобиль): ")
+		if err != nil {
+			return err
+		}
+		value, err := app.readValue()
+		if err != nil {
+			return err
+		}
+		if _, err := app.netWorthService.AddAsset(name, kind, value, time.Now()); err != nil {
+			return err
+		}
+		fmt.Println(ColorGreen.Render(fmt.Sprintf("%s добавлен(о): %s.", name, app.formatMoney(value))))
+	case "2":
+		name, err := app.readLine("Название: ")
+		if err != nil {
+			return err
+		}
+		value, err := app.readValue()
+		if err != nil {
+			return err
+		}
+		if _, err := app.netWorthService.Revalue(name, value, time.Now()); err != nil {
+			return err
+		}
+		fmt.Println(ColorGreen.Render(fmt.Sprintf("Оценка %s обновлена: %s.", name, app.formatMoney(value))))
+	case "3":
+		return app.printAssets()
+	case "4":
+		name, err := app.readLine("Название: ")
+		if err != nil {
+			return err
+		}
+		if err := app.netWorthService.DeleteAsset(name); err != nil {
+			return err
+		}
+		fmt.Println(ColorGreen.Render(fmt.Sprintf("%s удален(о) из списка.", name)))
+	default:
+		return fmt.Errorf("неверный выбор. Выберите от 1 до 4")
+	}
+
+	app.recordNetWorth()
+	return nil
+}
+
+func (app *App) readValue() (float64, error) {
+	s, err := app.readLine("Текущая стоимость: ")
+	if err != nil {
+		return 0, err
+	}
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка при вводе стоимости")
+	}
+	return value, nil
+}
+
+func (app *App) printNetWorth(at time.Time) error {
+	nw, err := app.netWorthService.At(at)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(ColorCyan.Render("Капитал на " + at.Format(dayLayout)))
+	for _, l := range nw.Lines {
+		value := app.formatMoney(l.Value)
+		if l.Kind == models.AssetKindLiability {
+			value = "-" + value
+		}
+		fmt.Printf("  %-30s %18s\n", l.Name, value)
+	}
+
+	fmt.Printf("\nАктивы: %s | Обязательства: %s\n", app.formatMoney(nw.Cash+nw.Assets), app.formatMoney(nw.Liabilities))
+	total := "Чистый капитал: " + app.formatMoney(nw.Total)
+	if nw.Total < 0 {
+		fmt.Println(ColorRed.Render(total))
+	} else {
+		fmt.Println(ColorGreen.Render(total))
+	}
+	return nil
+}
+
+func (app *App) printNetWorthTrend(limit int, opts charts.Options) error {
+	snapshots, err := app.netWorthService.Snapshots()
+	if err != nil {
+		return err
+	}
+	if len(snapshots) < 2 {
+		fmt.Println(ColorYellow.Render("Для графика нужно хотя бы два месячных снимка (см. networth snapshot -backfill)."))
+		return nil
+	}
+	if limit > 0 && len(snapshots) > limit {
+		snapshots = snapshots[len(snapshots)-limit:]
+	}
+
+	values := make([]float64, len(snapshots))
+	bars := make([]charts.Bar, len(snapshots))
+	for i, s := range snapshots {
+		values[i] = s.Total
+		bars[i] = charts.Bar{Label: s.Month, Value: s.Total}
+	}
+
+	first, last := snapshots[0], snapshots[len(snapshots)-1]
+	fmt.Println(ColorCyan.Render(fmt.Sprintf("Динамика капитала %s — %s: %s", first.Month, last.Month, charts.Sparkline(values, opts))))
+	fmt.Print(charts.BarChart(bars, opts))
+
+	change := fmt.Sprintf("Изменение: %+.2f", last.Total-first.Total)
+	if last.Total < first.Total {
+		fmt.Println(ColorRed.Render(change))
+	} else {
+		fmt.Println(ColorGreen.Render(change))
+	}
+	return nil
+}
+
+func (app *App) takeNetWorthSnapshot(backfill bool) error {
+	if backfill {
+		added, err := app.netWorthService.Backfill(time.Now())
+		if err != nil {
+			return err
+		}
+		fmt.Println(ColorGreen.Render(fmt.Sprintf("Добавлено снимков за прошлые месяцы: %d.", added)))
+	}
+	snapshot, err := app.netWorthService.RecordSnapshot(time.Now())
+	if err != nil {
+		return err
+	}
+	fmt.Println(ColorGreen.Render(fmt.Sprintf("Снимок за %s: %s.", snapshot.Month, app.formatMoney(snapshot.Total))))
+	return nil
+}
+
+func (app *App) printAssets() error {
+	assets, err := app.netWorthService.Assets()
+	if err != nil {
+		return err
+	}
+	if len(assets) == 0 {
+		fmt.Println(ColorYellow.Render("Активов и обязательств нет."))
+		return nil
+	}
+
+	for _, a := range assets {
+		kind := "актив"
+		if a.Kind == models.AssetKindLiability {
+			kind = "обязательство"
+		}
+		fmt.Println(ColorCyan.Render(fmt.Sprintf("%s (%s)", a.Name, kind)))
+		for _, v := range a.Valuations {
+			fmt.Printf("  %s  %s\n", v.Date.Local().Format(dayLayout), app.formatMoney(v.Value))
+		}
+	}
+	return nil
+}
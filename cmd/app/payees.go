@@ -0,0 +1,305 @@
+package main
+
+import (
+	"fintrack/internal/models"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	payeesFileName     = "payees.json"
+	defaultPayeeDays   = 90
+	defaultPayeesShown = 10
+)
+
+func (app *App) payeesFile() string {
+	return filepath.Join(app.profiles.Dir(app.profile), payeesFileName)
+}
+
+func (app *App) cmdPayee(args []string) error {
+	if len(args) == 0 {
+		return app.printPayees()
+	}
+
+	switch args[0] {
+	case "list":
+		fs := flag.NewFlagSet("payee list", flag.ContinueOnError)
+		asJSON := fs.Bool("json", false, "вывести получателей в формате JSON")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if *asJSON {
+			payees, err := app.payeeService.Payees()
+			if err != nil {
+				return err
+			}
+			return printJSON(payees)
+		}
+		return app.printPayees()
+
+	case "add":
+		fs := flag.NewFlagSet("payee add", flag.ContinueOnError)
+		category := fs.String("category", "", "категория новых транзакций получателя")
+		aliases := fs.String("aliases", "", "другие написания через запятую")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() == 0 {
+			return fmt.Errorf("использование: payee add [-category КАТЕГОРИЯ] [-aliases А,Б] НАЗВАНИЕ")
+		}
+		return app.createPayee(strings.Join(fs.Args(), " "), *category, *aliases)
+
+	case "alias":
+		if len(args) < 3 {
+			return fmt.Errorf("использование: payee alias ПОЛУЧАТЕЛЬ ПСЕВДОНИМ")
+		}
+		return app.addPayeeAlias(args[1], strings.Join(args[2:], " "))
+
+	case "category":
+		if len(args) < 2 {
+			return fmt.Errorf("использование: payee category ПОЛУЧАТЕЛЬ [КАТЕГОРИЯ]")
+		}
+		return app.setPayeeCategory(args[1], strings.Join(args[2:], " "))
+
+	case "delete":
+		if len(args) != 2 {
+			return fmt.Errorf("использование: payee delete ПОЛУЧАТЕЛЬ")
+		}
+		return app.deletePayee(args[1])
+
+	case "top":
+		fs := flag.NewFlagSet("payee top", flag.ContinueOnError)
+		by := fs.String("by", "spend", "порядок: spend — по сумме, count — по числу покупок")
+		days := fs.Int("days", defaultPayeeDays, "за сколько последних дней (0 — за все время)")
+		last := fs.Int("last", defaultPayeesShown, "показать первых N получателей (0 — всех)")
+		asJSON := fs.Bool("json", false, "вывести отчет в формате JSON")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if *by != "spend" && *by != "count" {
+			return fmt.Errorf("неизвестный порядок %q (доступно spend, count)", *by)
+		}
+		if *days < 0 || *last < 0 {
+			return fmt.Errorf("значения -days и -last не могут быть отрицательными")
+		}
+		return app.printTopPayees(*days, *last, *by == "count", *asJSON)
+
+	default:
+		return fmt.Errorf("неизвестное действие: %s (доступны list, add, alias, category, delete, top)", args[0])
+	}
+}
+
+func (app *App) showPayees() error {
+	clearScreen()
+	fmt.Println(ColorBlue.Render("===================== Получатели ====================="))
+	if err := app.printPayees(); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	fmt.Println(ColorWhite.Render("1. Новый получатель"))
+	fmt.Println(ColorWhite.Render("2. Добавить другое написание"))
+	fmt.Println(ColorWhite.Render("3. Категория по умолчанию"))
+	fmt.Println(ColorWhite.Render("4. Удалить получателя"))
+	fmt.Println(ColorWhite.Render("5. Топ по сумме расходов"))
+	fmt.Println(ColorWhite.Render("6. Топ по числу покупок"))
+
+	choice, err := app.readLine("\nВыберите действие (Enter — назад): ")
+	if err != nil {
+		return err
+	}
+
+	switch choice {
+	case "":
+		return nil
+	case "1":
+		name, err := app.readLine("Название: ")
+		if err != nil {
+			return err
+		}
+		aliases, err := app.readLine("Другие написания через запятую (Enter — нет): ")
+		if err != nil {
+			return err
+		}
+		category, err := app.readLine("Категория по умолчанию (Enter — нет): ")
+		if err != nil {
+			return err
+		}
+		return app.createPayee(name, category, aliases)
+	case "2":
+		ref, err := app.readLine("Получатель: ")
+		if err != nil {
+			return err
+		}
+		alias, err := app.readLine("Другое написание, например как в выписке банка: ")
+		if err != nil {
+			return err
+		}
+		return app.addPayeeAlias(ref, alias)
+	case "3":
+		ref, err := app.readLine("Получатель: ")
+		if err != nil {
+			return err
+		}
+		category, err := app.readLine("Категория (Enter — убрать): ")
+		if err != nil {
+			return err
+		}
+		return app.setPayeeCategory(ref, category)
+	case "4":
+		ref, err := app.readLine("Получатель: ")
+		if err != nil {
+			return err
+		}
+		return app.deletePayee(ref)
+	case "5", "6":
+		fmt.Println()
+		return app.printTopPayees(defaultPayeeDays, defaultPayeesShown, choice == "6", false)
+	default:
+		return fmt.Errorf("неверный выбор")
+	}
+}
+
+func (app *App) printPayees() error {
+	payees, err := app.payeeService.Payees()
+	if err != nil {
+		return err
+	}
+	if len(payees) == 0 {
+		fmt.Println(ColorYellow.Render("Получателей пока нет."))
+		return nil
+	}
+	for i, p := range payees {
+		category := p.Category
+		if category == "" {
+			category = "—"
+		}
+		line := fmt.Sprintf("%2d. %-25s %-15s", i+1, p.Name, category)
+		if len(p.Aliases) > 0 {
+			line += "  также: " + strings.Join(p.Aliases, ", ")
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+// printTopPayees reports the expenses of the last days by payee; days of 0
+// covers the whole history and last of 0 shows every payee.
+func (app *App) printTopPayees(days, last int, byCount, asJSON bool) error {
+	var from time.Time
+	if days > 0 {
+		now := time.Now()
+		from = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, -days+1)
+	}
+	stats, err := app.payeeService.Top(from, byCount)
+	if err != nil {
+		return err
+	}
+	if last > 0 && len(stats) > last {
+		stats = stats[:last]
+	}
+	if asJSON {
+		return printJSON(stats)
+	}
+
+	if days > 0 {
+		fmt.Println(ColorCyan.Render(fmt.Sprintf("Расходы по получателям за %d дн.:", days)))
+	} else {
+		fmt.Println(ColorCyan.Render("Расходы по получателям за все время:"))
+	}
+	if len(stats) == 0 {
+		fmt.Println(ColorYellow.Render("Расходов нет."))
+		return nil
+	}
+	for i, s := range stats {
+		line := fmt.Sprintf("%2d. %-25s %-15s %12s  покупок: %3d  в среднем %10s  последняя %s",
+			i+1, s.Payee, s.Category, app.formatMoney(s.Spent), s.Count, app.formatMoney(s.Average), s.Last.Local().Format(dayLayout))
+		if s.Known {
+			fmt.Println(line)
+		} else {
+			fmt.Println(ColorWhite.Render(line))
+		}
+	}
+	return nil
+}
+
+// choosePayee offers the saved payees when a transaction is entered by hand;
+// Enter or no payees at all returns a zero Payee.
+func (app *App) choosePayee() (models.Payee, error) {
+	payees, err := app.payeeService.Payees()
+	if err != nil || len(payees) == 0 {
+		return models.Payee{}, err
+	}
+
+	fmt.Println(ColorCyan.Render("\nПолучатели:"))
+	for i, p := range payees {
+		fmt.Printf("%d.%s\n", i+1, p.Name)
+	}
+	ref, err := app.readLine("\nПолучатель (номер или название, Enter — без получателя): ")
+	if err != nil || ref == "" {
+		return models.Payee{}, err
+	}
+	if n, convErr := strconv.Atoi(ref); convErr == nil {
+		if n < 1 || n > len(payees) {
+			return models.Payee{}, fmt.Errorf("неверный номер получателя. Выберите от 1 до %d", len(payees))
+		}
+		return payees[n-1], nil
+	}
+	if p, ok, err := app.payeeService.Match(ref); err != nil || ok {
+		return p, err
+	}
+	return app.payeeService.FindPayee(ref)
+}
+
+func (app *App) createPayee(name, category, aliases string) error {
+	var list []string
+	for _, alias := range strings.Split(aliases, ",") {
+		if alias = strings.TrimSpace(alias); alias != "" {
+			list = append(list, alias)
+		}
+	}
+	payee, err := app.payeeService.CreatePayee(name, category, list)
+	if err != nil {
+		return err
+	}
+	fmt.Println(ColorGreen.Render(fmt.Sprintf("Получатель %s добавлен.", payee.Name)))
+	return nil
+}
+
+func (app *App) addPayeeAlias(ref, alias string) error {
+	payee, err := app.payeeService.AddAlias(ref, alias)
+	if err != nil {
+		return err
+	}
+	fmt.Println(ColorGreen.Render(fmt.Sprintf("«%s» теперь относится к получателю %s.", strings.TrimSpace(alias), payee.Name)))
+	return nil
+}
+
+func (app *App) setPayeeCategory(ref, category string) error {
+	payee, err := app.payeeService.SetCategory(ref, category)
+	if err != nil {
+		return err
+	}
+	if payee.Category == "" {
+		fmt.Println(ColorGreen.Render(fmt.Sprintf("У получателя %s больше нет категории по умолчанию.", payee.Name)))
+	} else {
+		fmt.Println(ColorGreen.Render(fmt.Sprintf("Категория по умолчанию для %s: %s.", payee.Name, payee.Category)))
+	}
+	return nil
+}
+
+func (app *App) deletePayee(ref string) error {
+	payee, err := app.payeeService.FindPayee(ref)
+	if err != nil {
+		return err
+	}
+	if err := app.payeeService.DeletePayee(payee.ID); err != nil {
+		return err
+	}
+	fmt.Println(ColorGreen.Render(fmt.Sprintf("Получатель %s удален.", payee.Name)))
+	return nil
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fintrack/internal/profiles"
+	"fintrack/internal/vault"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+)
+
+func (app *App) manageProfiles() error {
+	clearScreen()
+	fmt.Println(ColorBlue.Render("======================= Профили ======================="))
+	app.printProfiles()
+
+	fmt.Println()
+	fmt.Println(ColorWhite.Render("1. Переключиться на профиль"))
+	fmt.Println(ColorWhite.Render("2. Создать профиль"))
+	fmt.Println(ColorWhite.Render("3. Установить пароль для веб-доступа"))
+	fmt.Println(ColorWhite.Render("4. Шифрование данных"))
+	fmt.Print(ColorCyan.Render("\nВыберите действие (Enter — назад): "))
+
+	if !app.scanner.Scan() {
+		return fmt.Errorf("ошибка чтения выбора")
+	}
+
+	switch strings.TrimSpace(app.scanner.Text()) {
+	case "":
+		return nil
+	case "1":
+		name, err := app.chooseProfile()
+		if err != nil {
+			return err
+		}
+		return app.switchProfile(name)
+	case "2":
+		fmt.Print(ColorCyan.Render("Имя нового профиля: "))
+		if !app.scanner.Scan() {
+			return fmt.Errorf("ошибка чтения имени профиля")
+		}
+		name := strings.TrimSpace(app.scanner.Text())
+		if _, err := app.profiles.Create(name); err != nil {
+			return err
+		}
+		fmt.Println(ColorGreen.Render(fmt.Sprintf("Профиль %s создан.", name)))
+		return app.switchProfile(name)
+	case "3":
+		return app.setProfilePassword(app.profile.Name)
+	case "4":
+		return app.manageEncryption()
+	default:
+		return fmt.Errorf("неверный выбор. Выберите от 1 до 4")
+	}
+}
+
+func (app *App) printProfiles() {
+	for i, p := range app.profiles.List() {
+		marker := "  "
+		if p.Name == app.profile.Name {
+			marker = "• "
+		}
+		lock := ""
+		if p.HasPassword() {
+			lock = " (с паролем)"
+		}
+		if vault.Exists(app.profiles.Dir(p)) {
+			lock += " (зашифрован)"
+		}
+		fmt.Printf("%s%d. %s — %s%s\n", marker, i+1, p.Name, app.profiles.Dir(p), lock)
+	}
+}
+
+func (app *App) chooseProfile() (string, error) {
+	list := app.profiles.List()
+	fmt.Print(ColorCyan.Render("Номер или имя профиля: "))
+	if !app.scanner.Scan() {
+		return "", fmt.Errorf("ошибка чтения профиля")
+	}
+
+	input := strings.TrimSpace(app.scanner.Text())
+	if n, err := strconv.Atoi(input); err == nil {
+		if n < 1 || n > len(list) {
+			return "", fmt.Errorf("неверный номер профиля. Выберите от 1 до %d", len(list))
+		}
+		return list[n-1].Name, nil
+	}
+	return input, nil
+}
+
+// switchProfile reopens the services on another profile and remembers it as the current one.
+func (app *App) switchProfile(name string) error {
+	if err := app.useProfile(name); err != nil {
+		return err
+	}
+	if err := app.profiles.SetCurrent(name); err != nil {
+		return err
+	}
+	fmt.Println(ColorGreen.Render("Текущий профиль: " + name))
+	return nil
+}
+
+func (app *App) setProfilePassword(name string) error {
+	password, err := app.readPassword("Новый пароль (пусто — снять пароль): ")
+	if err != nil {
+		return err
+	}
+
+	if password != "" {
+		confirm, err := app.readPassword("Повторите пароль: ")
+		if err != nil {
+			return err
+		}
+		if confirm != password {
+			return fmt.Errorf("пароли не совпадают")
+		}
+	}
+
+	if err := app.profiles.SetPassword(name, password); err != nil {
+		return err
+	}
+
+	if password == "" {
+		fmt.Println(ColorGreen.Render("Пароль профиля снят."))
+	} else {
+		fmt.Println(ColorGreen.Render("Пароль профиля установлен."))
+	}
+	return nil
+}
+
+// readPassword reads a line without echo when stdin is a terminal.
+func (app *App) readPassword(prompt string) (string, error) {
+	fmt.Print(ColorCyan.Render(prompt))
+
+	if term.IsTerminal(os.Stdin.Fd()) {
+		password, err := term.ReadPassword(os.Stdin.Fd())
+		fmt.Println()
+		if err != nil {
+			return "", fmt.Errorf("ошибка чтения пароля: %v", err)
+		}
+		return string(password), nil
+	}
+
+	if !app.scanner.Scan() {
+		return "", fmt.Errorf("ошибка чтения пароля")
+	}
+	return app.scanner.Text(), nil
+}
+
+func (app *App) cmdProfile(args []string) error {
+	if len(args) == 0 || args[0] == "list" {
+		app.printProfiles()
+		return nil
+	}
+
+	if len(args) < 2 {
+		return fmt.Errorf("использование: profile list | create ИМЯ | use ИМЯ | passwd ИМЯ")
+	}
+
+	name := args[1]
+	switch args[0] {
+	case "create":
+		if _, err := app.profiles.Create(name); err != nil {
+			return err
+		}
+		fmt.Println(ColorGreen.Render(fmt.Sprintf("Профиль %s создан.", name)))
+		return nil
+	case "use":
+		return app.switchProfile(name)
+	case "passwd":
+		if _, err := app.profiles.Get(name); err != nil {
+			return err
+		}
+		return app.setProfilePassword(name)
+	default:
+		return fmt.Errorf("неизвестное действие с профилем: %s", args[0])
+	}
+}
+
+// checkProfileAuth accepts Basic auth credentials where the user is the profile name.
+func (app *App) checkProfileAuth(user, password string) bool {
+	return user == app.profile.Name && profiles.CheckPassword(app.profile, password)
+}
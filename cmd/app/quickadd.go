@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fintrack/internal/models"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+)
+
+func (app *App) cmdAdd(args []string) error {
+	fs := flag.NewFlagSet("add", flag.ContinueOnError)
+	category := fs.String("category", "", "категория вместо угаданной")
+	yes := fs.Bool("y", false, "сохранить без подтверждения")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("использование: add [-category КАТЕГОРИЯ] [-y] ТЕКСТ, например: add кофе 250 вчера #работа")
+	}
+
+	entry, err := app.quickEntry(strings.Join(fs.Args(), " "))
+	if err != nil {
+		return err
+	}
+	if *category != "" {
+		entry.Category = *category
+	}
+	return app.confirmQuickEntry(entry, *yes)
+}
+
+func (app *App) quickAdd() error {
+	clearScreen()
+	fmt.Println(ColorBlue.Render("================= Быстрый ввод ================="))
+	fmt.Println("Например: кофе 250 вчера #работа или +50000 зарплата 05.10")
+
+	input, err := app.readLine("\nТранзакция: ")
+	if err != nil || input == "" {
+		return err
+	}
+	entry, err := app.quickEntry(input)
+	if err != nil {
+		return err
+	}
+	return app.confirmQuickEntry(entry, false)
+}
+
+// quickEntry parses the line and lets a known payee name the transaction
+// and choose its category.
+func (app *App) quickEntry(input string) (models.QuickEntry, error) {
+	entry, err := app.transactionService.QuickEntry(input, time.Now())
+	if err != nil {
+		return models.QuickEntry{}, err
+	}
+	return app.payeeService.Apply(entry)
+}
+
+// confirmQuickEntry shows what was recognized, asks for the category when
+// it could not be guessed and saves the transaction once confirmed.
+func (app *App) confirmQuickEntry(entry models.QuickEntry, yes bool) error {
+	typeName := "Расход"
+	if entry.Type == models.TransactionIncome {
+		typeName = "Доход"
+	}
+
+	fmt.Println(ColorCyan.Render("\nРаспознано:"))
+	fmt.Printf("Сумма: %s\nТип: %s\nДата: %s\nОписание: %s\n",
+		app.formatMoney(entry.Amount), typeName, entry.Date.Format(app.cfg.DateLayout()), entry.Description)
+	if len(entry.Tags) > 0 {
+		fmt.Printf("Теги: #%s\n", strings.Join(entry.Tags, " #"))
+	}
+	if entry.Category != "" {
+		fmt.Printf("Категория: %s\n", entry.Category)
+	} else {
+		fmt.Println(ColorYellow.Render("Категория: не определена"))
+	}
+
+	if entry.Category == "" {
+		if yes {
+			return fmt.Errorf("категорию не удалось угадать, укажите ее через -category")
+		}
+		category, err := app.askCategory(entry.Type, "")
+		if err != nil {
+			return err
+		}
+		entry.Category = category
+	} else if !yes {
+		answer, err := app.readLine("Сохранить? (д/н, другая категория — введите ее название): ")
+		if err != nil {
+			return err
+		}
+		switch strings.ToLower(answer) {
+		case "д", "y", "":
+		case "н", "n":
+			fmt.Println("Транзакция не сохранена.")
+			return nil
+		default:
+			entry.Category = answer
+		}
+	}
+
+	transaction, err := app.transactionService.AddQuickEntry(entry)
+	if err != nil {
+		return err
+	}
+	fmt.Println(ColorGreen.Render(fmt.Sprintf("Сохранено: %s, %s, %s (ID %s)",
+		transaction.Description, transaction.Category, app.formatMoney(transaction.Amount), transaction.ID)))
+	app.warnAnomalies(transaction.ID)
+	return nil
+}
+
+// askCategory lists the categories of the type and reads one; Enter keeps guess.
+func (app *App) askCategory(transactionType models.TransactionType, guess string) (string, error) {
+	categories, err := app.categoryService.GetCategoriesByType(transactionType == models.TransactionIncome)
+	if err != nil {
+		return "", err
+	}
+	names := make([]string, len(categories))
+	for i, c := range categories {
+		names[i] = c.Name
+	}
+	prompt := "Категория (" + strings.Join(names, ", ") + ")"
+	if guess != "" {
+		prompt += " [" + guess + "]"
+	}
+	category, err := app.readLine(prompt + ": ")
+	if err != nil || category == "" {
+		return guess, err
+	}
+	return category, nil
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"fintrack/internal/models"
+	"fintrack/internal/services"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const receiptsFileName = "receipts.json"
+
+// receipt operation names as printed on the receipt
+var receiptOperations = map[int]string{
+	1: "приход",
+	2: "возврат прихода",
+	3: "расход",
+	4: "возврат расхода",
+}
+
+func (app *App) receiptsFile() string {
+	return filepath.Join(app.profiles.Dir(app.profile), receiptsFileName)
+}
+
+func (app *App) cmdReceipt(args []string) error {
+	if len(args) > 0 && args[0] == "list" {
+		fs := flag.NewFlagSet("receipt list", flag.ContinueOnError)
+		asJSON := fs.Bool("json", false, "вывести чеки в формате JSON")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		return app.printReceipts(*asJSON)
+	}
+
+	fs := flag.NewFlagSet("receipt", flag.ContinueOnError)
+	file := fs.String("file", "", "файл с текстом QR-кода")
+	category := fs.String("category", "", "категория вместо угаданной")
+	description := fs.String("description", "", "описание (по умолчанию «Чек от ДД.ММ.ГГГГ»)")
+	yes := fs.Bool("y", false, "сохранить без вопросов")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var raw string
+	switch {
+	case *file != "":
+		data, err := os.ReadFile(*file)
+		if err != nil {
+			return fmt.Errorf("ошибка чтения файла: %w", err)
+		}
+		raw = firstLine(string(data))
+	case fs.NArg() > 0:
+		raw = strings.Join(fs.Args(), "")
+	default:
+		line, err := app.readLine("QR-код чека: ")
+		if err != nil {
+			return err
+		}
+		raw = line
+	}
+	return app.enterReceipt(raw, *category, *description, *yes)
+}
+
+func (app *App) showReceipt() error {
+	clearScreen()
+	fmt.Println(ColorBlue.Render("================ Чек по QR-коду ================"))
+	fmt.Println("Вставьте строку из QR-кода, например: t=20261017T1230&s=1250.00&fn=...&i=...&fp=...&n=1")
+
+	raw, err := app.readLine("\nQR-код чека или путь к файлу: ")
+	if err != nil || raw == "" {
+		return err
+	}
+	if !strings.Contains(raw, "=") {
+		data, err := os.ReadFile(raw)
+		if err != nil {
+			return fmt.Errorf("ошибка чтения файла: %w", err)
+		}
+		raw = firstLine(string(data))
+	}
+	return app.enterReceipt(raw, "", "", false)
+}
+
+// enterReceipt shows the parsed receipt, refuses one that was already
+// entered, warns about similar hand-made transactions and saves the expense.
+func (app *App) enterReceipt(raw, category, description string, yes bool) error {
+	receipt, err := services.ParseReceiptQR(raw)
+	if err != nil {
+		return err
+	}
+	transactionType := services.ReceiptType(receipt)
+
+	fmt.Println(ColorCyan.Render("\nЧек:"))
+	fmt.Printf("Дата: %s\nСумма: %s\nОперация: %s\nФН: %s  ФД: %s  ФП: %s\n",
+		receipt.Time.Format(app.cfg.DateLayout()), app.formatMoney(receipt.Amount),
+		receiptOperations[receipt.Operation], receipt.FN, receipt.FD, receipt.FP)
+
+	if t, ok, err := app.receiptService.Duplicate(receipt); err != nil {
+		return err
+	} else if ok {
+		return fmt.Errorf("этот чек уже внесен: %s, %s от %s (ID %s)",
+			t.Description, app.formatMoney(t.Amount), t.Date.Format(app.cfg.DateLayout()), t.ID)
+	}
+	similar, err := app.receiptService.Similar(receipt)
+	if err != nil {
+		return err
+	}
+	for _, t := range similar {
+		fmt.Println(ColorYellow.Render(fmt.Sprintf("Возможно, уже внесено вручную: %s, %s, %s (ID %s)",
+			t.Description, t.Category, t.Date.Format(app.cfg.DateLayout()), t.ID)))
+	}
+
+	if !yes && description == "" {
+		if description, err = app.readLine("Описание (Enter — «Чек от " + receipt.Time.Format(dayLayout) + "»): "); err != nil {
+			return err
+		}
+	}
+	entry, err := app.payeeService.Apply(models.QuickEntry{Type: transactionType, Description: description})
+	if err != nil {
+		return err
+	}
+	description = entry.Description
+	if category == "" {
+		category = entry.Category
+		if !yes {
+			guess := category
+			if guess == "" {
+				if guess, err = app.transactionService.GuessCategory(entry); err != nil {
+					return err
+				}
+			}
+			if category, err = app.askCategory(transactionType, guess); err != nil {
+				return err
+			}
+		}
+	}
+
+	transaction, err := app.receiptService.Add(receipt, category, description)
+	if err != nil {
+		return err
+	}
+	fmt.Println(ColorGreen.Render(fmt.Sprintf("Сохранено: %s, %s, %s (ID %s)",
+		transaction.Description, transaction.Category, app.formatMoney(transaction.Amount), transaction.ID)))
+	app.warnAnomalies(transaction.ID)
+	return nil
+}
+
+func (app *App) printReceipts(asJSON bool) error {
+	receipts, err := app.receiptService.Receipts()
+	if err != nil {
+		return err
+	}
+	if asJSON {
+		return printJSON(receipts)
+	}
+	if len(receipts) == 0 {
+		fmt.Println(ColorYellow.Render("Чеков пока нет."))
+		return nil
+	}
+	for _, r := range receipts {
+		fmt.Printf("%s  %12s  %-16s ФН %s  ФД %s  ФП %s  → %s\n",
+			r.Time.Format(app.cfg.DateLayout()), app.formatMoney(r.Amount), receiptOperations[r.Operation],
+			r.FN, r.FD, r.FP, r.TransactionID)
+	}
+	return nil
+}
+
+func firstLine(text string) string {
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fintrack/internal/models"
+	"fintrack/internal/services"
+	"fmt"
+	"strings"
+)
+
+func (app *App) showReports() error {
+	clearScreen()
+	fmt.Println(ColorBlue.Render("=================== Отчеты по периодам =================="))
+	fmt.Print(ColorCyan.Render("Период (1-месяц 2-квартал 3-год): "))
+
+	if !app.scanner.Scan() {
+		return fmt.Errorf("ошибка чтения периода")
+	}
+
+	var period models.Period
+	switch strings.TrimSpace(app.scanner.Text()) {
+	case "1":
+		period = models.PeriodMonth
+	case "2":
+		period = models.PeriodQuarter
+	case "3":
+		period = models.PeriodYear
+	default:
+		return fmt.Errorf("неверный выбор периода. Выберите 1, 2 или 3")
+	}
+
+	return app.printReport(period, 0)
+}
+
+// printReport prints the last `limit` summaries for the period; limit <= 0 prints all of them.
+func (app *App) printReport(period models.Period, limit int) error {
+	summaries, err := app.reportService.Summaries(period)
+	if err != nil {
+		return fmt.Errorf("ошибка построения отчета: %v", err)
+	}
+
+	if len(summaries) == 0 {
+		fmt.Println(ColorYellow.Render("Нет доступных транзакций для отчета."))
+		return nil
+	}
+
+	if limit > 0 && len(summaries) > limit {
+		summaries = summaries[len(summaries)-limit:]
+	}
+
+	for _, s := range summaries {
+		app.printPeriodSummary(s)
+	}
+
+	return nil
+}
+
+func (app *App) printPeriodSummary(s models.PeriodSummary) {
+	fmt.Println("\n" + ColorCyan.Render(fmt.Sprintf("── %s ──", s.Label)))
+
+	fmt.Printf("Доход:   %16s %s\n", app.formatMoney(s.Income), formatDelta(s.HasPrevious, s.IncomeDelta, false))
+	fmt.Printf("Расход:  %16s %s\n", app.formatMoney(s.Expense), formatDelta(s.HasPrevious, s.ExpenseDelta, true))
+
+	balanceColor := ColorGreen
+	if s.Balance < 0 {
+		balanceColor = ColorRed
+	}
+	fmt.Printf("Баланс:  %s %s\n", balanceColor.Render(fmt.Sprintf("%16s", app.formatMoney(s.Balance))), formatDelta(s.HasPrevious, s.BalanceDelta, false))
+
+	fmt.Printf("Транзакций: %d | Средний расход: %.2f | Расход в день: %.2f | Норма сбережений: %.1f%%\n",
+		s.Count, s.AvgExpense, s.AvgDailyExpense, s.SavingsRate)
+
+	for _, c := range s.Categories {
+		sign := "+"
+		if c.Type == models.TransactionExpense {
+			sign = "-"
+		}
+		fmt.Printf("  %s %-20s %12.2f (%5.1f%%)\n", sign, c.Category, c.Total, c.Share)
+	}
+}
+
+// formatDelta renders the change against the previous period; for expenses growth is bad.
+func formatDelta(hasPrevious bool, delta float64, inverse bool) string {
+	if !hasPrevious || delta == 0 {
+		return ""
+	}
+
+	good := delta > 0
+	if inverse {
+		good = !good
+	}
+
+	style := ColorGreen
+	if !good {
+		style = ColorRed
+	}
+	return style.Render(fmt.Sprintf("(%+.2f)", delta))
+}
+
+func parsePeriodFlag(value string) (models.Period, error) {
+	return services.ParsePeriod(strings.ToLower(strings.TrimSpace(value)))
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fintrack/internal/models"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func (app *App) cmdSubscriptions(args []string) error {
+	fs := flag.NewFlagSet("subscriptions", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "вывести подписки в формате JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	subs, err := app.subscriptionService.Detect(time.Now())
+	if err != nil {
+		return err
+	}
+
+	switch fs.Arg(0) {
+	case "":
+		if *asJSON {
+			return printJSON(subs)
+		}
+		app.printSubscriptions(subs)
+		return nil
+	case "track":
+		if fs.NArg() != 2 {
+			return fmt.Errorf("использование: subscriptions track НОМЕРА|all")
+		}
+		return app.trackSubscriptions(subs, fs.Arg(1))
+	default:
+		return fmt.Errorf("неизвестное действие: %s (доступно track)", fs.Arg(0))
+	}
+}
+
+func (app *App) showSubscriptions() error {
+	clearScreen()
+	fmt.Println(ColorBlue.Render("======================= Подписки ======================="))
+
+	subs, err := app.subscriptionService.Detect(time.Now())
+	if err != nil {
+		return err
+	}
+	app.printSubscriptions(subs)
+	if len(subs) == 0 {
+		return nil
+	}
+
+	choice, err := app.readLine("\nНомера подписок для регулярных платежей через запятую, all — все (Enter — назад): ")
+	if err != nil || choice == "" {
+		return err
+	}
+	return app.trackSubscriptions(subs, choice)
+}
+
+func (app *App) printSubscriptions(subs []models.Subscription) {
+	if len(subs) == 0 {
+		fmt.Println(ColorYellow.Render("Периодических списаний не найдено."))
+		return
+	}
+
+	var total float64
+	for i, s := range subs {
+		line := fmt.Sprintf("%2d. %-25s %-15s %-7s %10.2f  в год %12.2f  следующее: %s",
+			i+1, s.Description, s.Category, frequencyName(s.Frequency), s.Amount, s.AnnualCost, s.Next.Local().Format(dayLayout))
+		switch {
+		case s.Lapsed:
+			fmt.Println(ColorWhite.Render(line + "  (похоже, отменена)"))
+		case s.Tracked:
+			fmt.Println(line + "  (в регулярных)")
+		default:
+			fmt.Println(ColorCyan.Render(line))
+			total += s.AnnualCost
+		}
+	}
+	fmt.Printf("\nДействующие подписки вне регулярных платежей: %s в год\n", app.formatMoney(total))
+}
+
+// trackSubscriptions converts the subscriptions with the given 1-based
+// numbers, or all active untracked ones, into recurring items. A selection
+// with a tracked or lapsed subscription is rejected as a whole.
+func (app *App) trackSubscriptions(subs []models.Subscription, choice string) error {
+	var selected []models.Subscription
+	if strings.EqualFold(strings.TrimSpace(choice), "all") {
+		for _, s := range subs {
+			if !s.Tracked && !s.Lapsed {
+				selected = append(selected, s)
+			}
+		}
+	} else {
+		// the whole selection is checked before anything is added
+		seen := make(map[int]bool)
+		for _, field := range strings.Split(choice, ",") {
+			n, err := strconv.Atoi(strings.TrimSpace(field))
+			if err != nil || n < 1 || n > len(subs) {
+				return fmt.Errorf("некорректный номер подписки: %q", strings.TrimSpace(field))
+			}
+			s := subs[n-1]
+			switch {
+			case seen[n]:
+				return fmt.Errorf("подписка %d выбрана дважды", n)
+			case s.Tracked:
+				return fmt.Errorf("подписка %d «%s» уже есть в регулярных платежах", n, s.Description)
+			case s.Lapsed:
+				return fmt.Errorf("подписка %d «%s», похоже, отменена", n, s.Description)
+			}
+			seen[n] = true
+			selected = append(selected, s)
+		}
+	}
+
+	if len(selected) == 0 {
+		fmt.Println(ColorYellow.Render("Нечего добавлять."))
+		return nil
+	}
+	for _, s := range selected {
+		item, err := app.subscriptionService.Track(s)
+		if err != nil {
+			return err
+		}
+		fmt.Println(ColorGreen.Render(fmt.Sprintf("«%s» добавлена в регулярные платежи, следующее списание %s.", item.Description, item.Start.Local().Format(dayLayout))))
+	}
+	return nil
+}
@@ -0,0 +1,479 @@
+package api
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fintrack/internal/models"
+	"fintrack/internal/services"
+	"fintrack/internal/storage"
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultLimit = 50
+	maxLimit     = 500
+)
+
+//go:embed openapi.yaml
+var openAPISpec []byte
+
+type Server struct {
+	transactionService *services.TransactionService
+	categoryService    *services.CategoryService
+	reportService      *services.ReportService
+	history            *services.History
+	mux                *http.ServeMux
+
+	realm string
+	check func(user, password string) bool
+}
+
+func NewServer(transactionService *services.TransactionService, categoryService *services.CategoryService, reportService *services.ReportService) *Server {
+	s := &Server{
+		transactionService: transactionService,
+		categoryService:    categoryService,
+		reportService:      reportService,
+		mux:                http.NewServeMux(),
+	}
+
+	s.mux.HandleFunc("GET /api/transactions", s.listTransactions)
+	s.mux.HandleFunc("POST /api/transactions", s.createTransaction)
+	s.mux.HandleFunc("GET /api/transactions/{id}", s.getTransaction)
+	s.mux.HandleFunc("PUT /api/transactions/{id}", s.updateTransaction)
+	s.mux.HandleFunc("DELETE /api/transactions/{id}", s.deleteTransaction)
+	s.mux.HandleFunc("GET /api/categories", s.listCategories)
+	s.mux.HandleFunc("GET /api/summaries", s.listSummaries)
+	s.mux.HandleFunc("GET /api/openapi.yaml", s.openAPI)
+
+	return s
+}
+
+// EnableHistory adds the undo, redo and history endpoints.
+func (s *Server) EnableHistory(history *services.History) {
+	s.history = history
+	s.mux.HandleFunc("GET /api/history", s.getHistory)
+	s.mux.HandleFunc("POST /api/undo", s.undo)
+	s.mux.HandleFunc("POST /api/redo", s.redo)
+}
+
+// RequireBasicAuth protects every route with HTTP Basic authentication.
+func (s *Server) RequireBasicAuth(realm string, check func(user, password string) bool) {
+	s.realm = realm
+	s.check = check
+}
+
+// Handler returns the HTTP handler serving the API.
+func (s *Server) Handler() http.Handler {
+	var h http.Handler = s.mux
+	if s.check != nil {
+		h = s.basicAuth(h)
+	}
+	return logRequests(sameOrigin(h))
+}
+
+// Mux exposes the router so other handlers can be mounted next to the API.
+func (s *Server) Mux() *http.ServeMux {
+	return s.mux
+}
+
+func (s *Server) ListenAndServe(addr string) error {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return server.ListenAndServe()
+}
+
+// transactionRequest is the body of create and update requests.
+type transactionRequest struct {
+	Amount      float64   `json:"amount"`
+	Category    string    `json:"category"`
+	Description string    `json:"description"`
+	Type        string    `json:"type"`
+	Date        time.Time `json:"date"`
+}
+
+type transactionPage struct {
+	Items  []models.Transaction `json:"items"`
+	Total  int                  `json:"total"`
+	Limit  int                  `json:"limit"`
+	Offset int                  `json:"offset"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func (s *Server) listTransactions(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseFilter(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	limit, err := queryInt(r, "limit", defaultLimit)
+	if err != nil || limit < 1 || limit > maxLimit {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("limit должен быть от 1 до %d", maxLimit))
+		return
+	}
+
+	offset, err := queryInt(r, "offset", 0)
+	if err != nil || offset < 0 {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("offset должен быть неотрицательным числом"))
+		return
+	}
+
+	transactions, err := s.transactionService.FindTransactions(filter)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	page := transactionPage{Items: []models.Transaction{}, Total: len(transactions), Limit: limit, Offset: offset}
+	if offset < len(transactions) {
+		page.Items = transactions[offset:min(offset+limit, len(transactions))]
+	}
+
+	writeJSON(w, http.StatusOK, page)
+}
+
+func (s *Server) createTransaction(w http.ResponseWriter, r *http.Request) {
+	var req transactionRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	if req.Date.IsZero() {
+		req.Date = time.Now()
+	}
+
+	transaction, err := s.transactionService.AddTransactionAt(req.Amount, req.Category, req.Description, req.Type, req.Date)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", "/api/transactions/"+transaction.ID)
+	writeJSON(w, http.StatusCreated, transaction)
+}
+
+func (s *Server) getTransaction(w http.ResponseWriter, r *http.Request) {
+	transaction, err := s.transactionService.GetTransactionByID(r.PathValue("id"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, transaction)
+}
+
+func (s *Server) updateTransaction(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	existing, err := s.transactionService.GetTransactionByID(id)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	var req transactionRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	if req.Date.IsZero() {
+		req.Date = existing.Date
+	}
+
+	transaction, err := s.transactionService.UpdateTransaction(id, req.Amount, req.Category, req.Description, req.Type, req.Date)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, transaction)
+}
+
+func (s *Server) deleteTransaction(w http.ResponseWriter, r *http.Request) {
+	if err := s.transactionService.DeleteTransaction(r.PathValue("id")); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listCategories(w http.ResponseWriter, r *http.Request) {
+	var (
+		categories []models.Category
+		err        error
+	)
+
+	switch r.URL.Query().Get("type") {
+	case "":
+		var income, expense []models.Category
+		if income, err = s.categoryService.GetCategoriesByType(true); err == nil {
+			expense, err = s.categoryService.GetCategoriesByType(false)
+		}
+		categories = append(income, expense...)
+	case string(models.Income):
+		categories, err = s.categoryService.GetCategoriesByType(true)
+	case string(models.Expense):
+		categories, err = s.categoryService.GetCategoriesByType(false)
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Errorf("type должен быть income или expense"))
+		return
+	}
+
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	if categories == nil {
+		categories = []models.Category{}
+	}
+	writeJSON(w, http.StatusOK, categories)
+}
+
+func (s *Server) listSummaries(w http.ResponseWriter, r *http.Request) {
+	periodParam := r.URL.Query().Get("period")
+	if periodParam == "" {
+		periodParam = string(models.PeriodMonth)
+	}
+
+	period, err := services.ParsePeriod(periodParam)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	last, err := queryInt(r, "last", 0)
+	if err != nil || last < 0 {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("last должен быть неотрицательным числом"))
+		return
+	}
+
+	summaries, err := s.reportService.Summaries(period)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	if last > 0 && len(summaries) > last {
+		summaries = summaries[len(summaries)-last:]
+	}
+	writeJSON(w, http.StatusOK, summaries)
+}
+
+func (s *Server) getHistory(w http.ResponseWriter, r *http.Request) {
+	history, err := s.history.Entries()
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	if history.Undo == nil {
+		history.Undo = []models.HistoryEntry{}
+	}
+	if history.Redo == nil {
+		history.Redo = []models.HistoryEntry{}
+	}
+	writeJSON(w, http.StatusOK, history)
+}
+
+func (s *Server) undo(w http.ResponseWriter, r *http.Request) {
+	entry, err := s.history.Undo()
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, entry)
+}
+
+func (s *Server) redo(w http.ResponseWriter, r *http.Request) {
+	entry, err := s.history.Redo()
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, entry)
+}
+
+func (s *Server) openAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/yaml")
+	_, _ = w.Write(openAPISpec)
+}
+
+func parseFilter(r *http.Request) (services.TransactionFilter, error) {
+	q := r.URL.Query()
+	filter := services.TransactionFilter{
+		Category: q.Get("category"),
+		Query:    q.Get("q"),
+	}
+
+	switch typ := q.Get("type"); typ {
+	case "":
+	case string(models.TransactionIncome), string(models.TransactionExpense):
+		filter.Type = models.TransactionType(typ)
+	default:
+		return filter, fmt.Errorf("type должен быть income или expense")
+	}
+
+	var err error
+	if filter.From, err = queryDate(r, "from"); err != nil {
+		return filter, err
+	}
+	if filter.To, err = queryDate(r, "to"); err != nil {
+		return filter, err
+	}
+	if filter.MinAmount, err = queryFloat(r, "min"); err != nil {
+		return filter, err
+	}
+	if filter.MaxAmount, err = queryFloat(r, "max"); err != nil {
+		return filter, err
+	}
+	if filter.AsOf, err = queryDate(r, "as_of"); err != nil {
+		return filter, err
+	}
+	// a plain date means the state at the end of that day
+	if v := q.Get("as_of"); len(v) == len("2006-01-02") {
+		filter.AsOf = filter.AsOf.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	return filter, nil
+}
+
+// queryDate accepts either RFC 3339 timestamps or plain YYYY-MM-DD dates.
+func queryDate(r *http.Request, name string) (time.Time, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("некорректная дата в параметре %s: %s", name, value)
+	}
+	return t, nil
+}
+
+func queryFloat(r *http.Request, name string) (float64, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return 0, nil
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("некорректное число в параметре %s: %s", name, value)
+	}
+	return f, nil
+}
+
+func queryInt(r *http.Request, name string, fallback int) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return fallback, nil
+	}
+	return strconv.Atoi(value)
+}
+
+var errNotJSON = errors.New("тело запроса должно быть в формате application/json")
+
+func decodeJSON(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != "application/json" {
+		return errNotJSON
+	}
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("некорректный JSON: %v", err)
+	}
+	return nil
+}
+
+// writeDecodeError reports a request body rejected by decodeJSON.
+func writeDecodeError(w http.ResponseWriter, err error) {
+	if errors.Is(err, errNotJSON) {
+		writeError(w, http.StatusUnsupportedMediaType, err)
+		return
+	}
+	writeError(w, http.StatusBadRequest, err)
+}
+
+// writeServiceError maps errors produced by the services to HTTP status codes.
+func writeServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, storage.ErrTransactionNotFound):
+		writeError(w, http.StatusNotFound, err)
+	case services.IsValidationError(err):
+		writeError(w, http.StatusUnprocessableEntity, err)
+	case errors.Is(err, services.ErrNoPointInTime):
+		writeError(w, http.StatusBadRequest, err)
+	case errors.Is(err, services.ErrNothingToUndo), errors.Is(err, services.ErrNothingToRedo):
+		writeError(w, http.StatusConflict, err)
+	default:
+		log.Printf("api: %v", err)
+		writeError(w, http.StatusInternalServerError, errors.New("внутренняя ошибка сервера"))
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(v)
+}
+
+func (s *Server) basicAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		if !ok || !s.check(user, password) {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf("Basic realm=%q, charset=\"UTF-8\"", s.realm))
+			writeError(w, http.StatusUnauthorized, errors.New("требуется авторизация"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// sameOrigin rejects state-changing requests a browser sends from another
+// site. Requests without Origin come from scripts and other non-browser
+// clients and are let through.
+func sameOrigin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+		default:
+			if origin := r.Header.Get("Origin"); origin != "" {
+				if u, err := url.Parse(origin); err != nil || !strings.EqualFold(u.Host, r.Host) {
+					writeError(w, http.StatusForbidden, errors.New("запрос с другого сайта отклонен"))
+					return
+				}
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		next.ServeHTTP(w, r)
+		if strings.HasPrefix(r.URL.Path, "/api/") {
+			log.Printf("%s %s %s", r.Method, r.URL.RequestURI(), time.Since(start).Round(time.Millisecond))
+		}
+	})
+}
@@ -0,0 +1,188 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fintrack/internal/models"
+	"fintrack/internal/storage"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+const FileName = "audit.jsonl"
+
+// Log is an append-only journal of ledger mutations stored as JSON lines.
+// Every entry carries the hash of the previous one, so editing, removing or
+// reordering entries breaks the chain and is reported by Verify.
+type Log struct {
+	path  string
+	codec storage.Codec
+	actor string
+	mu    sync.Mutex
+}
+
+// VerifyResult describes the outcome of a chain check.
+type VerifyResult struct {
+	Entries int
+	Head    string
+	// Broken is the sequence number of the first bad entry, 0 if the chain is intact.
+	Broken int
+	Reason string
+}
+
+func (r VerifyResult) OK() bool {
+	return r.Broken == 0
+}
+
+func NewLog(path string, codec storage.Codec, actor string) *Log {
+	return &Log{path: path, codec: codec, actor: actor}
+}
+
+// SetActor changes who is recorded as the author of subsequent entries.
+func (l *Log) SetActor(actor string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.actor = actor
+}
+
+// Record appends an entry with JSON snapshots of the entity before and after the change.
+func (l *Log) Record(action models.AuditAction, entity, id string, before, after interface{}) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entries, data, err := l.read()
+	if err != nil {
+		return err
+	}
+
+	entry := models.AuditEntry{
+		Seq:      len(entries) + 1,
+		Time:     time.Now(),
+		Actor:    l.actor,
+		Action:   action,
+		Entity:   entity,
+		EntityID: id,
+	}
+	if entry.Before, err = snapshot(before); err != nil {
+		return err
+	}
+	if entry.After, err = snapshot(after); err != nil {
+		return err
+	}
+	if len(entries) > 0 {
+		entry.PrevHash = entries[len(entries)-1].Hash
+	}
+	if entry.Hash, err = Hash(entry); err != nil {
+		return err
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	data = append(data, line...)
+	data = append(data, '\n')
+	return storage.WriteFile(l.codec, l.path, data)
+}
+
+// Entries returns all entries from oldest to newest.
+func (l *Log) Entries() ([]models.AuditEntry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entries, _, err := l.read()
+	return entries, err
+}
+
+// Verify recomputes every hash and checks the links between entries.
+func (l *Log) Verify() (VerifyResult, error) {
+	entries, err := l.Entries()
+	if err != nil {
+		return VerifyResult{}, err
+	}
+
+	result := VerifyResult{Entries: len(entries)}
+	prev := ""
+	for i, e := range entries {
+		broken := func(reason string) (VerifyResult, error) {
+			result.Broken = i + 1
+			result.Reason = reason
+			return result, nil
+		}
+
+		if e.Seq != i+1 {
+			return broken(fmt.Sprintf("ожидался номер %d, найден %d: записи удалены или переставлены", i+1, e.Seq))
+		}
+		if e.PrevHash != prev {
+			return broken("ссылка на предыдущую запись не совпадает")
+		}
+		sum, err := Hash(e)
+		if err != nil {
+			return VerifyResult{}, err
+		}
+		if sum != e.Hash {
+			return broken("содержимое записи изменено")
+		}
+		prev = e.Hash
+	}
+
+	result.Head = prev
+	return result, nil
+}
+
+// Hash returns the hex SHA-256 of the entry with its Hash field cleared.
+func Hash(e models.AuditEntry) (string, error) {
+	e.Hash = ""
+	data, err := json.Marshal(e)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// read returns the parsed entries together with the raw decoded file.
+// Unlike the ledger files a malformed log is an error: it must never be silently reset.
+func (l *Log) read() ([]models.AuditEntry, []byte, error) {
+	data, err := storage.ReadFile(l.codec, l.path)
+	if os.IsNotExist(err) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("ошибка чтения журнала аудита: %w", err)
+	}
+
+	var entries []models.AuditEntry
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var e models.AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, nil, fmt.Errorf("журнал аудита поврежден в строке %d: %w", line, err)
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("ошибка чтения журнала аудита: %w", err)
+	}
+	return entries, data, nil
+}
+
+func snapshot(v interface{}) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка сериализации снимка: %w", err)
+	}
+	return data, nil
+}
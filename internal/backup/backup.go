@@ -0,0 +1,492 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	DirName = "backups"
+
+	manifestName = ".fintrack-backup.json"
+	filePrefix   = "fintrack-"
+	fileSuffix   = ".tar.gz"
+	timeLayout   = "20060102-150405.000"
+
+	// encrypted files are not JSON and are checked by hash only
+	encryptedMagic = "FTENC1\n"
+
+	// the most recent copies are always kept, so that several destructive
+	// steps in a row can each be rolled back
+	keepLatest = 5
+)
+
+// Manifest is stored as the first entry of every archive.
+type Manifest struct {
+	Created time.Time         `json:"created"`
+	Reason  string            `json:"reason"`
+	Files   map[string]string `json:"files"` // slash-separated path -> sha256
+}
+
+// Info describes a backup archive on disk.
+type Info struct {
+	Path    string
+	Created time.Time
+	Size    int64
+}
+
+// Manager takes, prunes and restores backups of a data directory. Archives
+// are kept in its backups subdirectory, which is never archived itself.
+type Manager struct {
+	dataDir     string
+	dir         string
+	keepDaily   int
+	keepMonthly int
+}
+
+func NewManager(dataDir string, keepDaily, keepMonthly int) *Manager {
+	return &Manager{
+		dataDir:     dataDir,
+		dir:         filepath.Join(dataDir, DirName),
+		keepDaily:   keepDaily,
+		keepMonthly: keepMonthly,
+	}
+}
+
+// Dir returns the directory holding the archives.
+func (m *Manager) Dir() string {
+	return m.dir
+}
+
+// Snapshot takes a backup unless nothing changed since the last one and
+// applies the retention policy. Services call it before destructive operations.
+func (m *Manager) Snapshot(reason string) error {
+	if _, err := m.Create(reason, false); err != nil {
+		return fmt.Errorf("ошибка резервного копирования: %w", err)
+	}
+	if _, err := m.Prune(); err != nil {
+		return fmt.Errorf("ошибка удаления старых копий: %w", err)
+	}
+	return nil
+}
+
+// Create archives the data directory. Unless force is set, it returns an
+// empty path and writes nothing when the data matches the latest backup.
+func (m *Manager) Create(reason string, force bool) (string, error) {
+	files, err := m.collect()
+	if err != nil {
+		return "", err
+	}
+
+	manifest := Manifest{Created: time.Now(), Reason: reason, Files: make(map[string]string, len(files))}
+	contents := make(map[string][]byte, len(files))
+	for _, name := range files {
+		data, err := os.ReadFile(filepath.Join(m.dataDir, filepath.FromSlash(name)))
+		if err != nil {
+			return "", err
+		}
+		contents[name] = data
+		manifest.Files[name] = hash(data)
+	}
+
+	if !force {
+		if latest, err := m.latestManifest(); err == nil && sameFiles(latest.Files, manifest.Files) {
+			return "", nil
+		}
+	}
+
+	if err := os.MkdirAll(m.dir, 0700); err != nil {
+		return "", err
+	}
+
+	target := filepath.Join(m.dir, filePrefix+manifest.Created.Format(timeLayout)+fileSuffix)
+	tmp, err := os.CreateTemp(m.dir, ".backup-*.tmp")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := writeArchive(tmp, manifest, files, contents); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Chmod(tmp.Name(), 0600); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return "", err
+	}
+	return target, nil
+}
+
+// List returns the archives from newest to oldest.
+func (m *Manager) List() ([]Info, error) {
+	entries, err := os.ReadDir(m.dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var list []Info
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, filePrefix) || !strings.HasSuffix(name, fileSuffix) {
+			continue
+		}
+		created, err := time.ParseInLocation(timeLayout, strings.TrimSuffix(strings.TrimPrefix(name, filePrefix), fileSuffix), time.Local)
+		if err != nil {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, Info{Path: filepath.Join(m.dir, name), Created: created, Size: info.Size()})
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Created.After(list[j].Created)
+	})
+	return list, nil
+}
+
+// Prune keeps the newest backup of each of the last keepDaily days and of
+// each of the last keepMonthly months, plus the keepLatest newest ones, and
+// removes the rest. It returns the removed paths.
+func (m *Manager) Prune() ([]string, error) {
+	list, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+
+	days := make(map[string]bool)
+	months := make(map[string]bool)
+	var removed []string
+	for i, b := range list {
+		keep := i < keepLatest
+
+		day := b.Created.Format("2006-01-02")
+		if !days[day] && len(days) < m.keepDaily {
+			days[day] = true
+			keep = true
+		}
+		month := b.Created.Format("2006-01")
+		if !months[month] && len(months) < m.keepMonthly {
+			months[month] = true
+			keep = true
+		}
+
+		if keep {
+			continue
+		}
+		if err := os.Remove(b.Path); err != nil {
+			return removed, err
+		}
+		removed = append(removed, b.Path)
+	}
+	return removed, nil
+}
+
+// Verify reads the whole archive and checks it against its manifest.
+func Verify(archive string) (Manifest, error) {
+	manifest, _, err := readArchive(archive)
+	return manifest, err
+}
+
+// Restore validates the archive, backs up the current data and replaces the
+// contents of the data directory with the archived files. The backups
+// directory is left untouched.
+func (m *Manager) Restore(archive string) error {
+	manifest, contents, err := readArchive(archive)
+	if err != nil {
+		return fmt.Errorf("архив не прошел проверку, данные не изменены: %w", err)
+	}
+
+	if _, err := m.Create("перед восстановлением из "+filepath.Base(archive), true); err != nil {
+		return fmt.Errorf("не удалось сохранить текущие данные, восстановление отменено: %w", err)
+	}
+
+	stamp := time.Now().Format("20060102150405")
+	staging := filepath.Join(m.dataDir, ".restore-"+stamp)
+	old := filepath.Join(m.dataDir, ".replaced-"+stamp)
+
+	for name := range manifest.Files {
+		target := filepath.Join(staging, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			os.RemoveAll(staging)
+			return err
+		}
+		if err := os.WriteFile(target, contents[name], 0600); err != nil {
+			os.RemoveAll(staging)
+			return err
+		}
+	}
+	defer os.RemoveAll(staging)
+
+	if err := os.Mkdir(old, 0700); err != nil {
+		return err
+	}
+
+	current, err := m.topLevel(staging, old)
+	if err != nil {
+		return err
+	}
+	var moved []string
+	rollback := func() {
+		for _, name := range moved {
+			os.RemoveAll(filepath.Join(m.dataDir, name))
+		}
+		for _, name := range current {
+			os.Rename(filepath.Join(old, name), filepath.Join(m.dataDir, name))
+		}
+		os.RemoveAll(old)
+	}
+
+	for _, name := range current {
+		if err := os.Rename(filepath.Join(m.dataDir, name), filepath.Join(old, name)); err != nil {
+			rollback()
+			return err
+		}
+	}
+
+	restored, err := os.ReadDir(staging)
+	if err != nil {
+		rollback()
+		return err
+	}
+	for _, e := range restored {
+		if err := os.Rename(filepath.Join(staging, e.Name()), filepath.Join(m.dataDir, e.Name())); err != nil {
+			rollback()
+			return err
+		}
+		moved = append(moved, e.Name())
+	}
+
+	return os.RemoveAll(old)
+}
+
+// collect lists the files to archive as slash-separated relative paths.
+func (m *Manager) collect() ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(m.dataDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(m.dataDir, p)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if rel != "." && (rel == DirName || strings.HasPrefix(d.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || strings.HasSuffix(d.Name(), ".tmp") {
+			return nil
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	sort.Strings(files)
+	return files, err
+}
+
+// topLevel lists the entries of the data directory that a restore replaces.
+func (m *Manager) topLevel(skip ...string) ([]string, error) {
+	entries, err := os.ReadDir(m.dataDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+outer:
+	for _, e := range entries {
+		if e.Name() == DirName {
+			continue
+		}
+		for _, s := range skip {
+			if e.Name() == filepath.Base(s) {
+				continue outer
+			}
+		}
+		names = append(names, e.Name())
+	}
+	return names, nil
+}
+
+func (m *Manager) latestManifest() (Manifest, error) {
+	list, err := m.List()
+	if err != nil || len(list) == 0 {
+		return Manifest{}, fmt.Errorf("нет резервных копий")
+	}
+
+	f, err := os.Open(list[0].Path)
+	if err != nil {
+		return Manifest{}, err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return Manifest{}, err
+	}
+	tr := tar.NewReader(gz)
+	header, err := tr.Next()
+	if err != nil {
+		return Manifest{}, err
+	}
+	if header.Name != manifestName {
+		return Manifest{}, fmt.Errorf("в архиве нет описания")
+	}
+
+	var manifest Manifest
+	err = json.NewDecoder(tr).Decode(&manifest)
+	return manifest, err
+}
+
+func writeArchive(w io.Writer, manifest Manifest, files []string, contents map[string][]byte) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeEntry(tw, manifestName, manifestData, manifest.Created); err != nil {
+		return err
+	}
+	for _, name := range files {
+		if err := writeEntry(tw, name, contents[name], manifest.Created); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func writeEntry(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0600,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+// readArchive loads every file of the archive and validates it: paths stay
+// inside the data directory, hashes match the manifest and plain JSON files parse.
+func readArchive(archive string) (Manifest, map[string][]byte, error) {
+	f, err := os.Open(archive)
+	if err != nil {
+		return Manifest{}, nil, err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return Manifest{}, nil, fmt.Errorf("не архив gzip: %w", err)
+	}
+	tr := tar.NewReader(gz)
+
+	var manifest *Manifest
+	contents := make(map[string][]byte)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return Manifest{}, nil, fmt.Errorf("архив поврежден: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			return Manifest{}, nil, fmt.Errorf("недопустимая запись в архиве: %s", header.Name)
+		}
+
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return Manifest{}, nil, fmt.Errorf("архив поврежден: %w", err)
+		}
+
+		if header.Name == manifestName {
+			manifest = &Manifest{}
+			if err := json.Unmarshal(data, manifest); err != nil {
+				return Manifest{}, nil, fmt.Errorf("описание архива повреждено: %w", err)
+			}
+			continue
+		}
+
+		clean := path.Clean(header.Name)
+		if path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") || clean != header.Name {
+			return Manifest{}, nil, fmt.Errorf("недопустимый путь в архиве: %s", header.Name)
+		}
+		contents[clean] = data
+	}
+
+	if manifest == nil {
+		return Manifest{}, nil, fmt.Errorf("в архиве нет описания %s", manifestName)
+	}
+	if len(manifest.Files) != len(contents) {
+		return Manifest{}, nil, fmt.Errorf("число файлов не совпадает с описанием архива")
+	}
+	for name, sum := range manifest.Files {
+		data, ok := contents[name]
+		if !ok {
+			return Manifest{}, nil, fmt.Errorf("в архиве нет файла %s", name)
+		}
+		if hash(data) != sum {
+			return Manifest{}, nil, fmt.Errorf("контрольная сумма файла %s не совпадает", name)
+		}
+		if strings.HasSuffix(name, ".json") && !bytes.HasPrefix(data, []byte(encryptedMagic)) && !json.Valid(data) {
+			return Manifest{}, nil, fmt.Errorf("файл %s содержит некорректный JSON", name)
+		}
+	}
+	return *manifest, contents, nil
+}
+
+func sameFiles(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, sum := range a {
+		if b[name] != sum {
+			return false
+		}
+	}
+	return true
+}
+
+func hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
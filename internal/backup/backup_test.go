@@ -0,0 +1,128 @@
+package backup
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func at(value string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02 15:04", value, time.Local)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func archiveName(created string) string {
+	return filePrefix + at(created).Format(timeLayout) + fileSuffix
+}
+
+func TestPrune(t *testing.T) {
+	tests := []struct {
+		name        string
+		keepDaily   int
+		keepMonthly int
+		backups     []string
+		wantRemoved []string
+	}{
+		{
+			name:    "fewer than the latest ones are all kept",
+			backups: []string{"2025-03-10 18:00", "2025-01-01 10:00", "2024-06-01 10:00"},
+		},
+		{
+			name: "latest five are kept without a policy",
+			backups: []string{
+				"2025-03-10 18:00", "2025-03-10 12:00", "2025-03-09 20:00", "2025-03-08 21:00",
+				"2025-03-01 07:00", "2025-02-01 07:00", "2025-01-01 07:00",
+			},
+			wantRemoved: []string{"2025-02-01 07:00", "2025-01-01 07:00"},
+		},
+		{
+			name:      "newest of each of the last days",
+			keepDaily: 3,
+			backups: []string{
+				"2025-03-10 18:00", "2025-03-10 12:00", "2025-03-10 10:00",
+				"2025-03-09 20:00", "2025-03-09 09:00",
+				"2025-03-08 21:00", "2025-03-08 08:00",
+				"2025-03-01 07:00",
+			},
+			wantRemoved: []string{"2025-03-08 08:00", "2025-03-01 07:00"},
+		},
+		{
+			name:        "newest of each of the last months",
+			keepDaily:   1,
+			keepMonthly: 3,
+			backups: []string{
+				"2025-03-10 18:00", "2025-03-10 10:00", "2025-03-02 10:00",
+				"2025-02-20 10:00", "2025-02-05 10:00",
+				"2025-01-15 10:00",
+				"2024-12-31 10:00", "2024-11-30 10:00",
+			},
+			wantRemoved: []string{"2024-12-31 10:00", "2024-11-30 10:00"},
+		},
+		{
+			name:        "days with no backups do not count",
+			keepDaily:   2,
+			keepMonthly: 1,
+			backups: []string{
+				"2025-03-10 18:00", "2025-03-10 17:00", "2025-03-10 16:00", "2025-03-10 15:00", "2025-03-10 14:00",
+				"2025-03-10 13:00",
+				"2025-02-01 10:00", "2025-02-01 09:00",
+			},
+			wantRemoved: []string{"2025-03-10 13:00", "2025-02-01 09:00"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewManager(t.TempDir(), tt.keepDaily, tt.keepMonthly)
+			if err := os.MkdirAll(m.Dir(), 0700); err != nil {
+				t.Fatal(err)
+			}
+			// files that are not archives are never touched
+			for _, name := range append([]string{"notes.txt"}, archiveNames(tt.backups)...) {
+				if err := os.WriteFile(filepath.Join(m.Dir(), name), nil, 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			removed, err := m.Prune()
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make([]string, 0, len(removed))
+			for _, path := range removed {
+				got = append(got, filepath.Base(path))
+			}
+			want := archiveNames(tt.wantRemoved)
+			sort.Strings(got)
+			sort.Strings(want)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("removed %v, want %v", got, want)
+			}
+
+			list, err := m.List()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(list) != len(tt.backups)-len(tt.wantRemoved) {
+				t.Errorf("%d archives left, want %d", len(list), len(tt.backups)-len(tt.wantRemoved))
+			}
+			if _, err := os.Stat(filepath.Join(m.Dir(), "notes.txt")); err != nil {
+				t.Errorf("notes.txt: %v", err)
+			}
+		})
+	}
+}
+
+func archiveNames(created []string) []string {
+	names := make([]string, 0, len(created))
+	for _, c := range created {
+		names = append(names, archiveName(c))
+	}
+	return names
+}
@@ -0,0 +1,244 @@
+package charts
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/term"
+	"github.com/muesli/termenv"
+)
+
+const (
+	defaultWidth = 80
+	minBarWidth  = 10
+)
+
+var (
+	barColor     = lipgloss.Color("#3bdddd")
+	incomeColor  = lipgloss.Color("#00ff0d")
+	expenseColor = lipgloss.Color("#ff0000")
+
+	sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+	sparkASCII  = []rune("_.-=+*#@")
+)
+
+// Options controls how charts are rendered.
+type Options struct {
+	// Width is the total width available for a chart line, in cells.
+	Width int
+	// Color enables lipgloss colors and unicode blocks; without it charts use plain ASCII.
+	Color bool
+}
+
+// DetectOptions reads the terminal width and color support of stdout.
+func DetectOptions() Options {
+	opts := Options{
+		Width: defaultWidth,
+		Color: lipgloss.ColorProfile() != termenv.Ascii,
+	}
+
+	if term.IsTerminal(os.Stdout.Fd()) {
+		if width, _, err := term.GetSize(os.Stdout.Fd()); err == nil && width > 0 {
+			opts.Width = width
+		}
+	}
+
+	return opts
+}
+
+// Bar is a single labeled value of a horizontal bar chart.
+type Bar struct {
+	Label string
+	Value float64
+}
+
+// BarChart renders one horizontal bar per item scaled to the largest value.
+func BarChart(bars []Bar, opts Options) string {
+	if len(bars) == 0 {
+		return ""
+	}
+
+	labelWidth := 0
+	maxValue := 0.0
+	for _, b := range bars {
+		labelWidth = max(labelWidth, lipgloss.Width(b.Label))
+		maxValue = math.Max(maxValue, b.Value)
+	}
+
+	values := make([]string, len(bars))
+	valueWidth := 0
+	for i, b := range bars {
+		values[i] = fmt.Sprintf("%.2f", b.Value)
+		valueWidth = max(valueWidth, len(values[i]))
+	}
+
+	barWidth := max(opts.Width-labelWidth-valueWidth-4, minBarWidth)
+	fill := barRune(opts)
+	style := lipgloss.NewStyle().Foreground(barColor)
+
+	var sb strings.Builder
+	for i, b := range bars {
+		n := scale(b.Value, maxValue, barWidth)
+		bar := strings.Repeat(string(fill), n)
+		if opts.Color {
+			bar = style.Render(bar)
+		}
+		sb.WriteString(fmt.Sprintf("%s │%s%s %*s\n",
+			padRight(b.Label, labelWidth),
+			bar,
+			strings.Repeat(" ", barWidth-n),
+			valueWidth, values[i]))
+	}
+
+	return sb.String()
+}
+
+// Sparkline renders values as a single line of block characters, one per value.
+// When there are more values than fit into the width only the most recent are shown.
+func Sparkline(values []float64, opts Options) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	if opts.Width > 0 && len(values) > opts.Width {
+		values = values[len(values)-opts.Width:]
+	}
+
+	levels := sparkBlocks
+	if !opts.Color {
+		levels = sparkASCII
+	}
+
+	minValue, maxValue := values[0], values[0]
+	for _, v := range values {
+		minValue = math.Min(minValue, v)
+		maxValue = math.Max(maxValue, v)
+	}
+
+	var sb strings.Builder
+	for _, v := range values {
+		idx := 0
+		if maxValue > minValue {
+			idx = int((v - minValue) / (maxValue - minValue) * float64(len(levels)-1))
+		}
+		sb.WriteRune(levels[idx])
+	}
+
+	line := sb.String()
+	if opts.Color {
+		line = lipgloss.NewStyle().Foreground(barColor).Render(line)
+	}
+	return line
+}
+
+// StackedBar is one row of a stacked income/expense chart.
+type StackedBar struct {
+	Label   string
+	Income  float64
+	Expense float64
+}
+
+// StackedChart renders income and expense of each row as two adjacent segments
+// of one bar, scaled to the largest income+expense sum.
+func StackedChart(rows []StackedBar, opts Options) string {
+	if len(rows) == 0 {
+		return ""
+	}
+
+	labelWidth := 0
+	maxValue := 0.0
+	for _, r := range rows {
+		labelWidth = max(labelWidth, lipgloss.Width(r.Label))
+		maxValue = math.Max(maxValue, r.Income+r.Expense)
+	}
+
+	totals := make([]string, len(rows))
+	totalWidth := 0
+	for i, r := range rows {
+		totals[i] = fmt.Sprintf("+%.2f / -%.2f", r.Income, r.Expense)
+		totalWidth = max(totalWidth, len(totals[i]))
+	}
+
+	barWidth := max(opts.Width-labelWidth-totalWidth-4, minBarWidth)
+
+	incomeRune, expenseRune := '+', '-'
+	if opts.Color {
+		incomeRune, expenseRune = '█', '█'
+	}
+	incomeStyle := lipgloss.NewStyle().Foreground(incomeColor)
+	expenseStyle := lipgloss.NewStyle().Foreground(expenseColor)
+
+	var sb strings.Builder
+	for i, r := range rows {
+		in := scale(r.Income, maxValue, barWidth)
+		out := scale(r.Expense, maxValue, barWidth)
+		if in+out > barWidth {
+			out = barWidth - in
+		}
+
+		income := strings.Repeat(string(incomeRune), in)
+		expense := strings.Repeat(string(expenseRune), out)
+		if opts.Color {
+			income = incomeStyle.Render(income)
+			expense = expenseStyle.Render(expense)
+		}
+
+		sb.WriteString(fmt.Sprintf("%s │%s%s%s %s\n",
+			padRight(r.Label, labelWidth),
+			income, expense,
+			strings.Repeat(" ", barWidth-in-out),
+			totals[i]))
+	}
+
+	legend := "+ доход  - расход"
+	if opts.Color {
+		legend = incomeStyle.Render("█ доход") + "  " + expenseStyle.Render("█ расход")
+	}
+	sb.WriteString(legend + "\n")
+
+	return sb.String()
+}
+
+// ProgressBar renders a bar of the given width filled up to fraction (0..1)
+// followed by the percentage.
+func ProgressBar(fraction float64, width int, opts Options) string {
+	width = max(width, minBarWidth)
+	n := scale(math.Min(fraction, 1), 1, width)
+
+	filled := strings.Repeat(string(barRune(opts)), n)
+	empty := strings.Repeat(".", width-n)
+	if opts.Color {
+		style := lipgloss.NewStyle().Foreground(barColor)
+		if fraction >= 1 {
+			style = style.Foreground(incomeColor)
+		}
+		filled = style.Render(filled)
+		empty = strings.Repeat("░", width-n)
+	}
+	return fmt.Sprintf("[%s%s] %5.1f%%", filled, empty, fraction*100)
+}
+
+func barRune(opts Options) rune {
+	if opts.Color {
+		return '█'
+	}
+	return '#'
+}
+
+func scale(value, maxValue float64, width int) int {
+	if maxValue <= 0 || value <= 0 {
+		return 0
+	}
+	n := int(math.Round(value / maxValue * float64(width)))
+	return min(max(n, 1), width)
+}
+
+func padRight(s string, width int) string {
+	if w := lipgloss.Width(s); w < width {
+		return s + strings.Repeat(" ", width-w)
+	}
+	return s
+}
@@ -0,0 +1,188 @@
+package charts
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+const heatmapLevels = 5
+
+var (
+	heatmapFrom = colorful.Color{R: 0.18, G: 0.20, B: 0.22}
+	heatmapTo   = colorful.Color{R: 1.00, G: 0.00, B: 0.00}
+
+	heatmapASCII = []rune(" .:*#")
+
+	weekdayLabels = []string{"Пн", "Вт", "Ср", "Чт", "Пт", "Сб", "Вс"}
+	monthLabels   = []string{"Янв", "Фев", "Мар", "Апр", "Май", "Июн", "Июл", "Авг", "Сен", "Окт", "Ноя", "Дек"}
+)
+
+// HeatmapDay is the value of one calendar day.
+type HeatmapDay struct {
+	Date  time.Time
+	Value float64
+}
+
+// YearHeatmap renders a GitHub-style calendar: one column per week, one row per weekday.
+// The selected day, if non-zero, is highlighted.
+func YearHeatmap(days []HeatmapDay, selected time.Time, opts Options) string {
+	if len(days) == 0 {
+		return ""
+	}
+
+	levels := heatmapLevelsFor(days)
+	first := days[0].Date
+	// align the grid to the monday of the first week
+	offset := (int(first.Weekday()) + 6) % 7
+	weeks := (offset + len(days) + 6) / 7
+
+	cellWidth := 2
+	if 3+weeks*cellWidth > opts.Width {
+		cellWidth = 1
+	}
+
+	grid := make([][]string, 7)
+	for i := range grid {
+		grid[i] = make([]string, weeks)
+		for j := range grid[i] {
+			grid[i][j] = strings.Repeat(" ", cellWidth)
+		}
+	}
+
+	header := []rune(strings.Repeat(" ", weeks*cellWidth))
+	labelEnd := 0
+	for i, d := range days {
+		pos := offset + i
+		week, weekday := pos/7, pos%7
+		grid[weekday][week] = heatmapCell(levels[i], "■", cellWidth, sameDay(d.Date, selected), opts)
+
+		// label the week column where a month starts unless it would overlap the previous label
+		if (i == 0 || d.Date.Day() == 1) && week*cellWidth >= labelEnd {
+			label := []rune(monthLabels[d.Date.Month()-1])
+			for k := 0; k < len(label) && week*cellWidth+k < len(header); k++ {
+				header[week*cellWidth+k] = label[k]
+			}
+			labelEnd = week*cellWidth + len(label) + 1
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("   " + string(header) + "\n")
+	for weekday, row := range grid {
+		sb.WriteString(weekdayLabels[weekday] + " " + strings.Join(row, "") + "\n")
+	}
+	sb.WriteString(heatmapLegend(days, opts))
+
+	return sb.String()
+}
+
+// MonthHeatmap renders a calendar page with day numbers colored by intensity.
+func MonthHeatmap(days []HeatmapDay, selected time.Time, opts Options) string {
+	if len(days) == 0 {
+		return ""
+	}
+
+	levels := heatmapLevelsFor(days)
+	offset := (int(days[0].Date.Weekday()) + 6) % 7
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s %d\n", monthLabels[days[0].Date.Month()-1], days[0].Date.Year()))
+	for _, label := range weekdayLabels {
+		sb.WriteString(fmt.Sprintf(" %s ", label))
+	}
+	sb.WriteString("\n")
+	sb.WriteString(strings.Repeat("    ", offset))
+
+	for i, d := range days {
+		sb.WriteString(heatmapCell(levels[i], fmt.Sprintf("%2d", d.Date.Day()), 3, sameDay(d.Date, selected), opts))
+		sb.WriteString(" ")
+		if (offset+i)%7 == 6 {
+			sb.WriteString("\n")
+		}
+	}
+	if (offset+len(days))%7 != 0 {
+		sb.WriteString("\n")
+	}
+	sb.WriteString(heatmapLegend(days, opts))
+
+	return sb.String()
+}
+
+// heatmapLevelsFor quantizes day values into 0..heatmapLevels-1, where 0 means no value.
+func heatmapLevelsFor(days []HeatmapDay) []int {
+	maxValue := 0.0
+	for _, d := range days {
+		maxValue = max(maxValue, d.Value)
+	}
+
+	levels := make([]int, len(days))
+	for i, d := range days {
+		if d.Value <= 0 || maxValue <= 0 {
+			continue
+		}
+		levels[i] = 1 + int(d.Value/maxValue*float64(heatmapLevels-2)+0.5)
+	}
+	return levels
+}
+
+func heatmapColor(level int) lipgloss.Color {
+	t := float64(level) / float64(heatmapLevels-1)
+	return lipgloss.Color(heatmapFrom.BlendLab(heatmapTo, t).Clamped().Hex())
+}
+
+// heatmapCell renders one cell. Single-character cells are drawn as intensity marks,
+// wider ones (day numbers) keep the text and add the mark after it in ASCII mode.
+func heatmapCell(level int, text string, width int, selected bool, opts Options) string {
+	if !opts.Color {
+		mark := string(heatmapASCII[level])
+		if selected {
+			mark = "@"
+		}
+		if lipgloss.Width(text) > 1 {
+			return padRight(text+mark, width)
+		}
+		return padRight(mark, width)
+	}
+
+	style := lipgloss.NewStyle().Foreground(heatmapColor(level))
+	if selected {
+		style = style.Reverse(true).Bold(true)
+	}
+	if lipgloss.Width(text) > 1 {
+		return style.Render(padRight(" "+text, width))
+	}
+	return style.Render(padRight(text, width))
+}
+
+func heatmapLegend(days []HeatmapDay, opts Options) string {
+	maxValue, total := 0.0, 0.0
+	for _, d := range days {
+		maxValue = max(maxValue, d.Value)
+		total += d.Value
+	}
+
+	var sb strings.Builder
+	sb.WriteString("меньше ")
+	for level := 0; level < heatmapLevels; level++ {
+		if opts.Color {
+			sb.WriteString(lipgloss.NewStyle().Foreground(heatmapColor(level)).Render("■"))
+		} else {
+			sb.WriteRune(heatmapASCII[level])
+		}
+	}
+	sb.WriteString(fmt.Sprintf(" больше   (максимум за день: %.2f, всего: %.2f)\n", maxValue, total))
+	return sb.String()
+}
+
+func sameDay(a, b time.Time) bool {
+	if a.IsZero() || b.IsZero() {
+		return false
+	}
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
@@ -0,0 +1,267 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+const (
+	appName  = "fintrack"
+	fileName = "config.toml"
+
+	BackendJSON   = "json"
+	BackendEvents = "events"
+
+	ThemeDefault = "default"
+	ThemeLight   = "light"
+	ThemeMono    = "mono"
+)
+
+// Config holds user settings. Values are resolved in order: defaults,
+// config file, FINTRACK_* environment variables, command-line flags.
+type Config struct {
+	DataDir        string `toml:"data_dir"`
+	Currency       string `toml:"currency"`
+	Locale         string `toml:"locale"`
+	Theme          string `toml:"theme"`
+	DateFormat     string `toml:"date_format"`
+	StorageBackend string `toml:"storage_backend"`
+	HistoryDepth   int    `toml:"history_depth"`
+	AutoBackup     bool   `toml:"auto_backup"`
+	BackupDaily    int    `toml:"backup_keep_daily"`
+	BackupMonthly  int    `toml:"backup_keep_monthly"`
+	EnvelopeBudget bool   `toml:"envelope_budget"`
+}
+
+// envVars maps environment variables to the keys they override.
+var envVars = []struct {
+	name string
+	key  string
+}{
+	{"FINTRACK_DATA_DIR", "data_dir"},
+	{"FINTRACK_CURRENCY", "currency"},
+	{"FINTRACK_LOCALE", "locale"},
+	{"FINTRACK_THEME", "theme"},
+	{"FINTRACK_DATE_FORMAT", "date_format"},
+	{"FINTRACK_STORAGE", "storage_backend"},
+	{"FINTRACK_HISTORY_DEPTH", "history_depth"},
+	{"FINTRACK_AUTO_BACKUP", "auto_backup"},
+	{"FINTRACK_ENVELOPE_BUDGET", "envelope_budget"},
+}
+
+func Default() Config {
+	return Config{
+		DataDir:        DefaultDataDir(),
+		Currency:       "₽",
+		Locale:         "ru",
+		Theme:          ThemeDefault,
+		DateFormat:     "DD.MM.YYYY HH:mm",
+		StorageBackend: BackendJSON,
+		HistoryDepth:   50,
+		AutoBackup:     true,
+		BackupDaily:    7,
+		BackupMonthly:  12,
+	}
+}
+
+// Path returns the config file location: $XDG_CONFIG_HOME/fintrack/config.toml
+// or the platform equivalent. FINTRACK_CONFIG overrides it.
+func Path() string {
+	if path := os.Getenv("FINTRACK_CONFIG"); path != "" {
+		return path
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return fileName
+	}
+	return filepath.Join(dir, appName, fileName)
+}
+
+// DefaultDataDir returns $XDG_DATA_HOME/fintrack, ~/.local/share/fintrack on
+// unix-like systems, or the user config dir on macOS and Windows.
+func DefaultDataDir() string {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, appName)
+	}
+
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		if dir, err := os.UserConfigDir(); err == nil {
+			return filepath.Join(dir, appName)
+		}
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join("internal", "data")
+	}
+	return filepath.Join(home, ".local", "share", appName)
+}
+
+// Load reads the config file (a missing file is not an error) and applies
+// environment overrides.
+func Load(path string) (Config, error) {
+	cfg, err := ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+	return cfg, applyEnv(&cfg)
+}
+
+// ReadFile reads the config file alone, without environment overrides, for
+// callers that write it back. A missing file gives the defaults.
+func ReadFile(path string) (Config, error) {
+	cfg := Default()
+
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if _, err := toml.Decode(string(data), &cfg); err != nil {
+			return cfg, fmt.Errorf("ошибка разбора файла настроек %s: %w", path, err)
+		}
+	case errors.Is(err, os.ErrNotExist):
+	default:
+		return cfg, fmt.Errorf("ошибка чтения файла настроек %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// applyEnv overrides cfg with the FINTRACK_* environment variables that are set.
+func applyEnv(cfg *Config) error {
+	for _, env := range envVars {
+		if value, ok := os.LookupEnv(env.name); ok && value != "" {
+			if err := cfg.Set(env.key, value); err != nil {
+				return fmt.Errorf("%s: %w", env.name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Save writes the config file, creating its directory.
+func Save(path string, cfg Config) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("# Настройки FinTrack\n")
+	buf.WriteString("# storage_backend: json или events (журнал событий с запросами на дату),\n")
+	buf.WriteString("# с events обратно на json не переключается\n")
+	buf.WriteString("# theme: default, light или mono\n")
+	buf.WriteString("# date_format: DD, MM, YYYY, YY, HH, mm, ss\n")
+	buf.WriteString("# history_depth: сколько действий можно отменить, 0 — без истории\n")
+	buf.WriteString("# auto_backup: копии при запуске и перед удалением; хранится backup_keep_daily\n")
+	buf.WriteString("# последних дней и backup_keep_monthly последних месяцев\n")
+	buf.WriteString("# envelope_budget: бюджет по конвертам, доход распределяется по категориям\n\n")
+	if err := toml.NewEncoder(&buf).Encode(cfg); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// Set changes a field by its TOML key, used by flags and the `config set` command.
+func (c *Config) Set(key, value string) error {
+	switch key {
+	case "data_dir":
+		c.DataDir = value
+	case "currency":
+		c.Currency = value
+	case "locale":
+		c.Locale = value
+	case "theme":
+		c.Theme = value
+	case "date_format":
+		c.DateFormat = value
+	case "storage_backend":
+		c.StorageBackend = value
+	case "history_depth":
+		depth, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("history_depth должен быть целым числом")
+		}
+		c.HistoryDepth = depth
+	case "auto_backup":
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("auto_backup должен быть true или false")
+		}
+		c.AutoBackup = enabled
+	case "envelope_budget":
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("envelope_budget должен быть true или false")
+		}
+		c.EnvelopeBudget = enabled
+	case "backup_keep_daily", "backup_keep_monthly":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%s должен быть целым числом", key)
+		}
+		if key == "backup_keep_daily" {
+			c.BackupDaily = n
+		} else {
+			c.BackupMonthly = n
+		}
+	default:
+		return fmt.Errorf("неизвестный параметр настроек: %s", key)
+	}
+	return nil
+}
+
+func (c Config) Validate() error {
+	if strings.TrimSpace(c.DataDir) == "" {
+		return fmt.Errorf("data_dir не может быть пустым")
+	}
+
+	switch c.StorageBackend {
+	case BackendJSON, BackendEvents:
+	default:
+		return fmt.Errorf("неизвестное хранилище: %s (доступны json, events)", c.StorageBackend)
+	}
+
+	switch c.Theme {
+	case ThemeDefault, ThemeLight, ThemeMono:
+	default:
+		return fmt.Errorf("неизвестная тема: %s (доступны default, light, mono)", c.Theme)
+	}
+
+	switch c.Locale {
+	case "ru", "en":
+	default:
+		return fmt.Errorf("неподдерживаемая локаль: %s (доступны ru, en)", c.Locale)
+	}
+
+	if strings.TrimSpace(c.DateFormat) == "" {
+		return fmt.Errorf("date_format не может быть пустым")
+	}
+
+	if c.HistoryDepth < 0 {
+		return fmt.Errorf("history_depth не может быть отрицательным")
+	}
+
+	if c.BackupDaily < 0 || c.BackupMonthly < 0 {
+		return fmt.Errorf("число хранимых резервных копий не может быть отрицательным")
+	}
+	return nil
+}
+
+// DateLayout converts DateFormat tokens (DD.MM.YYYY HH:mm) to a Go time layout.
+func (c Config) DateLayout() string {
+	return strings.NewReplacer(
+		"YYYY", "2006",
+		"YY", "06",
+		"MM", "01",
+		"DD", "02",
+		"HH", "15",
+		"mm", "04",
+		"ss", "05",
+	).Replace(c.DateFormat)
+}
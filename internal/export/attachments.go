@@ -0,0 +1,47 @@
+package export
+
+import (
+	"fintrack/internal/models"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// AttachmentsDir is the directory next to an exported workbook that
+// receives the attached files: fintrack.xlsx gets fintrack_files.
+func AttachmentsDir(path string) string {
+	return strings.TrimSuffix(path, filepath.Ext(path)) + "_files"
+}
+
+// AttachmentName is the file name of an exported attachment; the
+// transaction ID prefix keeps equal names of different transactions apart.
+func AttachmentName(t models.Transaction, a models.Attachment) string {
+	return t.ID + "_" + filepath.Base(a.Name)
+}
+
+// WriteAttachments copies the attachments of the transactions into
+// AttachmentsDir(path), reading each file with content, and returns how many
+// were written. Nothing is created when there are no attachments.
+func WriteAttachments(path string, transactions []models.Transaction, content func(models.Attachment) ([]byte, error)) (int, error) {
+	dir := AttachmentsDir(path)
+	written := 0
+	for _, t := range transactions {
+		for _, a := range t.Attachments {
+			data, err := content(a)
+			if err != nil {
+				return written, fmt.Errorf("вложение %s транзакции %s: %w", a.Name, t.ID, err)
+			}
+			if written == 0 {
+				if err := os.MkdirAll(dir, 0755); err != nil {
+					return written, err
+				}
+			}
+			if err := os.WriteFile(filepath.Join(dir, AttachmentName(t, a)), data, 0644); err != nil {
+				return written, err
+			}
+			written++
+		}
+	}
+	return written, nil
+}
@@ -0,0 +1,302 @@
+package export
+
+import (
+	"fintrack/internal/models"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	SheetTransactions  = "Транзакции"
+	SheetCategories    = "По категориям"
+	SheetIncomeExpense = "Доходы и расходы"
+
+	monthLayout = "2006-01"
+)
+
+// WriteXLSX writes transactions to an Excel workbook with three sheets:
+// raw transactions, monthly totals per category and monthly income vs expense.
+// Attachments are listed by their paths in AttachmentsDir(path).
+func WriteXLSX(path string, transactions []models.Transaction) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	styles, err := newStyles(f)
+	if err != nil {
+		return fmt.Errorf("ошибка создания стилей: %w", err)
+	}
+
+	if err := f.SetSheetName("Sheet1", SheetTransactions); err != nil {
+		return err
+	}
+	if err := writeTransactionsSheet(f, styles, transactions, filepath.Base(AttachmentsDir(path))); err != nil {
+		return fmt.Errorf("ошибка записи листа транзакций: %w", err)
+	}
+
+	if _, err := f.NewSheet(SheetCategories); err != nil {
+		return err
+	}
+	if err := writeCategoriesSheet(f, styles, transactions); err != nil {
+		return fmt.Errorf("ошибка записи листа категорий: %w", err)
+	}
+
+	if _, err := f.NewSheet(SheetIncomeExpense); err != nil {
+		return err
+	}
+	if err := writeIncomeExpenseSheet(f, styles, transactions); err != nil {
+		return fmt.Errorf("ошибка записи листа доходов и расходов: %w", err)
+	}
+
+	f.SetActiveSheet(0)
+	return f.SaveAs(path)
+}
+
+type sheetStyles struct {
+	header int
+	money  int
+	date   int
+}
+
+func newStyles(f *excelize.File) (sheetStyles, error) {
+	var s sheetStyles
+	var err error
+
+	s.header, err = f.NewStyle(&excelize.Style{
+		Font:      &excelize.Font{Bold: true, Color: "FFFFFF"},
+		Fill:      excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"3BDDDD"}},
+		Alignment: &excelize.Alignment{Horizontal: "center", Vertical: "center"},
+		Border: []excelize.Border{
+			{Type: "bottom", Color: "000000", Style: 1},
+		},
+	})
+	if err != nil {
+		return s, err
+	}
+
+	// 4 = "#,##0.00"
+	s.money, err = f.NewStyle(&excelize.Style{NumFmt: 4})
+	if err != nil {
+		return s, err
+	}
+
+	dateFormat := "dd.mm.yyyy hh:mm"
+	s.date, err = f.NewStyle(&excelize.Style{CustomNumFmt: &dateFormat})
+	if err != nil {
+		return s, err
+	}
+
+	return s, nil
+}
+
+func writeHeader(f *excelize.File, sheet string, style int, headers []string) error {
+	for i, h := range headers {
+		cell, err := excelize.CoordinatesToCellName(i+1, 1)
+		if err != nil {
+			return err
+		}
+		if err := f.SetCellValue(sheet, cell, h); err != nil {
+			return err
+		}
+	}
+
+	last, err := excelize.CoordinatesToCellName(len(headers), 1)
+	if err != nil {
+		return err
+	}
+	if err := f.SetCellStyle(sheet, "A1", last, style); err != nil {
+		return err
+	}
+
+	// freeze the header row so it stays visible while scrolling
+	return f.SetPanes(sheet, &excelize.Panes{
+		Freeze:      true,
+		YSplit:      1,
+		TopLeftCell: "A2",
+		ActivePane:  "bottomLeft",
+	})
+}
+
+func writeTransactionsSheet(f *excelize.File, styles sheetStyles, transactions []models.Transaction, attachmentsDir string) error {
+	sheet := SheetTransactions
+	headers := []string{"ID", "Дата", "Тип", "Категория", "Сумма", "Описание", "Вложения"}
+	if err := writeHeader(f, sheet, styles.header, headers); err != nil {
+		return err
+	}
+
+	for i, t := range transactions {
+		row := i + 2
+		transactionType := "Доход"
+		if t.Type == models.TransactionExpense {
+			transactionType = "Расход"
+		}
+
+		var files []string
+		for _, a := range t.Attachments {
+			files = append(files, filepath.ToSlash(filepath.Join(attachmentsDir, AttachmentName(t, a))))
+		}
+
+		values := []interface{}{t.ID, t.Date, transactionType, t.Category, t.Amount, t.Description, strings.Join(files, ", ")}
+		for col, v := range values {
+			cell, err := excelize.CoordinatesToCellName(col+1, row)
+			if err != nil {
+				return err
+			}
+			if err := f.SetCellValue(sheet, cell, v); err != nil {
+				return err
+			}
+		}
+
+		if err := f.SetCellStyle(sheet, fmt.Sprintf("B%d", row), fmt.Sprintf("B%d", row), styles.date); err != nil {
+			return err
+		}
+		if err := f.SetCellStyle(sheet, fmt.Sprintf("E%d", row), fmt.Sprintf("E%d", row), styles.money); err != nil {
+			return err
+		}
+	}
+
+	if err := f.SetColWidth(sheet, "A", "A", 24); err != nil {
+		return err
+	}
+	if err := f.SetColWidth(sheet, "B", "B", 18); err != nil {
+		return err
+	}
+	if err := f.SetColWidth(sheet, "C", "E", 14); err != nil {
+		return err
+	}
+	return f.SetColWidth(sheet, "F", "G", 40)
+}
+
+func writeCategoriesSheet(f *excelize.File, styles sheetStyles, transactions []models.Transaction) error {
+	sheet := SheetCategories
+
+	totals := make(map[string]map[string]float64)
+	categorySet := make(map[string]bool)
+	for _, t := range transactions {
+		if t.Type != models.TransactionExpense {
+			continue
+		}
+		month := t.Date.Format(monthLayout)
+		if totals[month] == nil {
+			totals[month] = make(map[string]float64)
+		}
+		totals[month][t.Category] += t.Amount
+		categorySet[t.Category] = true
+	}
+
+	categories := sortedKeys(categorySet)
+	headers := append([]string{"Месяц"}, categories...)
+	headers = append(headers, "Итого")
+	if err := writeHeader(f, sheet, styles.header, headers); err != nil {
+		return err
+	}
+
+	for i, month := range sortedMonths(totals) {
+		row := i + 2
+		if err := f.SetCellValue(sheet, fmt.Sprintf("A%d", row), month); err != nil {
+			return err
+		}
+
+		total := 0.0
+		for col, category := range categories {
+			cell, err := excelize.CoordinatesToCellName(col+2, row)
+			if err != nil {
+				return err
+			}
+			amount := totals[month][category]
+			total += amount
+			if err := f.SetCellValue(sheet, cell, amount); err != nil {
+				return err
+			}
+		}
+
+		totalCell, err := excelize.CoordinatesToCellName(len(categories)+2, row)
+		if err != nil {
+			return err
+		}
+		if err := f.SetCellValue(sheet, totalCell, total); err != nil {
+			return err
+		}
+		if err := f.SetCellStyle(sheet, fmt.Sprintf("B%d", row), totalCell, styles.money); err != nil {
+			return err
+		}
+	}
+
+	lastCol, err := excelize.ColumnNumberToName(len(headers))
+	if err != nil {
+		return err
+	}
+	return f.SetColWidth(sheet, "A", lastCol, 16)
+}
+
+func writeIncomeExpenseSheet(f *excelize.File, styles sheetStyles, transactions []models.Transaction) error {
+	sheet := SheetIncomeExpense
+
+	type monthTotals struct {
+		income  float64
+		expense float64
+	}
+	totals := make(map[string]*monthTotals)
+	for _, t := range transactions {
+		month := t.Date.Format(monthLayout)
+		if totals[month] == nil {
+			totals[month] = &monthTotals{}
+		}
+		if t.Type == models.TransactionExpense {
+			totals[month].expense += t.Amount
+		} else {
+			totals[month].income += t.Amount
+		}
+	}
+
+	if err := writeHeader(f, sheet, styles.header, []string{"Месяц", "Доход", "Расход", "Баланс"}); err != nil {
+		return err
+	}
+
+	months := make([]string, 0, len(totals))
+	for month := range totals {
+		months = append(months, month)
+	}
+	sort.Strings(months)
+
+	for i, month := range months {
+		row := i + 2
+		m := totals[month]
+		values := []interface{}{month, m.income, m.expense, m.income - m.expense}
+		for col, v := range values {
+			cell, err := excelize.CoordinatesToCellName(col+1, row)
+			if err != nil {
+				return err
+			}
+			if err := f.SetCellValue(sheet, cell, v); err != nil {
+				return err
+			}
+		}
+		if err := f.SetCellStyle(sheet, fmt.Sprintf("B%d", row), fmt.Sprintf("D%d", row), styles.money); err != nil {
+			return err
+		}
+	}
+
+	return f.SetColWidth(sheet, "A", "D", 16)
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedMonths(totals map[string]map[string]float64) []string {
+	months := make([]string, 0, len(totals))
+	for month := range totals {
+		months = append(months, month)
+	}
+	sort.Strings(months)
+	return months
+}
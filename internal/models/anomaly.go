@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+type AnomalyKind string
+
+const (
+	// AnomalyCategorySpike: spending in a category over the last 30 days is
+	// well above its rolling average.
+	AnomalyCategorySpike AnomalyKind = "category_spike"
+	// AnomalyLargeExpense: a single expense far above the usual ones in its category.
+	AnomalyLargeExpense AnomalyKind = "large_expense"
+	// AnomalyNewMerchant: the first expense with this description.
+	AnomalyNewMerchant AnomalyKind = "new_merchant"
+)
+
+// Anomaly is a transaction flagged for attention. Observed is the flagged
+// value (the expense itself or the category's 30-day total) and Expected is
+// the usual value it was compared with.
+type Anomaly struct {
+	ID            string      `json:"id"`
+	TransactionID string      `json:"transaction_id"`
+	Kind          AnomalyKind `json:"kind"`
+	Date          time.Time   `json:"date"`
+	Category      string      `json:"category"`
+	Description   string      `json:"description"`
+	Amount        float64     `json:"amount"`
+	Observed      float64     `json:"observed,omitempty"`
+	Expected      float64     `json:"expected,omitempty"`
+	DetectedAt    time.Time   `json:"detected_at"`
+	Dismissed     bool        `json:"dismissed,omitempty"`
+}
@@ -10,11 +10,14 @@ import (
 type CategoryType string
 
 const (
-	Income   CategoryType = "income"
-	Expense  CategoryType = "expense"
-	Filename              = "internal/data/categories.json"
+	Income  CategoryType = "income"
+	Expense CategoryType = "expense"
 )
 
+// Filename is the categories file of the active profile; the app points it
+// at the configured data directory on startup.
+var Filename = "internal/data/categories.json"
+
 type Category struct {
 	ID       string `json:"id"`
 	Name     string `json:"name"`
@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+type DebtDirection string
+
+const (
+	// DebtLent is money we lent out: the counterparty owes us.
+	DebtLent DebtDirection = "lent"
+	// DebtBorrowed is a loan we took: we owe the counterparty.
+	DebtBorrowed DebtDirection = "borrowed"
+)
+
+// Debt is a loan between us and a counterparty. Rate is the annual interest
+// rate in percent; TermMonths of zero means the loan has no repayment
+// schedule. Payments are the IDs of transactions that repay it: incomes for
+// lent money, expenses for borrowed money.
+type Debt struct {
+	ID           string        `json:"id"`
+	Name         string        `json:"name"`
+	Counterparty string        `json:"counterparty"`
+	Direction    DebtDirection `json:"direction"`
+	Principal    float64       `json:"principal"`
+	Rate         float64       `json:"rate"`
+	TermMonths   int           `json:"term_months"`
+	Start        time.Time     `json:"start"`
+	Category     string        `json:"category"`
+	Payments     []string      `json:"payments,omitempty"`
+	CreatedAt    time.Time     `json:"created_at"`
+}
+
+// AmortizationRow is one scheduled payment of an annuity loan.
+type AmortizationRow struct {
+	N         int       `json:"n"`
+	Date      time.Time `json:"date"`
+	Payment   float64   `json:"payment"`
+	Interest  float64   `json:"interest"`
+	Principal float64   `json:"principal"`
+	Balance   float64   `json:"balance"`
+}
+
+// DebtPayment is a linked transaction split into interest and principal.
+type DebtPayment struct {
+	TransactionID string    `json:"transaction_id"`
+	Date          time.Time `json:"date"`
+	Amount        float64   `json:"amount"`
+	Interest      float64   `json:"interest"`
+	Principal     float64   `json:"principal"`
+	Balance       float64   `json:"balance"`
+}
+
+// DebtStatus is the state of a debt at a given moment.
+type DebtStatus struct {
+	Debt           Debt          `json:"debt"`
+	Payments       []DebtPayment `json:"payments"`
+	Balance        float64       `json:"balance"`
+	PrincipalPaid  float64       `json:"principal_paid"`
+	InterestPaid   float64       `json:"interest_paid"`
+	MonthlyPayment float64       `json:"monthly_payment"`
+	NextDue        time.Time     `json:"next_due,omitempty"`
+	PaidOff        bool          `json:"paid_off"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// EnvelopeAllocation assigns income to the envelope of an expense category
+// for a month (YYYY-MM); a negative amount takes money back out.
+type EnvelopeAllocation struct {
+	ID        string    `json:"id"`
+	Month     string    `json:"month"`
+	Category  string    `json:"category"`
+	Amount    float64   `json:"amount"`
+	Note      string    `json:"note,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Envelope is the state of one category envelope in a month.
+type Envelope struct {
+	Category  string  `json:"category"`
+	Rollover  float64 `json:"rollover"`
+	Assigned  float64 `json:"assigned"`
+	Spent     float64 `json:"spent"`
+	Available float64 `json:"available"`
+}
+
+// EnvelopeMonth is the envelope budget of a month. ToBeAssigned is the balance
+// before budgeting started plus the income received since, up to the end of
+// the month, minus everything assigned up to it.
+type EnvelopeMonth struct {
+	Month        string     `json:"month"`
+	Income       float64    `json:"income"`
+	Assigned     float64    `json:"assigned"`
+	Spent        float64    `json:"spent"`
+	Available    float64    `json:"available"`
+	ToBeAssigned float64    `json:"to_be_assigned"`
+	Envelopes    []Envelope `json:"envelopes"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+type EventType string
+
+const (
+	EventTransactionAdded   EventType = "TransactionAdded"
+	EventTransactionAmended EventType = "TransactionAmended"
+	EventTransactionRemoved EventType = "TransactionRemoved"
+	EventCategoryAdded      EventType = "CategoryAdded"
+	EventCategoryRemoved    EventType = "CategoryRemoved"
+	EventCategoryRenamed    EventType = "CategoryRenamed"
+)
+
+// Event is one fact in the ledger's event stream. Only the fields relevant to
+// the event type are set: the full transaction for added and amended ones,
+// the category for CategoryAdded, the ID or names otherwise.
+type Event struct {
+	Seq         int          `json:"seq"`
+	Time        time.Time    `json:"time"`
+	Type        EventType    `json:"type"`
+	Transaction *Transaction `json:"transaction,omitempty"`
+	Category    *Category    `json:"category,omitempty"`
+	ID          string       `json:"id,omitempty"`
+	OldName     string       `json:"old_name,omitempty"`
+	NewName     string       `json:"new_name,omitempty"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// GoalAllocation is money explicitly set aside for a goal.
+type GoalAllocation struct {
+	Date   time.Time `json:"date"`
+	Amount float64   `json:"amount"`
+	Note   string    `json:"note,omitempty"`
+}
+
+// Goal is a savings target. Contributions come from explicit allocations and
+// from transactions of the linked category or tagged with #Tag: expenses put
+// money aside, incomes take it back.
+type Goal struct {
+	ID          string           `json:"id"`
+	Name        string           `json:"name"`
+	Target      float64          `json:"target"`
+	Deadline    time.Time        `json:"deadline,omitempty"`
+	Category    string           `json:"category,omitempty"`
+	Tag         string           `json:"tag,omitempty"`
+	Allocations []GoalAllocation `json:"allocations,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+}
+
+// GoalContribution is one movement counted toward a goal.
+type GoalContribution struct {
+	Date        time.Time `json:"date"`
+	Amount      float64   `json:"amount"`
+	Description string    `json:"description"`
+}
+
+// GoalProgress is the state of a goal at a given moment.
+type GoalProgress struct {
+	Goal          Goal               `json:"goal"`
+	Saved         float64            `json:"saved"`
+	Remaining     float64            `json:"remaining"`
+	Fraction      float64            `json:"fraction"`
+	MonthlyRate   float64            `json:"monthly_rate"`
+	MonthlyNeeded float64            `json:"monthly_needed"`
+	Projected     time.Time          `json:"projected,omitempty"`
+	OnTrack       bool               `json:"on_track"`
+	Contributions []GoalContribution `json:"contributions"`
+}
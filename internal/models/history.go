@@ -0,0 +1,30 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Change is one mutation of the ledger with snapshots of the entity before and
+// after it; undo applies it backwards, redo forwards.
+type Change struct {
+	Action AuditAction     `json:"action"`
+	Entity string          `json:"entity"`
+	ID     string          `json:"id"`
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+}
+
+// HistoryEntry is a single undoable step. A service call that changes
+// several records is one entry with many changes.
+type HistoryEntry struct {
+	Time    time.Time `json:"time"`
+	Label   string    `json:"label"`
+	Changes []Change  `json:"changes"`
+}
+
+// History holds the undo and redo stacks, the most recent entry last.
+type History struct {
+	Undo []HistoryEntry `json:"undo"`
+	Redo []HistoryEntry `json:"redo"`
+}
@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+type AssetKind string
+
+const (
+	AssetKindAsset     AssetKind = "asset"
+	AssetKindLiability AssetKind = "liability"
+)
+
+// Valuation is the value of an asset or liability as of a date.
+type Valuation struct {
+	Date  time.Time `json:"date"`
+	Value float64   `json:"value"`
+}
+
+// Asset is something valued by hand, such as a car, real estate or a
+// liability not tracked as a debt. Its value at a date is the latest
+// valuation made on or before it.
+type Asset struct {
+	ID         string      `json:"id"`
+	Name       string      `json:"name"`
+	Kind       AssetKind   `json:"kind"`
+	Valuations []Valuation `json:"valuations"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+// NetWorthLine is one item of a net worth statement; liabilities have
+// positive values and are subtracted.
+type NetWorthLine struct {
+	Name  string    `json:"name"`
+	Kind  AssetKind `json:"kind"`
+	Value float64   `json:"value"`
+}
+
+// NetWorth combines the account balance, valued assets and debts at a date.
+type NetWorth struct {
+	Date        time.Time      `json:"date"`
+	Cash        float64        `json:"cash"`
+	Assets      float64        `json:"assets"`
+	Liabilities float64        `json:"liabilities"`
+	Total       float64        `json:"total"`
+	Lines       []NetWorthLine `json:"lines"`
+}
+
+// NetWorthSnapshot is the net worth recorded for a calendar month.
+type NetWorthSnapshot struct {
+	Month       string    `json:"month"`
+	TakenAt     time.Time `json:"taken_at"`
+	Cash        float64   `json:"cash"`
+	Assets      float64   `json:"assets"`
+	Liabilities float64   `json:"liabilities"`
+	Total       float64   `json:"total"`
+}
+
+// NetWorthData is everything kept in the net worth file.
+type NetWorthData struct {
+	Assets    []Asset            `json:"assets"`
+	Snapshots []NetWorthSnapshot `json:"snapshots"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Payee is a merchant or person money is paid to or received from. A
+// transaction belongs to the payee when its description matches the name or
+// one of the aliases; Category is used for new transactions of the payee.
+type Payee struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Aliases   []string  `json:"aliases,omitempty"`
+	Category  string    `json:"category,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PayeeStat sums the expenses of one payee. Known is false for descriptions
+// that match no payee; they are grouped by their normalized spelling.
+type PayeeStat struct {
+	Payee    string    `json:"payee"`
+	Known    bool      `json:"known"`
+	Category string    `json:"category"`
+	Spent    float64   `json:"spent"`
+	Count    int       `json:"count"`
+	Average  float64   `json:"average"`
+	Last     time.Time `json:"last"`
+}
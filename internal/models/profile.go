@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+const DefaultProfile = "default"
+
+// Profile is a named ledger with its own storage directory.
+type Profile struct {
+	Name         string    `json:"name"`
+	DataDir      string    `json:"data_dir"`
+	PasswordHash string    `json:"password_hash,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// HasPassword reports whether access to the profile through the server requires a password.
+func (p Profile) HasPassword() bool {
+	return p.PasswordHash != ""
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// QuickEntry is a transaction parsed from one line of free text such as
+// "кофе 250 вчера #работа". Category is empty when it could not be guessed;
+// Payee is set when the description names a known payee.
+type QuickEntry struct {
+	Input       string          `json:"input"`
+	Amount      float64         `json:"amount"`
+	Type        TransactionType `json:"type"`
+	Date        time.Time       `json:"date"`
+	Description string          `json:"description"`
+	Tags        []string        `json:"tags,omitempty"`
+	Payee       string          `json:"payee,omitempty"`
+	Category    string          `json:"category"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// Receipt is the data of a Russian fiscal receipt QR code: FN is the fiscal
+// drive number, FD the fiscal document number, FP its fiscal sign and
+// Operation the calculation type (1 — приход, 2 — возврат прихода,
+// 3 — расход, 4 — возврат расхода). TransactionID is the transaction
+// created from the receipt.
+type Receipt struct {
+	ID            string    `json:"id"`
+	Time          time.Time `json:"time"`
+	Amount        float64   `json:"amount"`
+	FN            string    `json:"fn"`
+	FD            string    `json:"fd"`
+	FP            string    `json:"fp"`
+	Operation     int       `json:"operation"`
+	Raw           string    `json:"raw"`
+	TransactionID string    `json:"transaction_id,omitempty"`
+	CreatedAt     time.Time `json:"created_at,omitempty"`
+}
@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+type Frequency string
+
+const (
+	FrequencyWeekly  Frequency = "weekly"
+	FrequencyMonthly Frequency = "monthly"
+	FrequencyYearly  Frequency = "yearly"
+)
+
+// RecurringItem is a known income or expense that repeats with a fixed
+// frequency, starting at Start and, when Until is set, ending on it.
+type RecurringItem struct {
+	ID          string          `json:"id"`
+	Description string          `json:"description"`
+	Amount      float64         `json:"amount"`
+	Type        TransactionType `json:"type"`
+	Category    string          `json:"category"`
+	Frequency   Frequency       `json:"frequency"`
+	Start       time.Time       `json:"start"`
+	Until       time.Time       `json:"until,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+// ForecastEntry is one amount expected on a forecast day.
+type ForecastEntry struct {
+	Description string          `json:"description"`
+	Category    string          `json:"category"`
+	Type        TransactionType `json:"type"`
+	Amount      float64         `json:"amount"`
+	Recurring   bool            `json:"recurring"`
+}
+
+// ForecastDay is the projected movement and closing balance of one day.
+type ForecastDay struct {
+	Date     time.Time       `json:"date"`
+	Income   float64         `json:"income"`
+	Expense  float64         `json:"expense"`
+	Balance  float64         `json:"balance"`
+	Negative bool            `json:"negative"`
+	Entries  []ForecastEntry `json:"entries"`
+}
+
+// CategoryRate is the average daily discretionary spending in a category.
+type CategoryRate struct {
+	Category string  `json:"category"`
+	Daily    float64 `json:"daily"`
+}
+
+// Forecast projects the balance day by day from the current one.
+type Forecast struct {
+	Start         time.Time      `json:"start"`
+	Balance       float64        `json:"balance"`
+	LookbackDays  int            `json:"lookback_days"`
+	Discretionary []CategoryRate `json:"discretionary"`
+	Days          []ForecastDay  `json:"days"`
+	Lowest        ForecastDay    `json:"lowest"`
+	NegativeDays  int            `json:"negative_days"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+type Period string
+
+const (
+	PeriodMonth   Period = "month"
+	PeriodQuarter Period = "quarter"
+	PeriodYear    Period = "year"
+)
+
+// CategoryTotal is the sum of transactions of one category within a period.
+type CategoryTotal struct {
+	Category string          `json:"category"`
+	Type     TransactionType `json:"type"`
+	Total    float64         `json:"total"`
+	Count    int             `json:"count"`
+	Share    float64         `json:"share"`
+}
+
+// PeriodSummary aggregates transactions of a single month, quarter or year.
+type PeriodSummary struct {
+	Period          Period          `json:"period"`
+	Label           string          `json:"label"`
+	Start           time.Time       `json:"start"`
+	End             time.Time       `json:"end"`
+	Income          float64         `json:"income"`
+	Expense         float64         `json:"expense"`
+	Balance         float64         `json:"balance"`
+	Count           int             `json:"count"`
+	AvgDailyExpense float64         `json:"avg_daily_expense"`
+	AvgExpense      float64         `json:"avg_expense"`
+	SavingsRate     float64         `json:"savings_rate"`
+	Categories      []CategoryTotal `json:"categories"`
+
+	HasPrevious  bool    `json:"has_previous"`
+	IncomeDelta  float64 `json:"income_delta"`
+	ExpenseDelta float64 `json:"expense_delta"`
+	BalanceDelta float64 `json:"balance_delta"`
+}
+
+// DailyTotal is the sum of income and expense of one calendar day.
+type DailyTotal struct {
+	Date    time.Time `json:"date"`
+	Income  float64   `json:"income"`
+	Expense float64   `json:"expense"`
+}
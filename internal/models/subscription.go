@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// Subscription is a periodic charge found in the transaction history.
+type Subscription struct {
+	Description  string    `json:"description"`
+	Category     string    `json:"category"`
+	Frequency    Frequency `json:"frequency"`
+	Amount       float64   `json:"amount"`
+	Occurrences  int       `json:"occurrences"`
+	First        time.Time `json:"first"`
+	Last         time.Time `json:"last"`
+	Next         time.Time `json:"next"`
+	AnnualCost   float64   `json:"annual_cost"`
+	Lapsed       bool      `json:"lapsed"`
+	Tracked      bool      `json:"tracked"`
+	Transactions []string  `json:"transactions"`
+}
@@ -16,4 +16,14 @@ type Transaction struct {
 	Description string          `json:"description"`
 	Type        TransactionType `json:"type"`
 	Date        time.Time       `json:"date"`
+	Attachments []Attachment    `json:"attachments,omitempty"`
+}
+
+// Attachment is a file kept with a transaction, such as a receipt photo or a
+// warranty PDF. The content is stored once under its SHA-256 Hash.
+type Attachment struct {
+	Hash    string    `json:"hash"`
+	Name    string    `json:"name"`
+	Size    int64     `json:"size"`
+	AddedAt time.Time `json:"added_at"`
 }
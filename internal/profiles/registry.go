@@ -0,0 +1,208 @@
+package profiles
+
+import (
+	"encoding/json"
+	"errors"
+	"fintrack/internal/models"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	registryFile     = "profiles.json"
+	profilesDir      = "profiles"
+	transactionsFile = "transactions.json"
+	categoriesFile   = "categories.json"
+)
+
+var (
+	ErrProfileNotFound = errors.New("профиль не найден")
+	ErrProfileExists   = errors.New("профиль уже существует")
+
+	validName = regexp.MustCompile(`^[\p{L}\p{N}_-]{1,32}$`)
+)
+
+type registryData struct {
+	Current  string           `json:"current"`
+	Profiles []models.Profile `json:"profiles"`
+}
+
+// Registry keeps the list of profiles and the active one in <baseDir>/profiles.json.
+// The default profile stores its files directly in baseDir, so existing ledgers keep working.
+type Registry struct {
+	baseDir string
+	mu      sync.Mutex
+	data    registryData
+}
+
+// Open loads the registry from baseDir, creating it with the default profile if missing.
+func Open(baseDir string) (*Registry, error) {
+	r := &Registry{baseDir: baseDir}
+
+	data, err := os.ReadFile(r.path())
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, &r.data); err != nil {
+			return nil, fmt.Errorf("ошибка чтения списка профилей: %w", err)
+		}
+	case os.IsNotExist(err):
+	default:
+		return nil, fmt.Errorf("ошибка чтения списка профилей: %w", err)
+	}
+
+	if _, ok := r.find(models.DefaultProfile); !ok {
+		r.data.Profiles = append(r.data.Profiles, models.Profile{
+			Name:      models.DefaultProfile,
+			DataDir:   ".",
+			CreatedAt: time.Now(),
+		})
+	}
+	if _, ok := r.find(r.data.Current); !ok {
+		r.data.Current = models.DefaultProfile
+	}
+
+	return r, nil
+}
+
+func (r *Registry) path() string {
+	return filepath.Join(r.baseDir, registryFile)
+}
+
+func (r *Registry) find(name string) (int, bool) {
+	for i, p := range r.data.Profiles {
+		if p.Name == name {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+func (r *Registry) save() error {
+	if err := os.MkdirAll(r.baseDir, 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(r.data, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(r.path(), data, 0600)
+}
+
+// List returns all profiles sorted by name.
+func (r *Registry) List() []models.Profile {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	list := append([]models.Profile(nil), r.data.Profiles...)
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+func (r *Registry) Get(name string) (models.Profile, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, ok := r.find(name)
+	if !ok {
+		return models.Profile{}, fmt.Errorf("%w: %s", ErrProfileNotFound, name)
+	}
+	return r.data.Profiles[i], nil
+}
+
+// Current returns the profile that was selected last.
+func (r *Registry) Current() models.Profile {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, _ := r.find(r.data.Current)
+	return r.data.Profiles[i]
+}
+
+func (r *Registry) Create(name string) (models.Profile, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !validName.MatchString(name) {
+		return models.Profile{}, fmt.Errorf("некорректное имя профиля: допустимы буквы, цифры, '-' и '_' (до 32 символов)")
+	}
+	if _, ok := r.find(name); ok {
+		return models.Profile{}, fmt.Errorf("%w: %s", ErrProfileExists, name)
+	}
+
+	profile := models.Profile{
+		Name:      name,
+		DataDir:   filepath.Join(profilesDir, name),
+		CreatedAt: time.Now(),
+	}
+	r.data.Profiles = append(r.data.Profiles, profile)
+
+	if err := r.save(); err != nil {
+		r.data.Profiles = r.data.Profiles[:len(r.data.Profiles)-1]
+		return models.Profile{}, err
+	}
+	return profile, nil
+}
+
+// SetCurrent makes the profile the one opened by default on the next start.
+func (r *Registry) SetCurrent(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.find(name); !ok {
+		return fmt.Errorf("%w: %s", ErrProfileNotFound, name)
+	}
+	r.data.Current = name
+	return r.save()
+}
+
+// SetPassword protects the profile in server mode; an empty password removes protection.
+func (r *Registry) SetPassword(name, password string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, ok := r.find(name)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrProfileNotFound, name)
+	}
+
+	if password == "" {
+		r.data.Profiles[i].PasswordHash = ""
+		return r.save()
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("ошибка хеширования пароля: %w", err)
+	}
+	r.data.Profiles[i].PasswordHash = string(hash)
+	return r.save()
+}
+
+// CheckPassword reports whether password opens the profile. Profiles without a password accept any.
+func CheckPassword(profile models.Profile, password string) bool {
+	if !profile.HasPassword() {
+		return true
+	}
+	return bcrypt.CompareHashAndPassword([]byte(profile.PasswordHash), []byte(password)) == nil
+}
+
+// Dir returns the absolute or base-relative directory holding the profile's files.
+func (r *Registry) Dir(profile models.Profile) string {
+	if filepath.IsAbs(profile.DataDir) {
+		return profile.DataDir
+	}
+	return filepath.Join(r.baseDir, profile.DataDir)
+}
+
+// Files returns the transaction and category files of the profile.
+func (r *Registry) Files(profile models.Profile) (transactions, categories string) {
+	dir := r.Dir(profile)
+	return filepath.Join(dir, transactionsFile), filepath.Join(dir, categoriesFile)
+}
@@ -0,0 +1,261 @@
+package services
+
+import (
+	"fintrack/internal/models"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// category spending is summed over windows of this many days and the
+	// last window is compared with the average of the previous ones
+	anomalyWindowDays = 30
+	anomalyBaseWindow = 3
+	anomalySpikeRatio = 1.5
+	// an expense is large when it exceeds the category mean by this many
+	// standard deviations and twice the category median
+	anomalyLargeSigmas = 3
+	anomalyMinSamples  = 5
+	// new merchants are only reported once the history has this many expenses
+	anomalyMinHistory = 10
+)
+
+// AnomalyStore persists flagged transactions.
+type AnomalyStore interface {
+	Load() ([]models.Anomaly, error)
+	Save(anomalies []models.Anomaly) error
+}
+
+// AnomalyService compares expenses with the history before them and flags
+// category spikes, unusually large expenses and first-time merchants. It
+// observes the transaction service, so every added transaction is checked.
+type AnomalyService struct {
+	store              AnomalyStore
+	transactionService *TransactionService
+	mu                 sync.Mutex
+}
+
+func NewAnomalyService(store AnomalyStore, transactionService *TransactionService) *AnomalyService {
+	return &AnomalyService{
+		store:              store,
+		transactionService: transactionService,
+	}
+}
+
+// TransactionAdded checks a newly added transaction against the earlier ones.
+func (as *AnomalyService) TransactionAdded(transaction models.Transaction) error {
+	transactions, err := as.transactionService.GetAllTransactions()
+	if err != nil {
+		return err
+	}
+	var history []models.Transaction
+	for _, t := range transactions {
+		if t.ID != transaction.ID && transactionBefore(t, transaction) {
+			history = append(history, t)
+		}
+	}
+	_, err = as.save(detectAnomalies(transaction, history, time.Now()))
+	return err
+}
+
+// Analyze checks the whole history, each transaction against those before
+// it, and returns the anomalies that were not flagged yet.
+func (as *AnomalyService) Analyze() ([]models.Anomaly, error) {
+	transactions, err := as.transactionService.GetAllTransactions()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(transactions, func(i, j int) bool {
+		return transactionBefore(transactions[i], transactions[j])
+	})
+
+	now := time.Now()
+	var found []models.Anomaly
+	for i, t := range transactions {
+		found = append(found, detectAnomalies(t, transactions[:i], now)...)
+	}
+	return as.save(found)
+}
+
+// Attention returns the flagged transactions that still exist, newest
+// first; dismissed ones are included only when all is set.
+func (as *AnomalyService) Attention(all bool) ([]models.Anomaly, error) {
+	anomalies, err := as.store.Load()
+	if err != nil {
+		return nil, err
+	}
+	transactions, err := as.transactionService.GetAllTransactions()
+	if err != nil {
+		return nil, err
+	}
+	exists := make(map[string]bool, len(transactions))
+	for _, t := range transactions {
+		exists[t.ID] = true
+	}
+
+	result := []models.Anomaly{}
+	for _, a := range anomalies {
+		if exists[a.TransactionID] && (all || !a.Dismissed) {
+			result = append(result, a)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Date.After(result[j].Date)
+	})
+	return result, nil
+}
+
+// Dismiss marks the anomalies with the given IDs as reviewed.
+func (as *AnomalyService) Dismiss(ids ...string) error {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	anomalies, err := as.store.Load()
+	if err != nil {
+		return err
+	}
+	dismiss := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		dismiss[id] = true
+	}
+	for i := range anomalies {
+		if dismiss[anomalies[i].ID] {
+			anomalies[i].Dismissed = true
+		}
+	}
+	return as.store.Save(anomalies)
+}
+
+// save stores the anomalies not flagged before and returns them. Anomaly IDs
+// are derived from the transaction and the kind, so a dismissed anomaly is
+// not raised again by a later analysis.
+func (as *AnomalyService) save(found []models.Anomaly) ([]models.Anomaly, error) {
+	if len(found) == 0 {
+		return []models.Anomaly{}, nil
+	}
+
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	anomalies, err := as.store.Load()
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool, len(anomalies))
+	for _, a := range anomalies {
+		known[a.ID] = true
+	}
+
+	added := []models.Anomaly{}
+	for _, a := range found {
+		if !known[a.ID] {
+			known[a.ID] = true
+			added = append(added, a)
+		}
+	}
+	if len(added) == 0 {
+		return added, nil
+	}
+	return added, as.store.Save(append(anomalies, added...))
+}
+
+// detectAnomalies checks an expense against the transactions made before it.
+func detectAnomalies(t models.Transaction, history []models.Transaction, now time.Time) []models.Anomaly {
+	if t.Type != models.TransactionExpense {
+		return nil
+	}
+
+	flag := func(kind models.AnomalyKind, observed, expected float64) models.Anomaly {
+		return models.Anomaly{
+			ID:            t.ID + "_" + string(kind),
+			TransactionID: t.ID,
+			Kind:          kind,
+			Date:          t.Date,
+			Category:      t.Category,
+			Description:   t.Description,
+			Amount:        t.Amount,
+			Observed:      roundMoney(observed),
+			Expected:      roundMoney(expected),
+			DetectedAt:    now,
+		}
+	}
+
+	var (
+		result    []models.Anomaly
+		amounts   []float64
+		expenses  int
+		seen      bool
+		earliest  = t.Date
+		merchant  = normalizeDescription(t.Description)
+		window    = time.Duration(anomalyWindowDays) * 24 * time.Hour
+		baseStart = t.Date.Add(-window * (anomalyBaseWindow + 1))
+		current   = t.Amount
+		base      float64
+		active    = map[int]bool{}
+	)
+	for _, h := range history {
+		if h.Date.Before(earliest) {
+			earliest = h.Date
+		}
+		if h.Type != models.TransactionExpense {
+			continue
+		}
+		expenses++
+		if merchant != "" && normalizeDescription(h.Description) == merchant {
+			seen = true
+		}
+		if !strings.EqualFold(h.Category, t.Category) {
+			continue
+		}
+		amounts = append(amounts, h.Amount)
+		switch {
+		case h.Date.After(t.Date.Add(-window)):
+			current += h.Amount
+		case h.Date.After(baseStart):
+			base += h.Amount
+			active[int(t.Date.Sub(h.Date)/window)] = true
+		}
+	}
+
+	// the spike is reported by the expense that pushes the category over
+	// the threshold, and only when the history covers all base windows and
+	// the category was spent on in most of them
+	average := base / anomalyBaseWindow
+	threshold := average * anomalySpikeRatio
+	if len(active) > anomalyBaseWindow/2 && !earliest.After(baseStart.Add(window)) && current > threshold && current-t.Amount <= threshold {
+		result = append(result, flag(models.AnomalyCategorySpike, current, average))
+	}
+
+	if len(amounts) >= anomalyMinSamples {
+		var mean, variance float64
+		for _, a := range amounts {
+			mean += a
+		}
+		mean /= float64(len(amounts))
+		for _, a := range amounts {
+			variance += (a - mean) * (a - mean)
+		}
+		stddev := math.Sqrt(variance / float64(len(amounts)))
+		usual := median(amounts)
+		if t.Amount > mean+anomalyLargeSigmas*stddev && t.Amount > 2*usual {
+			result = append(result, flag(models.AnomalyLargeExpense, t.Amount, usual))
+		}
+	}
+
+	if merchant != "" && !seen && expenses >= anomalyMinHistory {
+		result = append(result, flag(models.AnomalyNewMerchant, t.Amount, 0))
+	}
+	return result
+}
+
+// transactionBefore orders transactions by date and then by ID, which
+// grows with the creation time.
+func transactionBefore(a, b models.Transaction) bool {
+	if !a.Date.Equal(b.Date) {
+		return a.Date.Before(b.Date)
+	}
+	return a.ID < b.ID
+}
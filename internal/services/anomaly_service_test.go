@@ -0,0 +1,143 @@
+package services
+
+import (
+	"fintrack/internal/models"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func expense(id, category, description string, amount float64, d time.Time) models.Transaction {
+	return models.Transaction{ID: id, Category: category, Description: description, Amount: amount, Type: models.TransactionExpense, Date: d}
+}
+
+func TestDetectAnomalies(t *testing.T) {
+	now := date(2025, time.May, 1)
+
+	// weekly groceries for four months, alternating 500 and 1500: 4000 in the
+	// last 30 days and 12500 in the three windows before them
+	var groceries []models.Transaction
+	for i := 1; i <= 17; i++ {
+		amount := 500.0
+		if i%2 == 0 {
+			amount = 1500
+		}
+		groceries = append(groceries, expense("g"+strconv.Itoa(i), "Продукты", "Магазин", amount, now.AddDate(0, 0, -7*i)))
+	}
+	with := func(extra ...models.Transaction) []models.Transaction {
+		return append(append([]models.Transaction(nil), groceries...), extra...)
+	}
+
+	// a few recent coffees, too short a history for a spike
+	var coffee []models.Transaction
+	for i := 1; i <= 5; i++ {
+		coffee = append(coffee, expense("c"+strconv.Itoa(i), "Кафе", "Кофейня", 100, now.AddDate(0, 0, -i)))
+	}
+
+	tests := []struct {
+		name    string
+		t       models.Transaction
+		history []models.Transaction
+		want    []models.AnomalyKind
+	}{
+		{
+			name:    "usual expense",
+			t:       expense("t", "Продукты", "Магазин", 1500, now),
+			history: groceries,
+		},
+		{
+			name:    "expense that pushes the category over the threshold",
+			t:       expense("t", "Продукты", "Магазин", 2300, now),
+			history: groceries,
+			want:    []models.AnomalyKind{models.AnomalyCategorySpike},
+		},
+		{
+			name:    "categories are compared without case",
+			t:       expense("t", "продукты", "Магазин", 2300, now),
+			history: groceries,
+			want:    []models.AnomalyKind{models.AnomalyCategorySpike},
+		},
+		{
+			name:    "category already over the threshold",
+			t:       expense("t", "Продукты", "Магазин", 500, now),
+			history: with(expense("x", "Продукты", "Магазин", 3000, now.AddDate(0, 0, -2))),
+		},
+		{
+			name:    "spike and large expense",
+			t:       expense("t", "Продукты", "Магазин", 5000, now),
+			history: groceries,
+			want:    []models.AnomalyKind{models.AnomalyCategorySpike, models.AnomalyLargeExpense},
+		},
+		{
+			name:    "large expense without a long history",
+			t:       expense("t", "Кафе", "Кофейня", 1000, now),
+			history: coffee,
+			want:    []models.AnomalyKind{models.AnomalyLargeExpense},
+		},
+		{
+			name:    "too few samples for a large expense",
+			t:       expense("t", "Кафе", "Кофейня", 1000, now),
+			history: coffee[:4],
+		},
+		{
+			name:    "new merchant",
+			t:       expense("t", "Продукты", "Рынок 12", 500, now),
+			history: groceries,
+			want:    []models.AnomalyKind{models.AnomalyNewMerchant},
+		},
+		{
+			name:    "merchant seen with other digits",
+			t:       expense("t", "Продукты", "МАГАЗИН №5", 500, now),
+			history: groceries,
+		},
+		{
+			name:    "new merchant with a short history",
+			t:       expense("t", "Кафе", "Пекарня", 100, now),
+			history: coffee,
+		},
+		{
+			name:    "no description",
+			t:       expense("t", "Продукты", "", 500, now),
+			history: groceries,
+		},
+		{
+			name:    "income is not checked",
+			t:       models.Transaction{ID: "t", Category: "Продукты", Description: "Возврат", Amount: 9000, Type: models.TransactionIncome, Date: now},
+			history: groceries,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			anomalies := detectAnomalies(tt.t, tt.history, now)
+			var kinds []models.AnomalyKind
+			for _, a := range anomalies {
+				kinds = append(kinds, a.Kind)
+				if a.TransactionID != tt.t.ID || a.ID != tt.t.ID+"_"+string(a.Kind) {
+					t.Errorf("%s flags %s as %s", a.Kind, a.TransactionID, a.ID)
+				}
+			}
+			if !reflect.DeepEqual(kinds, tt.want) {
+				t.Errorf("kinds = %v, want %v", kinds, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectAnomaliesSpikeAmounts(t *testing.T) {
+	now := date(2025, time.May, 1)
+	var history []models.Transaction
+	for i := 1; i <= 4; i++ {
+		history = append(history, expense("h"+strconv.Itoa(i), "Такси", "", 300, now.AddDate(0, 0, -30*i+15)))
+	}
+
+	anomalies := detectAnomalies(expense("t", "Такси", "", 200, now), history, now)
+	if len(anomalies) != 1 {
+		t.Fatalf("%d anomalies, want 1", len(anomalies))
+	}
+	// 500 in the last 30 days against 300 a window before
+	if a := anomalies[0]; a.Observed != 500 || a.Expected != 300 {
+		t.Errorf("observed %.2f and expected %.2f, want 500 and 300", a.Observed, a.Expected)
+	}
+}
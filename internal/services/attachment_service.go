@@ -0,0 +1,208 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fintrack/internal/models"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrAttachmentNotFound is returned when a transaction has no such attachment.
+var ErrAttachmentNotFound = errors.New("вложение не найдено")
+
+// maxAttachmentSize limits attached files to what is sensible for a receipt or document.
+const maxAttachmentSize = 50 << 20
+
+// AttachmentContent stores attached files by the hash of their content.
+type AttachmentContent interface {
+	Put(content []byte) (string, error)
+	Get(hash string) ([]byte, error)
+	Remove(hash string) error
+	Hashes() ([]string, error)
+}
+
+// AttachmentService attaches files to transactions. The list of attachments
+// is part of the transaction, so attaching and detaching are audited and can
+// be undone like any other edit; the files themselves are only removed by
+// Cleanup once nothing refers to them.
+type AttachmentService struct {
+	content            AttachmentContent
+	transactionService *TransactionService
+	history            HistoryStore
+}
+
+// NewAttachmentService takes the history store rather than the History, so
+// files are kept for undo entries saved while undo was enabled.
+func NewAttachmentService(content AttachmentContent, transactionService *TransactionService, history HistoryStore) *AttachmentService {
+	return &AttachmentService{
+		content:            content,
+		transactionService: transactionService,
+		history:            history,
+	}
+}
+
+// Attach copies the file at path into the store and links it to the transaction.
+func (as *AttachmentService) Attach(transactionID, path string) (models.Attachment, error) {
+	transaction, err := as.transactionService.GetTransactionByID(transactionID)
+	if err != nil {
+		return models.Attachment{}, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return models.Attachment{}, validationError("файл не найден: %s", path)
+	}
+	if !info.Mode().IsRegular() {
+		return models.Attachment{}, validationError("%s не является файлом", path)
+	}
+	if info.Size() > maxAttachmentSize {
+		return models.Attachment{}, validationError("файл больше %d МБ", maxAttachmentSize>>20)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return models.Attachment{}, err
+	}
+
+	hash, err := as.content.Put(content)
+	if err != nil {
+		return models.Attachment{}, err
+	}
+	for _, a := range transaction.Attachments {
+		if a.Hash == hash {
+			return models.Attachment{}, validationError("файл уже приложен как %s", a.Name)
+		}
+	}
+
+	attachment := models.Attachment{
+		Hash:    hash,
+		Name:    filepath.Base(path),
+		Size:    int64(len(content)),
+		AddedAt: time.Now(),
+	}
+	attachments := append(append([]models.Attachment{}, transaction.Attachments...), attachment)
+	return attachment, as.transactionService.setAttachments(transaction, attachments)
+}
+
+// Detach unlinks an attachment, given by its 1-based number, name or hash,
+// from the transaction. The file stays in the store until Cleanup.
+func (as *AttachmentService) Detach(transactionID, ref string) (models.Attachment, error) {
+	transaction, err := as.transactionService.GetTransactionByID(transactionID)
+	if err != nil {
+		return models.Attachment{}, err
+	}
+	i := findAttachment(transaction.Attachments, ref)
+	if i < 0 {
+		return models.Attachment{}, ErrAttachmentNotFound
+	}
+
+	removed := transaction.Attachments[i]
+	attachments := append(append([]models.Attachment{}, transaction.Attachments[:i]...), transaction.Attachments[i+1:]...)
+	return removed, as.transactionService.setAttachments(transaction, attachments)
+}
+
+// Attachments returns the attachments of the transaction.
+func (as *AttachmentService) Attachments(transactionID string) ([]models.Attachment, error) {
+	transaction, err := as.transactionService.GetTransactionByID(transactionID)
+	if err != nil {
+		return nil, err
+	}
+	return transaction.Attachments, nil
+}
+
+// Find returns an attachment of the transaction by its 1-based number, name or hash.
+func (as *AttachmentService) Find(transactionID, ref string) (models.Attachment, error) {
+	attachments, err := as.Attachments(transactionID)
+	if err != nil {
+		return models.Attachment{}, err
+	}
+	i := findAttachment(attachments, ref)
+	if i < 0 {
+		return models.Attachment{}, ErrAttachmentNotFound
+	}
+	return attachments[i], nil
+}
+
+// Content returns the stored file of an attachment.
+func (as *AttachmentService) Content(attachment models.Attachment) ([]byte, error) {
+	return as.content.Get(attachment.Hash)
+}
+
+// Cleanup removes stored files that no transaction refers to, counting the
+// transactions kept in the undo and redo history, and returns their hashes.
+func (as *AttachmentService) Cleanup() ([]string, error) {
+	used, err := as.referenced()
+	if err != nil {
+		return nil, err
+	}
+	hashes, err := as.content.Hashes()
+	if err != nil {
+		return nil, err
+	}
+
+	removed := []string{}
+	for _, hash := range hashes {
+		if used[hash] {
+			continue
+		}
+		if err := as.content.Remove(hash); err != nil {
+			return removed, err
+		}
+		removed = append(removed, hash)
+	}
+	return removed, nil
+}
+
+func (as *AttachmentService) referenced() (map[string]bool, error) {
+	transactions, err := as.transactionService.GetAllTransactions()
+	if err != nil {
+		return nil, err
+	}
+	used := map[string]bool{}
+	for _, t := range transactions {
+		for _, a := range t.Attachments {
+			used[a.Hash] = true
+		}
+	}
+	if as.history == nil {
+		return used, nil
+	}
+
+	history, err := as.history.Load()
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range append(history.Undo, history.Redo...) {
+		for _, change := range entry.Changes {
+			if change.Entity != entityTransaction {
+				continue
+			}
+			for _, snapshot := range []json.RawMessage{change.Before, change.After} {
+				var t models.Transaction
+				if len(snapshot) == 0 || json.Unmarshal(snapshot, &t) != nil {
+					continue
+				}
+				for _, a := range t.Attachments {
+					used[a.Hash] = true
+				}
+			}
+		}
+	}
+	return used, nil
+}
+
+func findAttachment(attachments []models.Attachment, ref string) int {
+	ref = strings.TrimSpace(ref)
+	if n, err := strconv.Atoi(ref); err == nil && n >= 1 && n <= len(attachments) {
+		return n - 1
+	}
+	for i, a := range attachments {
+		if a.Hash == ref || strings.EqualFold(a.Name, ref) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"fintrack/internal/models"
+	"fmt"
+)
+
+// Auditor receives every mutation made through the services together with
+// snapshots of the entity before and after it.
+type Auditor interface {
+	Record(action models.AuditAction, entity, id string, before, after interface{}) error
+}
+
+// Snapshotter backs up the data before destructive operations.
+type Snapshotter interface {
+	Snapshot(reason string) error
+}
+
+const (
+	entityTransaction = "transaction"
+	entityCategory    = "category"
+)
+
+// mutationHooks is embedded by the services that change the ledger and
+// forwards each saved change to the audit log and the undo history.
+type mutationHooks struct {
+	auditor     Auditor
+	history     *History
+	snapshotter Snapshotter
+}
+
+// SetAuditor makes the service report every mutation to a.
+func (h *mutationHooks) SetAuditor(a Auditor) {
+	h.auditor = a
+}
+
+// SetHistory makes the service's mutations undoable through history.
+func (h *mutationHooks) SetHistory(history *History) {
+	h.history = history
+}
+
+// SetSnapshotter makes the service back up the data before deleting or renaming.
+func (h *mutationHooks) SetSnapshotter(s Snapshotter) {
+	h.snapshotter = s
+}
+
+// destructive is called before a change that removes or rewrites data.
+func (h *mutationHooks) destructive(reason string) error {
+	return takeSnapshot(h.snapshotter, reason)
+}
+
+// group records the mutations fn makes as one history step.
+func (h *mutationHooks) group(label string, fn func() error) error {
+	if h.history == nil {
+		return fn()
+	}
+	return h.history.Group(label, fn)
+}
+
+// changed is called after a mutation is saved, so errors say so explicitly.
+func (h *mutationHooks) changed(action models.AuditAction, entity, id string, before, after interface{}) error {
+	if err := record(h.auditor, action, entity, id, before, after); err != nil {
+		return err
+	}
+	if h.history == nil {
+		return nil
+	}
+	if err := h.history.push(action, entity, id, before, after); err != nil {
+		return fmt.Errorf("изменение сохранено, но не записано в историю действий: %w", err)
+	}
+	return nil
+}
+
+func record(auditor Auditor, action models.AuditAction, entity, id string, before, after interface{}) error {
+	if auditor == nil {
+		return nil
+	}
+	if err := auditor.Record(action, entity, id, before, after); err != nil {
+		return fmt.Errorf("изменение сохранено, но не записано в журнал аудита: %w", err)
+	}
+	return nil
+}
+
+func takeSnapshot(s Snapshotter, reason string) error {
+	if s == nil {
+		return nil
+	}
+	return s.Snapshot(reason)
+}
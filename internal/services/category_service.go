@@ -3,10 +3,20 @@ package services
 import (
 	"fintrack/internal/models"
 	"fintrack/internal/storage"
+	"fmt"
+	"strings"
 )
 
+// CategoryObserver keeps data that refers to categories by name in step
+// with renames, including renames made by undo and redo.
+type CategoryObserver interface {
+	CategoryRenamed(oldName, newName string) error
+}
+
 type CategoryService struct {
 	storage storage.Storage
+	mutationHooks
+	observers []CategoryObserver
 }
 
 func NewCategoryService(storage storage.Storage) *CategoryService {
@@ -15,6 +25,11 @@ func NewCategoryService(storage storage.Storage) *CategoryService {
 	}
 }
 
+// Observe makes the service tell o about every renamed category.
+func (cs *CategoryService) Observe(o CategoryObserver) {
+	cs.observers = append(cs.observers, o)
+}
+
 // GetCategoriesByType returns categories filtered by income/expense type.
 func (cs *CategoryService) GetCategoriesByType(isIncome bool) ([]models.Category, error) {
 	allCategories, err := cs.storage.GetCategories()
@@ -37,5 +52,82 @@ func (cs *CategoryService) AddCategory(name string, isIncome bool) error {
 		IsIncome: isIncome,
 		Type:     map[bool]string{true: "income", false: "expense"}[isIncome],
 	}
-	return cs.storage.SaveCategory(category)
+	if err := cs.storage.SaveCategory(category); err != nil {
+		return err
+	}
+	return cs.changed(models.AuditCreate, entityCategory, category.Name, nil, category)
+}
+
+// RenameCategory renames a category; transactions filed under it follow the new name.
+func (cs *CategoryService) RenameCategory(oldName, newName string) (models.Category, error) {
+	newName = strings.TrimSpace(newName)
+	if newName == "" {
+		return models.Category{}, validationError("название не должно быть пустым")
+	}
+
+	categories, err := cs.storage.GetCategories()
+	if err != nil {
+		return models.Category{}, err
+	}
+
+	var existing *models.Category
+	for i := range categories {
+		switch {
+		case strings.EqualFold(categories[i].Name, oldName):
+			existing = &categories[i]
+		case strings.EqualFold(categories[i].Name, newName):
+			return models.Category{}, validationError("категория %s уже существует", categories[i].Name)
+		}
+	}
+	if existing == nil {
+		return models.Category{}, storage.ErrCategoryNotFound
+	}
+	if existing.Edit {
+		return models.Category{}, validationError("системные категории нельзя изменять")
+	}
+
+	if err := cs.destructive("переименование категории " + existing.Name); err != nil {
+		return models.Category{}, err
+	}
+
+	renamed := *existing
+	renamed.Name = newName
+	if err := cs.storage.RenameCategory(existing.Name, newName); err != nil {
+		return models.Category{}, err
+	}
+	if err := cs.changed(models.AuditUpdate, entityCategory, existing.Name, *existing, renamed); err != nil {
+		return renamed, err
+	}
+	return renamed, notifyCategoryRenamed(cs.observers, existing.Name, newName)
+}
+
+func notifyCategoryRenamed(observers []CategoryObserver, oldName, newName string) error {
+	for _, o := range observers {
+		if err := o.CategoryRenamed(oldName, newName); err != nil {
+			return fmt.Errorf("категория переименована, но не везде: %w", err)
+		}
+	}
+	return nil
+}
+
+// renameCategoryIn moves the items of a store filed under oldName to newName.
+func renameCategoryIn[T any](store interface {
+	Load() ([]T, error)
+	Save([]T) error
+}, category func(*T) *string, oldName, newName string) error {
+	items, err := store.Load()
+	if err != nil {
+		return err
+	}
+	changed := false
+	for i := range items {
+		if c := category(&items[i]); strings.EqualFold(*c, oldName) {
+			*c = newName
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return store.Save(items)
 }
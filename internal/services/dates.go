@@ -0,0 +1,27 @@
+package services
+
+import "time"
+
+// addMonths moves t by n months, keeping its day but clamping it to the
+// last day of shorter months: 31.01 plus one month is 28.02, not 03.03.
+func addMonths(t time.Time, n int) time.Time {
+	first := time.Date(t.Year(), t.Month(), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location()).AddDate(0, n, 0)
+	last := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(t.Day(), last)-1)
+}
+
+// monthsSince counts the months from start to t by calendar days. Whole
+// months end on the dates addMonths gives, and the rest of a month counts
+// as the share of its days that has passed.
+func monthsSince(start, t time.Time) float64 {
+	start, t = startOfDay(start), startOfDay(t)
+	if !t.After(start) {
+		return 0
+	}
+	n := 0
+	for !addMonths(start, n+1).After(t) {
+		n++
+	}
+	from, to := addMonths(start, n), addMonths(start, n+1)
+	return float64(n) + t.Sub(from).Hours()/to.Sub(from).Hours()
+}
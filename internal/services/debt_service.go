@@ -0,0 +1,425 @@
+package services
+
+import (
+	"errors"
+	"fintrack/internal/models"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrDebtNotFound is returned when no debt has the given ID or name.
+var ErrDebtNotFound = errors.New("долг не найден")
+
+const entityDebt = "debt"
+
+// DebtStore persists debts and loans.
+type DebtStore interface {
+	Load() ([]models.Debt, error)
+	Save(debts []models.Debt) error
+}
+
+type DebtService struct {
+	store              DebtStore
+	transactionService *TransactionService
+	mutationHooks
+	mu sync.Mutex
+}
+
+func NewDebtService(store DebtStore, transactionService *TransactionService) *DebtService {
+	return &DebtService{
+		store:              store,
+		transactionService: transactionService,
+	}
+}
+
+// CategoryRenamed moves the debts paid from a renamed category to its new name.
+func (ds *DebtService) CategoryRenamed(oldName, newName string) error {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	return renameCategoryIn(ds.store, func(d *models.Debt) *string { return &d.Category }, oldName, newName)
+}
+
+func (ds *DebtService) Debts() ([]models.Debt, error) {
+	return ds.store.Load()
+}
+
+// FindDebt looks a debt up by ID or by name, ignoring case.
+func (ds *DebtService) FindDebt(ref string) (models.Debt, error) {
+	debts, err := ds.store.Load()
+	if err != nil {
+		return models.Debt{}, err
+	}
+	i := findDebt(debts, ref)
+	if i < 0 {
+		return models.Debt{}, ErrDebtNotFound
+	}
+	return debts[i], nil
+}
+
+// CreateDebt validates and saves a new debt; ID, payments and creation time are set here.
+func (ds *DebtService) CreateDebt(debt models.Debt) (models.Debt, error) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	debt.Name = strings.TrimSpace(debt.Name)
+	debt.Counterparty = strings.TrimSpace(debt.Counterparty)
+	if debt.Name == "" {
+		debt.Name = debt.Counterparty
+	}
+	if debt.Name == "" {
+		return models.Debt{}, validationError("укажите название долга или контрагента")
+	}
+	if debt.Direction != models.DebtLent && debt.Direction != models.DebtBorrowed {
+		return models.Debt{}, validationError("направление долга должно быть %s или %s", models.DebtLent, models.DebtBorrowed)
+	}
+	if debt.Principal <= 0 {
+		return models.Debt{}, validationError("сумма долга должна быть положительной")
+	}
+	if debt.Rate < 0 {
+		return models.Debt{}, validationError("ставка не может быть отрицательной")
+	}
+	if debt.TermMonths < 0 {
+		return models.Debt{}, validationError("срок не может быть отрицательным")
+	}
+	if debt.Start.IsZero() {
+		debt.Start = time.Now()
+	}
+
+	category, err := ds.paymentCategory(debt.Direction, debt.Category)
+	if err != nil {
+		return models.Debt{}, err
+	}
+	debt.Category = category
+
+	debts, err := ds.store.Load()
+	if err != nil {
+		return models.Debt{}, err
+	}
+	if findDebt(debts, debt.Name) >= 0 {
+		return models.Debt{}, validationError("долг %s уже существует", debt.Name)
+	}
+
+	debt.ID = fmt.Sprintf("debt_%d", time.Now().UnixNano())
+	debt.Payments = nil
+	debt.CreatedAt = time.Now()
+	if err := ds.store.Save(append(debts, debt)); err != nil {
+		return models.Debt{}, err
+	}
+	return debt, ds.changed(models.AuditCreate, entityDebt, debt.ID, nil, debt)
+}
+
+// paymentCategory checks that the category exists and suits payments of the
+// debt: incomes for lent money, expenses for borrowed money.
+func (ds *DebtService) paymentCategory(direction models.DebtDirection, name string) (string, error) {
+	if strings.TrimSpace(name) == "" {
+		return "", validationError("категория платежей не может быть пустой")
+	}
+	categories, err := ds.transactionService.storage.GetCategories()
+	if err != nil {
+		return "", err
+	}
+	for _, c := range categories {
+		if !strings.EqualFold(c.Name, name) {
+			continue
+		}
+		if c.IsIncome != (direction == models.DebtLent) {
+			return "", validationError("категория %s не подходит для платежей по этому долгу", c.Name)
+		}
+		return c.Name, nil
+	}
+	return "", validationError("категория не найдена")
+}
+
+// RecordPayment adds a repayment transaction in the debt's category and
+// links it, as one history step.
+func (ds *DebtService) RecordPayment(ref string, amount float64, date time.Time) (models.Transaction, error) {
+	debt, err := ds.FindDebt(ref)
+	if err != nil {
+		return models.Transaction{}, err
+	}
+
+	description := "Платеж по долгу: " + debt.Name
+	if debt.Direction == models.DebtLent {
+		description = "Возврат долга: " + debt.Name
+	}
+	var transaction models.Transaction
+	err = ds.transactionService.group(description, func() error {
+		var err error
+		transaction, err = ds.transactionService.AddTransactionAt(amount, debt.Category, description, string(paymentType(debt.Direction)), date)
+		if transaction.ID == "" {
+			return err
+		}
+		if linkErr := ds.LinkPayment(debt.ID, transaction.ID); linkErr != nil {
+			// an unlinked payment would not reduce the debt, so it is not kept
+			id := transaction.ID
+			transaction = models.Transaction{}
+			if deleteErr := ds.transactionService.DeleteTransaction(id); deleteErr != nil {
+				return errors.Join(linkErr, deleteErr)
+			}
+			return linkErr
+		}
+		return err
+	})
+	return transaction, err
+}
+
+// LinkPayment marks an existing transaction as a payment on the debt.
+func (ds *DebtService) LinkPayment(ref, transactionID string) error {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	transaction, err := ds.transactionService.GetTransactionByID(transactionID)
+	if err != nil {
+		return err
+	}
+
+	debts, err := ds.store.Load()
+	if err != nil {
+		return err
+	}
+	i := findDebt(debts, ref)
+	if i < 0 {
+		return ErrDebtNotFound
+	}
+	if transaction.Type != paymentType(debts[i].Direction) {
+		return validationError("тип транзакции не соответствует направлению долга")
+	}
+	for _, d := range debts {
+		for _, id := range d.Payments {
+			if id == transactionID {
+				return validationError("транзакция уже привязана к долгу %s", d.Name)
+			}
+		}
+	}
+
+	before := debts[i]
+	debts[i].Payments = append(append([]string{}, before.Payments...), transactionID)
+	if err := ds.store.Save(debts); err != nil {
+		return err
+	}
+	return ds.changed(models.AuditUpdate, entityDebt, before.ID, before, debts[i])
+}
+
+// UnlinkPayment detaches a transaction from the debt; the transaction itself is kept.
+func (ds *DebtService) UnlinkPayment(ref, transactionID string) error {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	debts, err := ds.store.Load()
+	if err != nil {
+		return err
+	}
+	i := findDebt(debts, ref)
+	if i < 0 {
+		return ErrDebtNotFound
+	}
+
+	before := debts[i]
+	payments := []string{}
+	for _, id := range before.Payments {
+		if id != transactionID {
+			payments = append(payments, id)
+		}
+	}
+	if len(payments) == len(before.Payments) {
+		return validationError("транзакция не привязана к этому долгу")
+	}
+
+	debts[i].Payments = payments
+	if err := ds.store.Save(debts); err != nil {
+		return err
+	}
+	return ds.changed(models.AuditUpdate, entityDebt, before.ID, before, debts[i])
+}
+
+// DeleteDebt removes the debt; its payment transactions are kept.
+func (ds *DebtService) DeleteDebt(ref string) error {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	debts, err := ds.store.Load()
+	if err != nil {
+		return err
+	}
+	i := findDebt(debts, ref)
+	if i < 0 {
+		return ErrDebtNotFound
+	}
+
+	deleted := debts[i]
+	if err := ds.destructive("удаление долга " + deleted.Name); err != nil {
+		return err
+	}
+	if err := ds.store.Save(append(debts[:i], debts[i+1:]...)); err != nil {
+		return err
+	}
+	return ds.changed(models.AuditDelete, entityDebt, deleted.ID, deleted, nil)
+}
+
+// AllStatus returns the state of every debt.
+func (ds *DebtService) AllStatus() ([]models.DebtStatus, error) {
+	debts, err := ds.store.Load()
+	if err != nil {
+		return nil, err
+	}
+	transactions, err := ds.transactionService.GetAllTransactions()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.DebtStatus, len(debts))
+	for i, d := range debts {
+		result[i] = debtStatus(d, transactions)
+	}
+	return result, nil
+}
+
+// Status returns the state of one debt.
+func (ds *DebtService) Status(ref string) (models.DebtStatus, error) {
+	debt, err := ds.FindDebt(ref)
+	if err != nil {
+		return models.DebtStatus{}, err
+	}
+	transactions, err := ds.transactionService.GetAllTransactions()
+	if err != nil {
+		return models.DebtStatus{}, err
+	}
+	return debtStatus(debt, transactions), nil
+}
+
+// AmortizationSchedule returns the annuity schedule of a debt with a term:
+// equal monthly payments, the last one adjusted to close the balance.
+func AmortizationSchedule(debt models.Debt) []models.AmortizationRow {
+	if debt.TermMonths == 0 {
+		return nil
+	}
+
+	payment := annuityPayment(debt.Principal, debt.Rate, debt.TermMonths)
+	monthly := debt.Rate / 100 / 12
+	balance := debt.Principal
+
+	rows := make([]models.AmortizationRow, 0, debt.TermMonths)
+	for n := 1; n <= debt.TermMonths; n++ {
+		interest := roundMoney(balance * monthly)
+		principal := roundMoney(payment - interest)
+		if n == debt.TermMonths || principal > balance {
+			principal = roundMoney(balance)
+		}
+		balance = roundMoney(balance - principal)
+		rows = append(rows, models.AmortizationRow{
+			N:         n,
+			Date:      addMonths(debt.Start, n),
+			Payment:   roundMoney(interest + principal),
+			Interest:  interest,
+			Principal: principal,
+			Balance:   balance,
+		})
+	}
+	return rows
+}
+
+// annuityPayment is the equal monthly payment that repays principal in months
+// at the given annual rate in percent.
+func annuityPayment(principal, rate float64, months int) float64 {
+	if months <= 0 {
+		return 0
+	}
+	r := rate / 100 / 12
+	if r == 0 {
+		return roundMoney(principal / float64(months))
+	}
+	return roundMoney(principal * r / (1 - math.Pow(1+r, -float64(months))))
+}
+
+// debtStatus applies the linked payments in date order. Interest accrues on
+// the outstanding balance at a twelfth of the annual rate per month, as in
+// AmortizationSchedule, so paying the scheduled amounts on the scheduled
+// dates closes the debt; a part of a month accrues by its days. Each payment
+// covers the interest first and the rest repays the principal. Interest
+// left unpaid by a small payment is capitalized, so the balance always
+// shows everything that is owed. Payments whose transactions no longer
+// exist are skipped.
+func debtStatus(debt models.Debt, transactions []models.Transaction) models.DebtStatus {
+	status := models.DebtStatus{
+		Debt:           debt,
+		Payments:       []models.DebtPayment{},
+		MonthlyPayment: annuityPayment(debt.Principal, debt.Rate, debt.TermMonths),
+	}
+
+	byID := make(map[string]models.Transaction, len(transactions))
+	for _, t := range transactions {
+		byID[t.ID] = t
+	}
+	var paid []models.Transaction
+	for _, id := range debt.Payments {
+		if t, ok := byID[id]; ok {
+			paid = append(paid, t)
+		}
+	}
+	sort.SliceStable(paid, func(i, j int) bool {
+		return paid[i].Date.Before(paid[j].Date)
+	})
+
+	balance := debt.Principal
+	elapsed := 0.0
+	for _, t := range paid {
+		interest := 0.0
+		if months := monthsSince(debt.Start, t.Date); months > elapsed {
+			interest = roundMoney(balance * debt.Rate / 100 / 12 * (months - elapsed))
+			elapsed = months
+		}
+		// interest the payment does not cover is added to the balance
+		unpaid := roundMoney(math.Max(interest-t.Amount, 0))
+		interest = roundMoney(interest - unpaid)
+		balance = roundMoney(balance + unpaid)
+		principal := roundMoney(math.Min(t.Amount-interest, balance))
+		balance = roundMoney(balance - principal)
+
+		status.InterestPaid += interest
+		status.PrincipalPaid += principal
+		status.Payments = append(status.Payments, models.DebtPayment{
+			TransactionID: t.ID,
+			Date:          t.Date,
+			Amount:        t.Amount,
+			Interest:      interest,
+			Principal:     principal,
+			Balance:       balance,
+		})
+	}
+
+	status.Balance = balance
+	status.InterestPaid = roundMoney(status.InterestPaid)
+	status.PrincipalPaid = roundMoney(status.PrincipalPaid)
+	status.PaidOff = balance <= 0
+	if !status.PaidOff && debt.TermMonths > 0 {
+		status.NextDue = addMonths(debt.Start, min(len(status.Payments), debt.TermMonths-1)+1)
+	}
+	return status
+}
+
+// paymentType is the transaction type of repayments: we receive money back
+// for lent debts and pay it for borrowed ones.
+func paymentType(direction models.DebtDirection) models.TransactionType {
+	if direction == models.DebtLent {
+		return models.TransactionIncome
+	}
+	return models.TransactionExpense
+}
+
+func roundMoney(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+func findDebt(debts []models.Debt, ref string) int {
+	ref = strings.TrimSpace(ref)
+	for i, d := range debts {
+		if d.ID == ref || strings.EqualFold(d.Name, ref) {
+			return i
+		}
+	}
+	return -1
+}
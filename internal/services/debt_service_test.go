@@ -0,0 +1,190 @@
+package services
+
+import (
+	"fintrack/internal/models"
+	"math"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+}
+
+func TestAmortizationSchedule(t *testing.T) {
+	tests := []struct {
+		name        string
+		debt        models.Debt
+		wantPayment float64
+		wantDates   []time.Time // the first scheduled dates
+	}{
+		{
+			name:        "annuity",
+			debt:        models.Debt{Principal: 120000, Rate: 12, TermMonths: 12, Start: date(2025, time.January, 15)},
+			wantPayment: 10661.85,
+			wantDates:   []time.Time{date(2025, time.February, 15), date(2025, time.March, 15)},
+		},
+		{
+			name:        "no interest",
+			debt:        models.Debt{Principal: 1000, TermMonths: 3, Start: date(2025, time.January, 1)},
+			wantPayment: 333.33,
+		},
+		{
+			name:        "end of month start",
+			debt:        models.Debt{Principal: 50000, Rate: 18.5, TermMonths: 6, Start: date(2025, time.January, 31)},
+			wantPayment: 8788.72,
+			wantDates:   []time.Time{date(2025, time.February, 28), date(2025, time.March, 31), date(2025, time.April, 30)},
+		},
+		{
+			name: "no term",
+			debt: models.Debt{Principal: 1000, Rate: 10, Start: date(2025, time.January, 1)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := AmortizationSchedule(tt.debt)
+			if len(rows) != tt.debt.TermMonths {
+				t.Fatalf("%d rows, want %d", len(rows), tt.debt.TermMonths)
+			}
+			if len(rows) == 0 {
+				return
+			}
+
+			principal := 0.0
+			for i, row := range rows {
+				principal += row.Principal
+				if i < len(rows)-1 && row.Payment != tt.wantPayment {
+					t.Errorf("payment %d = %.2f, want %.2f", row.N, row.Payment, tt.wantPayment)
+				}
+				if row.Payment != roundMoney(row.Interest+row.Principal) {
+					t.Errorf("payment %d = %.2f, not interest %.2f plus principal %.2f", row.N, row.Payment, row.Interest, row.Principal)
+				}
+			}
+			if math.Abs(principal-tt.debt.Principal) > 0.005 {
+				t.Errorf("principal repaid %.2f, want %.2f", principal, tt.debt.Principal)
+			}
+			if last := rows[len(rows)-1]; last.Balance != 0 {
+				t.Errorf("balance after the last payment %.2f, want 0", last.Balance)
+			}
+			for i, want := range tt.wantDates {
+				if !rows[i].Date.Equal(want) {
+					t.Errorf("date %d = %s, want %s", rows[i].N, rows[i].Date.Format("02.01.2006"), want.Format("02.01.2006"))
+				}
+			}
+		})
+	}
+}
+
+func TestDebtStatus(t *testing.T) {
+	loan := models.Debt{Principal: 100000, Rate: 12, TermMonths: 12, Start: date(2025, time.January, 1)}
+	endOfMonth := models.Debt{Principal: 50000, Rate: 18.5, TermMonths: 6, Start: date(2025, time.January, 31)}
+
+	// scheduled pays every row of the schedule on its date, at a later hour
+	scheduled := func(debt models.Debt) []models.Transaction {
+		var transactions []models.Transaction
+		for _, row := range AmortizationSchedule(debt) {
+			transactions = append(transactions, models.Transaction{ID: "p" + strconv.Itoa(row.N), Amount: row.Payment, Date: row.Date.Add(13 * time.Hour)})
+		}
+		return transactions
+	}
+
+	// scheduleInterest is the interest the schedule of debt charges in total
+	scheduleInterest := func(debt models.Debt) float64 {
+		total := 0.0
+		for _, row := range AmortizationSchedule(debt) {
+			total += row.Interest
+		}
+		return roundMoney(total)
+	}
+
+	tests := []struct {
+		name         string
+		debt         models.Debt
+		transactions []models.Transaction
+		unlinked     []string // linked IDs without a transaction
+		wantBalance  float64
+		wantInterest float64
+		wantNextDue  time.Time
+	}{
+		{
+			name:        "no payments",
+			debt:        loan,
+			wantBalance: 100000,
+			wantNextDue: date(2025, time.February, 1),
+		},
+		{
+			name:         "scheduled payments close the debt",
+			debt:         loan,
+			transactions: scheduled(loan),
+			wantBalance:  0,
+			wantInterest: scheduleInterest(loan),
+		},
+		{
+			name:         "scheduled payments from the end of a month",
+			debt:         endOfMonth,
+			transactions: scheduled(endOfMonth),
+			wantBalance:  0,
+			wantInterest: scheduleInterest(endOfMonth),
+		},
+		{
+			name:         "a part of a month accrues by its days",
+			debt:         loan,
+			transactions: []models.Transaction{{ID: "p1", Amount: 1000, Date: date(2025, time.January, 16)}},
+			wantBalance:  99483.87,
+			wantInterest: 483.87,
+			wantNextDue:  date(2025, time.March, 1),
+		},
+		{
+			name:         "unpaid interest is capitalized",
+			debt:         loan,
+			transactions: []models.Transaction{{ID: "p1", Amount: 500, Date: date(2025, time.February, 1)}},
+			wantBalance:  100500,
+			wantInterest: 500,
+			wantNextDue:  date(2025, time.March, 1),
+		},
+		{
+			name:         "payments of deleted transactions are skipped",
+			debt:         loan,
+			transactions: []models.Transaction{{ID: "p1", Amount: 1000, Date: date(2025, time.February, 1)}},
+			unlinked:     []string{"gone"},
+			wantBalance:  100000,
+			wantInterest: 1000,
+			wantNextDue:  date(2025, time.March, 1),
+		},
+		{
+			name:         "interest-free loan",
+			debt:         models.Debt{Principal: 3000, Start: date(2025, time.January, 1)},
+			transactions: []models.Transaction{{ID: "p1", Amount: 1000, Date: date(2025, time.June, 1)}},
+			wantBalance:  2000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			debt := tt.debt
+			debt.Payments = append([]string(nil), tt.unlinked...)
+			for _, tr := range tt.transactions {
+				debt.Payments = append(debt.Payments, tr.ID)
+			}
+
+			status := debtStatus(debt, tt.transactions)
+			if status.Balance != tt.wantBalance {
+				t.Errorf("balance = %.2f, want %.2f", status.Balance, tt.wantBalance)
+			}
+			if status.InterestPaid != tt.wantInterest {
+				t.Errorf("interest paid = %.2f, want %.2f", status.InterestPaid, tt.wantInterest)
+			}
+			if status.PaidOff != (tt.wantBalance <= 0) {
+				t.Errorf("paid off = %v with balance %.2f", status.PaidOff, status.Balance)
+			}
+			if len(status.Payments) != len(tt.transactions) {
+				t.Errorf("%d payments, want %d", len(status.Payments), len(tt.transactions))
+			}
+			if !status.NextDue.Equal(tt.wantNextDue) {
+				t.Errorf("next due = %s, want %s", status.NextDue.Format("02.01.2006"), tt.wantNextDue.Format("02.01.2006"))
+			}
+		})
+	}
+}
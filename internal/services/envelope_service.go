@@ -0,0 +1,340 @@
+package services
+
+import (
+	"fintrack/internal/models"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const entityEnvelope = "envelope"
+
+// EnvelopeStore persists envelope allocations.
+type EnvelopeStore interface {
+	Load() ([]models.EnvelopeAllocation, error)
+	Save(allocations []models.EnvelopeAllocation) error
+}
+
+// EnvelopeService implements zero-based budgeting: income is assigned to
+// expense category envelopes month by month, expenses draw them down and
+// whatever is left, including overspending, rolls over to the next month.
+type EnvelopeService struct {
+	store              EnvelopeStore
+	transactionService *TransactionService
+	mutationHooks
+	mu sync.Mutex
+}
+
+func NewEnvelopeService(store EnvelopeStore, transactionService *TransactionService) *EnvelopeService {
+	return &EnvelopeService{
+		store:              store,
+		transactionService: transactionService,
+	}
+}
+
+// CategoryRenamed moves the envelope allocations of a renamed category to its new name.
+func (es *EnvelopeService) CategoryRenamed(oldName, newName string) error {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	return renameCategoryIn(es.store, func(a *models.EnvelopeAllocation) *string { return &a.Category }, oldName, newName)
+}
+
+// Assign puts amount into the category envelope for the month of t; a
+// negative amount returns money to "to be assigned".
+func (es *EnvelopeService) Assign(t time.Time, category string, amount float64, note string) (models.EnvelopeAllocation, error) {
+	allocation, err := es.assign(t, category, amount, note)
+	if err != nil {
+		return models.EnvelopeAllocation{}, err
+	}
+	return allocation, es.changed(models.AuditCreate, entityEnvelope, allocation.ID, nil, allocation)
+}
+
+func (es *EnvelopeService) assign(t time.Time, category string, amount float64, note string) (models.EnvelopeAllocation, error) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	if amount == 0 {
+		return models.EnvelopeAllocation{}, validationError("сумма не может быть нулевой")
+	}
+	category, err := es.expenseCategory(category)
+	if err != nil {
+		return models.EnvelopeAllocation{}, err
+	}
+
+	allocations, err := es.store.Load()
+	if err != nil {
+		return models.EnvelopeAllocation{}, err
+	}
+
+	allocation := newAllocation(t, category, amount, note)
+	if err := es.store.Save(append(allocations, allocation)); err != nil {
+		return models.EnvelopeAllocation{}, err
+	}
+	return allocation, nil
+}
+
+// Move transfers money between two envelopes in the month of t. The source
+// envelope must have enough available. Both allocations are one history step.
+func (es *EnvelopeService) Move(t time.Time, from, to string, amount float64) error {
+	out, in, err := es.move(t, from, to, amount)
+	if err != nil {
+		return err
+	}
+	return es.group(out.Note, func() error {
+		if err := es.changed(models.AuditCreate, entityEnvelope, out.ID, nil, out); err != nil {
+			return err
+		}
+		return es.changed(models.AuditCreate, entityEnvelope, in.ID, nil, in)
+	})
+}
+
+func (es *EnvelopeService) move(t time.Time, from, to string, amount float64) (out, in models.EnvelopeAllocation, err error) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	if amount <= 0 {
+		return out, in, validationError("сумма перевода должна быть положительной")
+	}
+	from, err = es.expenseCategory(from)
+	if err != nil {
+		return out, in, err
+	}
+	to, err = es.expenseCategory(to)
+	if err != nil {
+		return out, in, err
+	}
+	if from == to {
+		return out, in, validationError("конверты должны различаться")
+	}
+
+	allocations, err := es.store.Load()
+	if err != nil {
+		return out, in, err
+	}
+	month, err := es.month(t, allocations)
+	if err != nil {
+		return out, in, err
+	}
+	for _, e := range month.Envelopes {
+		if e.Category == from && e.Available < amount {
+			return out, in, validationError("в конверте %s доступно только %.2f", from, e.Available)
+		}
+	}
+
+	note := fmt.Sprintf("перевод %s → %s", from, to)
+	out = newAllocation(t, from, -amount, note)
+	in = newAllocation(t, to, amount, note)
+	in.ID += "_in"
+	if err := es.store.Save(append(allocations, out, in)); err != nil {
+		return models.EnvelopeAllocation{}, models.EnvelopeAllocation{}, err
+	}
+	return out, in, nil
+}
+
+// SetHistory makes allocations undoable through history. Changes are
+// reported after es.mu is released, since undo takes it under the history lock.
+func (es *EnvelopeService) SetHistory(history *History) {
+	es.mutationHooks.SetHistory(history)
+	history.track(entityEnvelope, es.applyChange)
+}
+
+// applyChange performs an allocation change undone or redone by History.
+func (es *EnvelopeService) applyChange(c models.Change) error {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	var allocation models.EnvelopeAllocation
+	if err := unmarshalSnapshot(c.After, &allocation); err != nil {
+		return err
+	}
+	allocations, err := es.store.Load()
+	if err != nil {
+		return err
+	}
+	allocations = slices.DeleteFunc(allocations, func(a models.EnvelopeAllocation) bool { return a.ID == c.ID })
+	switch c.Action {
+	case models.AuditCreate, models.AuditUpdate:
+		allocations = append(allocations, allocation)
+	case models.AuditDelete:
+	default:
+		return fmt.Errorf("неизвестное действие: %s", c.Action)
+	}
+	return es.store.Save(allocations)
+}
+
+// Month returns the envelope budget of the month of t.
+func (es *EnvelopeService) Month(t time.Time) (models.EnvelopeMonth, error) {
+	allocations, err := es.store.Load()
+	if err != nil {
+		return models.EnvelopeMonth{}, err
+	}
+	return es.month(t, allocations)
+}
+
+// Allocations returns the allocations made for the month of t.
+func (es *EnvelopeService) Allocations(t time.Time) ([]models.EnvelopeAllocation, error) {
+	allocations, err := es.store.Load()
+	if err != nil {
+		return nil, err
+	}
+	key := monthKey(t)
+	result := []models.EnvelopeAllocation{}
+	for _, a := range allocations {
+		if a.Month == key {
+			result = append(result, a)
+		}
+	}
+	return result, nil
+}
+
+// month replays every month from the first allocation up to the month of t,
+// carrying what is left in each envelope forward. The balance before the
+// first allocation becomes money to be assigned; spending from that time is
+// not charged to envelopes.
+func (es *EnvelopeService) month(t time.Time, allocations []models.EnvelopeAllocation) (models.EnvelopeMonth, error) {
+	transactions, err := es.transactionService.GetAllTransactions()
+	if err != nil {
+		return models.EnvelopeMonth{}, err
+	}
+	categories, err := es.transactionService.storage.GetCategories()
+	if err != nil {
+		return models.EnvelopeMonth{}, err
+	}
+
+	target := monthKey(t)
+	first := target
+	envelopes := map[string]*models.Envelope{}
+	envelope := func(category string) *models.Envelope {
+		e, ok := envelopes[category]
+		if !ok {
+			e = &models.Envelope{Category: category}
+			envelopes[category] = e
+		}
+		return e
+	}
+
+	for _, c := range categories {
+		if !c.IsIncome {
+			envelope(c.Name)
+		}
+	}
+	for _, a := range allocations {
+		if a.Month < first {
+			first = a.Month
+		}
+	}
+
+	result := models.EnvelopeMonth{Month: target}
+	var income, assigned float64
+	for _, tr := range transactions {
+		if monthKey(tr.Date) >= first {
+			continue
+		}
+		if tr.Type == models.TransactionIncome {
+			income += tr.Amount
+		} else {
+			income -= tr.Amount
+		}
+	}
+	start, err := time.ParseInLocation(monthLayout, first, time.Local)
+	if err != nil {
+		return models.EnvelopeMonth{}, err
+	}
+
+	for m := start; monthKey(m) <= target; m = m.AddDate(0, 1, 0) {
+		key := monthKey(m)
+		for _, e := range envelopes {
+			e.Rollover = e.Available
+			e.Assigned, e.Spent = 0, 0
+		}
+
+		for _, a := range allocations {
+			if a.Month == key {
+				envelope(a.Category).Assigned += a.Amount
+				assigned += a.Amount
+			}
+		}
+		for _, tr := range transactions {
+			if monthKey(tr.Date) != key {
+				continue
+			}
+			if tr.Type == models.TransactionIncome {
+				income += tr.Amount
+				if key == target {
+					result.Income += tr.Amount
+				}
+				continue
+			}
+			envelope(canonicalCategory(categories, tr.Category)).Spent += tr.Amount
+		}
+
+		for _, e := range envelopes {
+			e.Available = roundMoney(e.Rollover + e.Assigned - e.Spent)
+		}
+	}
+
+	for _, e := range envelopes {
+		e.Assigned = roundMoney(e.Assigned)
+		e.Spent = roundMoney(e.Spent)
+		result.Assigned += e.Assigned
+		result.Spent += e.Spent
+		result.Available += e.Available
+		result.Envelopes = append(result.Envelopes, *e)
+	}
+	sort.Slice(result.Envelopes, func(i, j int) bool {
+		return result.Envelopes[i].Category < result.Envelopes[j].Category
+	})
+
+	result.Income = roundMoney(result.Income)
+	result.Assigned = roundMoney(result.Assigned)
+	result.Spent = roundMoney(result.Spent)
+	result.Available = roundMoney(result.Available)
+	result.ToBeAssigned = roundMoney(income - assigned)
+	return result, nil
+}
+
+// expenseCategory returns the stored name of an existing expense category.
+func (es *EnvelopeService) expenseCategory(name string) (string, error) {
+	categories, err := es.transactionService.storage.GetCategories()
+	if err != nil {
+		return "", err
+	}
+	for _, c := range categories {
+		if strings.EqualFold(c.Name, strings.TrimSpace(name)) {
+			if c.IsIncome {
+				return "", validationError("конверты заводятся только для категорий расходов")
+			}
+			return c.Name, nil
+		}
+	}
+	return "", validationError("категория не найдена")
+}
+
+// canonicalCategory maps a transaction category to the stored spelling so
+// envelopes are not split by letter case.
+func canonicalCategory(categories []models.Category, name string) string {
+	for _, c := range categories {
+		if strings.EqualFold(c.Name, name) {
+			return c.Name
+		}
+	}
+	return name
+}
+
+func newAllocation(t time.Time, category string, amount float64, note string) models.EnvelopeAllocation {
+	return models.EnvelopeAllocation{
+		ID:        fmt.Sprintf("env_%d", time.Now().UnixNano()),
+		Month:     monthKey(t),
+		Category:  category,
+		Amount:    amount,
+		Note:      strings.TrimSpace(note),
+		CreatedAt: time.Now(),
+	}
+}
+
+func monthKey(t time.Time) string {
+	return t.Local().Format(monthLayout)
+}
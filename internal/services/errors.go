@@ -0,0 +1,26 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ValidationError reports invalid user input; callers such as the HTTP API
+// use it to tell client mistakes from internal failures.
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+func validationError(format string, args ...interface{}) error {
+	return &ValidationError{Message: fmt.Sprintf(format, args...)}
+}
+
+// IsValidationError reports whether err or any error it wraps is a *ValidationError.
+func IsValidationError(err error) bool {
+	var v *ValidationError
+	return errors.As(err, &v)
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"fintrack/internal/models"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ForecastService projects the balance from recurring items and the
+// spending habits seen in the history.
+type ForecastService struct {
+	transactionService *TransactionService
+	recurringService   *RecurringService
+}
+
+func NewForecastService(transactionService *TransactionService, recurringService *RecurringService) *ForecastService {
+	return &ForecastService{
+		transactionService: transactionService,
+		recurringService:   recurringService,
+	}
+}
+
+// Forecast projects the closing balance of today and each of the next days.
+// Every day gets the recurring items due on it plus the average daily
+// discretionary spending by category over the last lookback days; expenses
+// that look like a recurring item are not counted as discretionary. Today
+// only gets the recurring items not posted yet and what is left of the
+// usual spending after today's transactions.
+func (fs *ForecastService) Forecast(now time.Time, days, lookback int) (models.Forecast, error) {
+	if days <= 0 {
+		return models.Forecast{}, validationError("количество дней прогноза должно быть положительным")
+	}
+	if lookback <= 0 {
+		return models.Forecast{}, validationError("период истории должен быть положительным")
+	}
+
+	transactions, err := fs.transactionService.GetAllTransactions()
+	if err != nil {
+		return models.Forecast{}, err
+	}
+	items, err := fs.recurringService.Items()
+	if err != nil {
+		return models.Forecast{}, err
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	forecast := models.Forecast{
+		Start:         today,
+		LookbackDays:  lookback,
+		Discretionary: discretionaryRates(transactions, items, today, lookback),
+		Days:          make([]models.ForecastDay, 0, days),
+	}
+
+	for _, t := range transactions {
+		if t.Date.After(now) {
+			continue
+		}
+		if t.Type == models.TransactionIncome {
+			forecast.Balance += t.Amount
+		} else {
+			forecast.Balance -= t.Amount
+		}
+	}
+	forecast.Balance = roundMoney(forecast.Balance)
+
+	var postedToday []models.Transaction
+	spentToday := map[string]float64{}
+	for _, t := range transactions {
+		if t.Date.Before(today) || t.Date.After(now) {
+			continue
+		}
+		postedToday = append(postedToday, t)
+		if t.Type == models.TransactionExpense && !isRecurring(t, items) {
+			spentToday[t.Category] += t.Amount
+		}
+	}
+
+	balance := forecast.Balance
+	for i := 0; i < days; i++ {
+		day := models.ForecastDay{Date: today.AddDate(0, 0, i), Entries: []models.ForecastEntry{}}
+		next := today.AddDate(0, 0, i+1)
+
+		for _, item := range items {
+			due := len(Occurrences(item, day.Date, next))
+			if i == 0 {
+				for _, t := range postedToday {
+					if due > 0 && isRecurring(t, []models.RecurringItem{item}) {
+						due--
+					}
+				}
+			}
+			for n := 0; n < due; n++ {
+				day.Entries = append(day.Entries, models.ForecastEntry{
+					Description: item.Description,
+					Category:    item.Category,
+					Type:        item.Type,
+					Amount:      item.Amount,
+					Recurring:   true,
+				})
+			}
+		}
+		for _, r := range forecast.Discretionary {
+			amount := r.Daily
+			if i == 0 {
+				amount = roundMoney(math.Max(amount-spentToday[r.Category], 0))
+			}
+			if amount == 0 {
+				continue
+			}
+			day.Entries = append(day.Entries, models.ForecastEntry{
+				Description: "обычные траты",
+				Category:    r.Category,
+				Type:        models.TransactionExpense,
+				Amount:      amount,
+			})
+		}
+
+		for _, e := range day.Entries {
+			if e.Type == models.TransactionIncome {
+				day.Income += e.Amount
+			} else {
+				day.Expense += e.Amount
+			}
+		}
+		day.Income = roundMoney(day.Income)
+		day.Expense = roundMoney(day.Expense)
+		balance += day.Income - day.Expense
+		day.Balance = roundMoney(balance)
+		day.Negative = day.Balance < 0
+
+		if day.Negative {
+			forecast.NegativeDays++
+		}
+		if i == 0 || day.Balance < forecast.Lowest.Balance {
+			forecast.Lowest = day
+		}
+		forecast.Days = append(forecast.Days, day)
+	}
+	return forecast, nil
+}
+
+// discretionaryRates averages the expenses of the lookback days before today
+// by category. The window is shortened to the first transaction so a new
+// ledger is not averaged over days it did not track.
+func discretionaryRates(transactions []models.Transaction, items []models.RecurringItem, today time.Time, lookback int) []models.CategoryRate {
+	from := today.AddDate(0, 0, -lookback)
+	first := today
+	for _, t := range transactions {
+		if t.Date.Before(first) {
+			first = t.Date
+		}
+	}
+	if first.After(from) {
+		from = time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, today.Location())
+	}
+	span := math.Max(today.Sub(from).Hours()/24, 1)
+
+	totals := map[string]float64{}
+	for _, t := range transactions {
+		if t.Type != models.TransactionExpense || t.Date.Before(from) || !t.Date.Before(today) {
+			continue
+		}
+		if isRecurring(t, items) {
+			continue
+		}
+		totals[t.Category] += t.Amount
+	}
+
+	rates := make([]models.CategoryRate, 0, len(totals))
+	for category, total := range totals {
+		rates = append(rates, models.CategoryRate{Category: category, Daily: roundMoney(total / span)})
+	}
+	sort.Slice(rates, func(i, j int) bool {
+		if rates[i].Daily != rates[j].Daily {
+			return rates[i].Daily > rates[j].Daily
+		}
+		return rates[i].Category < rates[j].Category
+	})
+	return rates
+}
+
+// isRecurring reports whether the transaction is an occurrence of one of the
+// recurring items: same type, category and description up to digits and
+// punctuation.
+func isRecurring(t models.Transaction, items []models.RecurringItem) bool {
+	description := normalizeDescription(t.Description)
+	for _, item := range items {
+		if t.Type == item.Type &&
+			strings.EqualFold(t.Category, item.Category) &&
+			description == normalizeDescription(item.Description) {
+			return true
+		}
+	}
+	return false
+}
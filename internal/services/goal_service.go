@@ -0,0 +1,297 @@
+package services
+
+import (
+	"errors"
+	"fintrack/internal/models"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrGoalNotFound is returned when no goal has the given ID or name.
+var ErrGoalNotFound = errors.New("цель не найдена")
+
+// average month length used to turn elapsed days into months
+const daysPerMonth = 365.25 / 12
+
+const entityGoal = "goal"
+
+// GoalStore persists savings goals.
+type GoalStore interface {
+	Load() ([]models.Goal, error)
+	Save(goals []models.Goal) error
+}
+
+type GoalService struct {
+	store              GoalStore
+	transactionService *TransactionService
+	mutationHooks
+	mu sync.Mutex
+}
+
+func NewGoalService(store GoalStore, transactionService *TransactionService) *GoalService {
+	return &GoalService{
+		store:              store,
+		transactionService: transactionService,
+	}
+}
+
+// CategoryRenamed moves the goals that count spending in a renamed category to its new name.
+func (gs *GoalService) CategoryRenamed(oldName, newName string) error {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	return renameCategoryIn(gs.store, func(g *models.Goal) *string { return &g.Category }, oldName, newName)
+}
+
+func (gs *GoalService) Goals() ([]models.Goal, error) {
+	return gs.store.Load()
+}
+
+// FindGoal looks a goal up by ID or by name, ignoring case.
+func (gs *GoalService) FindGoal(ref string) (models.Goal, error) {
+	goals, err := gs.store.Load()
+	if err != nil {
+		return models.Goal{}, err
+	}
+	i := findGoal(goals, ref)
+	if i < 0 {
+		return models.Goal{}, ErrGoalNotFound
+	}
+	return goals[i], nil
+}
+
+// CreateGoal adds a goal. Deadline, category and tag are optional.
+func (gs *GoalService) CreateGoal(name string, target float64, deadline time.Time, category, tag string) (models.Goal, error) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return models.Goal{}, validationError("название цели не может быть пустым")
+	}
+	if target <= 0 {
+		return models.Goal{}, validationError("сумма цели должна быть положительной")
+	}
+	tag = strings.TrimPrefix(strings.TrimSpace(tag), "#")
+
+	if category != "" {
+		categories, err := gs.transactionService.storage.GetCategories()
+		if err != nil {
+			return models.Goal{}, err
+		}
+		found := false
+		for _, c := range categories {
+			if strings.EqualFold(c.Name, category) {
+				category, found = c.Name, true
+			}
+		}
+		if !found {
+			return models.Goal{}, validationError("категория не найдена")
+		}
+	}
+
+	goals, err := gs.store.Load()
+	if err != nil {
+		return models.Goal{}, err
+	}
+	if findGoal(goals, name) >= 0 {
+		return models.Goal{}, validationError("цель %s уже существует", name)
+	}
+
+	goal := models.Goal{
+		ID:        fmt.Sprintf("goal_%d", time.Now().UnixNano()),
+		Name:      name,
+		Target:    target,
+		Deadline:  deadline,
+		Category:  category,
+		Tag:       tag,
+		CreatedAt: time.Now(),
+	}
+	if err := gs.store.Save(append(goals, goal)); err != nil {
+		return models.Goal{}, err
+	}
+	return goal, gs.changed(models.AuditCreate, entityGoal, goal.ID, nil, goal)
+}
+
+// Allocate records money set aside for the goal; a negative amount takes it back.
+func (gs *GoalService) Allocate(ref string, amount float64, note string, date time.Time) (models.Goal, error) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+
+	if amount == 0 {
+		return models.Goal{}, validationError("сумма не может быть нулевой")
+	}
+
+	goals, err := gs.store.Load()
+	if err != nil {
+		return models.Goal{}, err
+	}
+	i := findGoal(goals, ref)
+	if i < 0 {
+		return models.Goal{}, ErrGoalNotFound
+	}
+
+	before := goals[i]
+	goals[i].Allocations = append(append([]models.GoalAllocation{}, before.Allocations...),
+		models.GoalAllocation{Date: date, Amount: amount, Note: strings.TrimSpace(note)})
+	if err := gs.store.Save(goals); err != nil {
+		return models.Goal{}, err
+	}
+	return goals[i], gs.changed(models.AuditUpdate, entityGoal, before.ID, before, goals[i])
+}
+
+func (gs *GoalService) DeleteGoal(ref string) error {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+
+	goals, err := gs.store.Load()
+	if err != nil {
+		return err
+	}
+	i := findGoal(goals, ref)
+	if i < 0 {
+		return ErrGoalNotFound
+	}
+
+	deleted := goals[i]
+	if err := gs.destructive("удаление цели " + deleted.Name); err != nil {
+		return err
+	}
+	if err := gs.store.Save(append(goals[:i], goals[i+1:]...)); err != nil {
+		return err
+	}
+	return gs.changed(models.AuditDelete, entityGoal, deleted.ID, deleted, nil)
+}
+
+// AllProgress returns the progress of every goal at now.
+func (gs *GoalService) AllProgress(now time.Time) ([]models.GoalProgress, error) {
+	goals, err := gs.store.Load()
+	if err != nil {
+		return nil, err
+	}
+	transactions, err := gs.transactionService.GetAllTransactions()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.GoalProgress, len(goals))
+	for i, g := range goals {
+		result[i] = goalProgress(g, transactions, now)
+	}
+	return result, nil
+}
+
+// Progress returns the progress of one goal at now.
+func (gs *GoalService) Progress(ref string, now time.Time) (models.GoalProgress, error) {
+	goal, err := gs.FindGoal(ref)
+	if err != nil {
+		return models.GoalProgress{}, err
+	}
+	transactions, err := gs.transactionService.GetAllTransactions()
+	if err != nil {
+		return models.GoalProgress{}, err
+	}
+	return goalProgress(goal, transactions, now), nil
+}
+
+// goalProgress sums the contributions and projects the completion date from
+// the average monthly contribution since the goal was started.
+func goalProgress(goal models.Goal, transactions []models.Transaction, now time.Time) models.GoalProgress {
+	p := models.GoalProgress{Goal: goal, Contributions: []models.GoalContribution{}}
+
+	for _, a := range goal.Allocations {
+		note := a.Note
+		if note == "" {
+			note = "отложено вручную"
+		}
+		p.Contributions = append(p.Contributions, models.GoalContribution{Date: a.Date, Amount: a.Amount, Description: note})
+	}
+	for _, t := range transactions {
+		if !goalMatches(goal, t) {
+			continue
+		}
+		amount := t.Amount
+		if t.Type == models.TransactionIncome {
+			amount = -amount
+		}
+		p.Contributions = append(p.Contributions, models.GoalContribution{Date: t.Date, Amount: amount, Description: t.Description})
+	}
+	sort.SliceStable(p.Contributions, func(i, j int) bool {
+		return p.Contributions[i].Date.Before(p.Contributions[j].Date)
+	})
+
+	start := goal.CreatedAt
+	for _, c := range p.Contributions {
+		p.Saved += c.Amount
+		if c.Date.Before(start) {
+			start = c.Date
+		}
+	}
+
+	p.Remaining = math.Max(goal.Target-p.Saved, 0)
+	p.Fraction = math.Max(p.Saved, 0) / goal.Target
+
+	elapsed := math.Max(now.Sub(start).Hours()/24/daysPerMonth, 1)
+	if p.Saved > 0 {
+		p.MonthlyRate = p.Saved / elapsed
+	}
+
+	switch {
+	case p.Remaining == 0:
+		if n := len(p.Contributions); n > 0 {
+			p.Projected = p.Contributions[n-1].Date
+		}
+	case p.MonthlyRate > 0:
+		days := p.Remaining / p.MonthlyRate * daysPerMonth
+		p.Projected = now.Add(time.Duration(days * 24 * float64(time.Hour)))
+	}
+
+	if !goal.Deadline.IsZero() && p.Remaining > 0 {
+		left := goal.Deadline.Sub(now).Hours() / 24 / daysPerMonth
+		p.MonthlyNeeded = p.Remaining
+		if left > 1 {
+			p.MonthlyNeeded = p.Remaining / left
+		}
+	}
+
+	switch {
+	case p.Remaining == 0:
+		p.OnTrack = true
+	case goal.Deadline.IsZero():
+		p.OnTrack = p.MonthlyRate > 0
+	default:
+		p.OnTrack = !p.Projected.IsZero() && !p.Projected.After(goal.Deadline)
+	}
+	return p
+}
+
+func goalMatches(goal models.Goal, t models.Transaction) bool {
+	if goal.Category != "" && strings.EqualFold(t.Category, goal.Category) {
+		return true
+	}
+	return goal.Tag != "" && hasTag(t.Description, goal.Tag)
+}
+
+// hasTag reports whether the text contains #tag as a separate word, ignoring case.
+func hasTag(text, tag string) bool {
+	for _, word := range strings.Fields(text) {
+		word = strings.TrimRight(word, ".,;:!?")
+		if strings.EqualFold(word, "#"+tag) {
+			return true
+		}
+	}
+	return false
+}
+
+func findGoal(goals []models.Goal, ref string) int {
+	ref = strings.TrimSpace(ref)
+	for i, g := range goals {
+		if g.ID == ref || strings.EqualFold(g.Name, ref) {
+			return i
+		}
+	}
+	return -1
+}
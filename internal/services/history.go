@@ -0,0 +1,362 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fintrack/internal/models"
+	"fintrack/internal/storage"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	ErrNothingToUndo = errors.New("нечего отменять")
+	ErrNothingToRedo = errors.New("нечего повторять")
+)
+
+// HistoryStore persists the undo/redo stacks between runs.
+type HistoryStore interface {
+	Load() (models.History, error)
+	Save(history models.History) error
+}
+
+// History keeps the last depth mutations made through the services and
+// reverts or reapplies them directly on the storage. Undo and redo are
+// themselves reported to the auditor, so the audit log stays complete.
+type History struct {
+	storage storage.Storage
+	store   HistoryStore
+	depth   int
+	auditor Auditor
+	backup  Snapshotter
+
+	categoryObservers []CategoryObserver
+	tracked           map[string]func(models.Change) error
+
+	mu    sync.Mutex
+	group *models.HistoryEntry
+}
+
+func NewHistory(storage storage.Storage, store HistoryStore, depth int) *History {
+	return &History{
+		storage: storage,
+		store:   store,
+		depth:   depth,
+	}
+}
+
+// SetAuditor makes undo and redo report the changes they make to a.
+func (h *History) SetAuditor(a Auditor) {
+	h.auditor = a
+}
+
+// SetSnapshotter makes undo and redo back up the data before changing it.
+func (h *History) SetSnapshotter(s Snapshotter) {
+	h.backup = s
+}
+
+// ObserveCategories makes undo and redo tell o about the categories they rename.
+func (h *History) ObserveCategories(o CategoryObserver) {
+	h.categoryObservers = append(h.categoryObservers, o)
+}
+
+// track makes undo and redo hand the changes of entity to apply; it is used
+// by services whose data History does not store itself.
+func (h *History) track(entity string, apply func(models.Change) error) {
+	if h.tracked == nil {
+		h.tracked = make(map[string]func(models.Change) error)
+	}
+	h.tracked[entity] = apply
+}
+
+// Entries returns the undo and redo stacks.
+func (h *History) Entries() (models.History, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.store.Load()
+}
+
+// Group runs fn and records all mutations it makes as a single history
+// entry, so a service call that changes several records is undone in one
+// step. A nested group joins the outer one. Groups must not run
+// concurrently with other mutations.
+func (h *History) Group(label string, fn func() error) error {
+	h.mu.Lock()
+	if h.group != nil {
+		h.mu.Unlock()
+		return fn()
+	}
+	h.group = &models.HistoryEntry{Time: time.Now(), Label: label}
+	h.mu.Unlock()
+
+	fnErr := fn()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	entry := *h.group
+	h.group = nil
+
+	if len(entry.Changes) > 0 {
+		if err := h.pushEntry(entry); err != nil {
+			return errors.Join(fnErr, err)
+		}
+	}
+	return fnErr
+}
+
+func (h *History) push(action models.AuditAction, entity, id string, before, after interface{}) error {
+	change := models.Change{Action: action, Entity: entity, ID: id}
+	var err error
+	if change.Before, err = snapshot(before); err != nil {
+		return err
+	}
+	if change.After, err = snapshot(after); err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.group != nil {
+		h.group.Changes = append(h.group.Changes, change)
+		return nil
+	}
+
+	return h.pushEntry(models.HistoryEntry{
+		Time:    time.Now(),
+		Label:   describeChange(action, entity, before, after),
+		Changes: []models.Change{change},
+	})
+}
+
+// pushEntry adds a new step, drops the oldest ones beyond depth and clears redo.
+func (h *History) pushEntry(entry models.HistoryEntry) error {
+	history, err := h.store.Load()
+	if err != nil {
+		return err
+	}
+
+	history.Undo = append(history.Undo, entry)
+	if len(history.Undo) > h.depth {
+		history.Undo = history.Undo[len(history.Undo)-h.depth:]
+	}
+	history.Redo = nil
+	return h.store.Save(history)
+}
+
+// Undo reverts the most recent step and moves it to the redo stack.
+func (h *History) Undo() (models.HistoryEntry, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	history, err := h.store.Load()
+	if err != nil {
+		return models.HistoryEntry{}, err
+	}
+	if len(history.Undo) == 0 {
+		return models.HistoryEntry{}, ErrNothingToUndo
+	}
+
+	entry := history.Undo[len(history.Undo)-1]
+	if err := takeSnapshot(h.backup, "отмена: "+entry.Label); err != nil {
+		return models.HistoryEntry{}, err
+	}
+	inverses := make([]models.Change, len(entry.Changes))
+	for i, change := range entry.Changes {
+		inverses[len(inverses)-1-i] = invert(change)
+	}
+	if err := h.applyAll(inverses); err != nil {
+		return models.HistoryEntry{}, fmt.Errorf("не удалось отменить «%s»: %w", entry.Label, err)
+	}
+
+	history.Undo = history.Undo[:len(history.Undo)-1]
+	history.Redo = append(history.Redo, entry)
+	return entry, h.store.Save(history)
+}
+
+// Redo reapplies the most recently undone step.
+func (h *History) Redo() (models.HistoryEntry, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	history, err := h.store.Load()
+	if err != nil {
+		return models.HistoryEntry{}, err
+	}
+	if len(history.Redo) == 0 {
+		return models.HistoryEntry{}, ErrNothingToRedo
+	}
+
+	entry := history.Redo[len(history.Redo)-1]
+	if err := takeSnapshot(h.backup, "повтор: "+entry.Label); err != nil {
+		return models.HistoryEntry{}, err
+	}
+	if err := h.applyAll(entry.Changes); err != nil {
+		return models.HistoryEntry{}, fmt.Errorf("не удалось повторить «%s»: %w", entry.Label, err)
+	}
+
+	history.Redo = history.Redo[:len(history.Redo)-1]
+	history.Undo = append(history.Undo, entry)
+	return entry, h.store.Save(history)
+}
+
+// applyAll performs the changes in order. When one fails, the changes
+// already made are reverted, so the step is applied entirely or not at all.
+func (h *History) applyAll(changes []models.Change) error {
+	for i, change := range changes {
+		err := h.apply(change)
+		if err == nil {
+			continue
+		}
+		for j := i - 1; j >= 0; j-- {
+			if rollbackErr := h.apply(invert(changes[j])); rollbackErr != nil {
+				return errors.Join(err, fmt.Errorf("данные изменены частично: %w", rollbackErr))
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+// invert returns the change that cancels c.
+func invert(c models.Change) models.Change {
+	inverse := models.Change{Entity: c.Entity, ID: c.ID, Before: c.After, After: c.Before}
+	switch c.Action {
+	case models.AuditCreate:
+		inverse.Action = models.AuditDelete
+	case models.AuditDelete:
+		inverse.Action = models.AuditCreate
+	default:
+		inverse.Action = c.Action
+	}
+	return inverse
+}
+
+// apply performs a change on the storage, bypassing the services so that it
+// is not recorded as a new step.
+func (h *History) apply(c models.Change) error {
+	switch c.Entity {
+	case entityTransaction:
+		var before, after models.Transaction
+		if err := unmarshalSnapshot(c.Before, &before); err != nil {
+			return err
+		}
+		if err := unmarshalSnapshot(c.After, &after); err != nil {
+			return err
+		}
+
+		var err error
+		switch c.Action {
+		case models.AuditCreate:
+			err = h.storage.SaveTransaction(after)
+		case models.AuditUpdate:
+			err = h.storage.UpdateTransaction(after)
+		case models.AuditDelete:
+			err = h.storage.DeleteTransaction(c.ID)
+		default:
+			return fmt.Errorf("неизвестное действие: %s", c.Action)
+		}
+		if err != nil {
+			return err
+		}
+		return record(h.auditor, c.Action, c.Entity, c.ID, snapshotOrNil(c.Before, before), snapshotOrNil(c.After, after))
+
+	case entityCategory:
+		var before, after models.Category
+		if err := unmarshalSnapshot(c.Before, &before); err != nil {
+			return err
+		}
+		if err := unmarshalSnapshot(c.After, &after); err != nil {
+			return err
+		}
+
+		var err error
+		switch c.Action {
+		case models.AuditCreate:
+			err = h.storage.SaveCategory(after)
+		case models.AuditUpdate:
+			if err = h.storage.RenameCategory(before.Name, after.Name); err == nil {
+				err = notifyCategoryRenamed(h.categoryObservers, before.Name, after.Name)
+			}
+		case models.AuditDelete:
+			err = h.storage.DeleteCategory(c.ID)
+		default:
+			return fmt.Errorf("неизвестное действие: %s", c.Action)
+		}
+		if err != nil {
+			return err
+		}
+		return record(h.auditor, c.Action, c.Entity, c.ID, snapshotOrNil(c.Before, before), snapshotOrNil(c.After, after))
+
+	default:
+		apply, ok := h.tracked[c.Entity]
+		if !ok {
+			return fmt.Errorf("неизвестный тип записи: %s", c.Entity)
+		}
+		if err := apply(c); err != nil {
+			return err
+		}
+		return record(h.auditor, c.Action, c.Entity, c.ID, snapshotOrNil(c.Before, c.Before), snapshotOrNil(c.After, c.After))
+	}
+}
+
+// describeChange builds a short human-readable label for a single change.
+func describeChange(action models.AuditAction, entity string, before, after interface{}) string {
+	subject := after
+	if subject == nil {
+		subject = before
+	}
+
+	var what string
+	switch v := subject.(type) {
+	case models.Transaction:
+		what = fmt.Sprintf("транзакции «%s» %.2f", v.Description, v.Amount)
+	case models.Category:
+		what = fmt.Sprintf("категории «%s»", v.Name)
+	case models.EnvelopeAllocation:
+		what = fmt.Sprintf("%.2f в конверте «%s»", v.Amount, v.Category)
+	default:
+		what = entity
+	}
+
+	switch action {
+	case models.AuditCreate:
+		return "добавление " + what
+	case models.AuditUpdate:
+		return "изменение " + what
+	case models.AuditDelete:
+		return "удаление " + what
+	default:
+		return string(action) + " " + what
+	}
+}
+
+func snapshot(v interface{}) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка сериализации снимка: %w", err)
+	}
+	return data, nil
+}
+
+func unmarshalSnapshot(data json.RawMessage, v interface{}) error {
+	if len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("поврежденная запись истории: %w", err)
+	}
+	return nil
+}
+
+// snapshotOrNil returns v, or nil when the change has no such snapshot.
+func snapshotOrNil(data json.RawMessage, v interface{}) interface{} {
+	if len(data) == 0 {
+		return nil
+	}
+	return v
+}
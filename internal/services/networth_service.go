@@ -0,0 +1,364 @@
+package services
+
+import (
+	"errors"
+	"fintrack/internal/models"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrAssetNotFound is returned when no asset has the given ID or name.
+var ErrAssetNotFound = errors.New("актив не найден")
+
+const (
+	entityAsset = "asset"
+	monthLayout = "2006-01"
+)
+
+// NetWorthStore persists valued assets and net worth snapshots.
+type NetWorthStore interface {
+	Load() (models.NetWorthData, error)
+	Save(nw models.NetWorthData) error
+}
+
+// NetWorthService computes net worth as the account balance (all incomes
+// minus all expenses) plus manually valued assets and money lent, minus
+// liabilities and money borrowed.
+type NetWorthService struct {
+	store              NetWorthStore
+	transactionService *TransactionService
+	debtService        *DebtService
+	mutationHooks
+	mu sync.Mutex
+}
+
+func NewNetWorthService(store NetWorthStore, transactionService *TransactionService, debtService *DebtService) *NetWorthService {
+	return &NetWorthService{
+		store:              store,
+		transactionService: transactionService,
+		debtService:        debtService,
+	}
+}
+
+func (ns *NetWorthService) Assets() ([]models.Asset, error) {
+	nw, err := ns.store.Load()
+	return nw.Assets, err
+}
+
+// AddAsset adds an asset or liability with its first valuation.
+func (ns *NetWorthService) AddAsset(name string, kind models.AssetKind, value float64, date time.Time) (models.Asset, error) {
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return models.Asset{}, validationError("название не может быть пустым")
+	}
+	if kind != models.AssetKindAsset && kind != models.AssetKindLiability {
+		return models.Asset{}, validationError("вид должен быть %s или %s", models.AssetKindAsset, models.AssetKindLiability)
+	}
+	if value < 0 {
+		return models.Asset{}, validationError("стоимость не может быть отрицательной")
+	}
+
+	nw, err := ns.store.Load()
+	if err != nil {
+		return models.Asset{}, err
+	}
+	if findAsset(nw.Assets, name) >= 0 {
+		return models.Asset{}, validationError("%s уже есть в списке", name)
+	}
+
+	asset := models.Asset{
+		ID:         fmt.Sprintf("asset_%d", time.Now().UnixNano()),
+		Name:       name,
+		Kind:       kind,
+		Valuations: []models.Valuation{{Date: date, Value: value}},
+		CreatedAt:  time.Now(),
+	}
+	nw.Assets = append(nw.Assets, asset)
+	if err := ns.store.Save(nw); err != nil {
+		return models.Asset{}, err
+	}
+	return asset, ns.changed(models.AuditCreate, entityAsset, asset.ID, nil, asset)
+}
+
+// Revalue records a new valuation; a valuation on the same day replaces the old one.
+func (ns *NetWorthService) Revalue(ref string, value float64, date time.Time) (models.Asset, error) {
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+
+	if value < 0 {
+		return models.Asset{}, validationError("стоимость не может быть отрицательной")
+	}
+
+	nw, err := ns.store.Load()
+	if err != nil {
+		return models.Asset{}, err
+	}
+	i := findAsset(nw.Assets, ref)
+	if i < 0 {
+		return models.Asset{}, ErrAssetNotFound
+	}
+
+	before := nw.Assets[i]
+	valuations := []models.Valuation{}
+	for _, v := range before.Valuations {
+		if !sameDay(v.Date, date) {
+			valuations = append(valuations, v)
+		}
+	}
+	valuations = append(valuations, models.Valuation{Date: date, Value: value})
+	sort.SliceStable(valuations, func(a, b int) bool {
+		return valuations[a].Date.Before(valuations[b].Date)
+	})
+
+	nw.Assets[i].Valuations = valuations
+	if err := ns.store.Save(nw); err != nil {
+		return models.Asset{}, err
+	}
+	return nw.Assets[i], ns.changed(models.AuditUpdate, entityAsset, before.ID, before, nw.Assets[i])
+}
+
+func (ns *NetWorthService) DeleteAsset(ref string) error {
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+
+	nw, err := ns.store.Load()
+	if err != nil {
+		return err
+	}
+	i := findAsset(nw.Assets, ref)
+	if i < 0 {
+		return ErrAssetNotFound
+	}
+
+	deleted := nw.Assets[i]
+	if err := ns.destructive("удаление актива " + deleted.Name); err != nil {
+		return err
+	}
+	nw.Assets = append(nw.Assets[:i], nw.Assets[i+1:]...)
+	if err := ns.store.Save(nw); err != nil {
+		return err
+	}
+	return ns.changed(models.AuditDelete, entityAsset, deleted.ID, deleted, nil)
+}
+
+// At returns the net worth statement as of t.
+func (ns *NetWorthService) At(t time.Time) (models.NetWorth, error) {
+	nw, err := ns.store.Load()
+	if err != nil {
+		return models.NetWorth{}, err
+	}
+	transactions, err := ns.transactionService.GetAllTransactions()
+	if err != nil {
+		return models.NetWorth{}, err
+	}
+	debts, err := ns.debtService.Debts()
+	if err != nil {
+		return models.NetWorth{}, err
+	}
+	return netWorthAt(t, nw.Assets, debts, transactions), nil
+}
+
+// Snapshots returns the recorded monthly snapshots, oldest first.
+func (ns *NetWorthService) Snapshots() ([]models.NetWorthSnapshot, error) {
+	nw, err := ns.store.Load()
+	return nw.Snapshots, err
+}
+
+// RecordSnapshot stores the current net worth as the snapshot of the month
+// of now, replacing an earlier snapshot of the same month.
+func (ns *NetWorthService) RecordSnapshot(now time.Time) (models.NetWorthSnapshot, error) {
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+
+	nw, err := ns.store.Load()
+	if err != nil {
+		return models.NetWorthSnapshot{}, err
+	}
+	transactions, err := ns.transactionService.GetAllTransactions()
+	if err != nil {
+		return models.NetWorthSnapshot{}, err
+	}
+	debts, err := ns.debtService.Debts()
+	if err != nil {
+		return models.NetWorthSnapshot{}, err
+	}
+
+	snapshot := snapshotOf(netWorthAt(now, nw.Assets, debts, transactions), now)
+	nw.Snapshots = putSnapshot(nw.Snapshots, snapshot)
+	return snapshot, ns.store.Save(nw)
+}
+
+// Backfill computes snapshots at the end of every past month since the first
+// transaction or valuation that has none yet, and returns how many were added.
+func (ns *NetWorthService) Backfill(now time.Time) (int, error) {
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+
+	nw, err := ns.store.Load()
+	if err != nil {
+		return 0, err
+	}
+	transactions, err := ns.transactionService.GetAllTransactions()
+	if err != nil {
+		return 0, err
+	}
+	debts, err := ns.debtService.Debts()
+	if err != nil {
+		return 0, err
+	}
+
+	first := now
+	for _, t := range transactions {
+		if t.Date.Before(first) {
+			first = t.Date
+		}
+	}
+	for _, a := range nw.Assets {
+		for _, v := range a.Valuations {
+			if v.Date.Before(first) {
+				first = v.Date
+			}
+		}
+	}
+	for _, d := range debts {
+		if d.Start.Before(first) {
+			first = d.Start
+		}
+	}
+
+	recorded := make(map[string]bool, len(nw.Snapshots))
+	for _, s := range nw.Snapshots {
+		recorded[s.Month] = true
+	}
+
+	added := 0
+	current := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	for month := time.Date(first.Year(), first.Month(), 1, 0, 0, 0, 0, now.Location()); month.Before(current); month = month.AddDate(0, 1, 0) {
+		if recorded[month.Format(monthLayout)] {
+			continue
+		}
+		end := month.AddDate(0, 1, 0).Add(-time.Nanosecond)
+		nw.Snapshots = putSnapshot(nw.Snapshots, snapshotOf(netWorthAt(end, nw.Assets, debts, transactions), end))
+		added++
+	}
+
+	if added == 0 {
+		return 0, nil
+	}
+	return added, ns.store.Save(nw)
+}
+
+func netWorthAt(t time.Time, assets []models.Asset, debts []models.Debt, transactions []models.Transaction) models.NetWorth {
+	result := models.NetWorth{Date: t, Lines: []models.NetWorthLine{}}
+
+	var past []models.Transaction
+	for _, tr := range transactions {
+		if tr.Date.After(t) {
+			continue
+		}
+		past = append(past, tr)
+		if tr.Type == models.TransactionIncome {
+			result.Cash += tr.Amount
+		} else {
+			result.Cash -= tr.Amount
+		}
+	}
+	result.Cash = roundMoney(result.Cash)
+	result.Lines = append(result.Lines, models.NetWorthLine{Name: "Баланс счета", Kind: models.AssetKindAsset, Value: result.Cash})
+
+	for _, a := range assets {
+		value, ok := valueAt(a, t)
+		if !ok {
+			continue
+		}
+		result.Lines = append(result.Lines, models.NetWorthLine{Name: a.Name, Kind: a.Kind, Value: value})
+	}
+
+	for _, d := range debts {
+		if d.Start.After(t) {
+			continue
+		}
+		status := debtStatus(d, past)
+		if status.Balance <= 0 {
+			continue
+		}
+		line := models.NetWorthLine{Name: "Долг: " + d.Name, Kind: models.AssetKindLiability, Value: status.Balance}
+		if d.Direction == models.DebtLent {
+			line = models.NetWorthLine{Name: "Нам должны: " + d.Name, Kind: models.AssetKindAsset, Value: status.Balance}
+		}
+		result.Lines = append(result.Lines, line)
+	}
+
+	// the first line is the account balance, counted as cash
+	for _, l := range result.Lines[1:] {
+		if l.Kind == models.AssetKindLiability {
+			result.Liabilities += l.Value
+		} else {
+			result.Assets += l.Value
+		}
+	}
+	result.Assets = roundMoney(result.Assets)
+	result.Liabilities = roundMoney(result.Liabilities)
+	result.Total = roundMoney(result.Cash + result.Assets - result.Liabilities)
+	return result
+}
+
+// valueAt returns the latest valuation made on or before t.
+func valueAt(a models.Asset, t time.Time) (float64, bool) {
+	value, ok := 0.0, false
+	for _, v := range a.Valuations {
+		if v.Date.After(t) {
+			break
+		}
+		value, ok = v.Value, true
+	}
+	return value, ok
+}
+
+func snapshotOf(nw models.NetWorth, at time.Time) models.NetWorthSnapshot {
+	return models.NetWorthSnapshot{
+		Month:       at.Format(monthLayout),
+		TakenAt:     at,
+		Cash:        nw.Cash,
+		Assets:      nw.Assets,
+		Liabilities: nw.Liabilities,
+		Total:       nw.Total,
+	}
+}
+
+// putSnapshot replaces the snapshot of the same month or inserts it, keeping months in order.
+func putSnapshot(snapshots []models.NetWorthSnapshot, s models.NetWorthSnapshot) []models.NetWorthSnapshot {
+	for i := range snapshots {
+		if snapshots[i].Month == s.Month {
+			snapshots[i] = s
+			return snapshots
+		}
+	}
+	snapshots = append(snapshots, s)
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].Month < snapshots[j].Month
+	})
+	return snapshots
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
+
+func findAsset(assets []models.Asset, ref string) int {
+	ref = strings.TrimSpace(ref)
+	for i, a := range assets {
+		if a.ID == ref || strings.EqualFold(a.Name, ref) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,413 @@
+package services
+
+import (
+	"errors"
+	"fintrack/internal/models"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrPayeeNotFound is returned when no payee has the given ID or name.
+var ErrPayeeNotFound = errors.New("получатель не найден")
+
+const entityPayee = "payee"
+
+// Latin spellings of Russian sounds, longest first, so card statements such
+// as "PYATEROCHKA" normalize to the same key as "Пятёрочка".
+var payeeTranslit = []struct{ latin, cyrillic string }{
+	{"shch", "щ"}, {"sch", "щ"}, {"zh", "ж"}, {"kh", "х"}, {"ts", "ц"}, {"ch", "ч"},
+	{"sh", "ш"}, {"yu", "ю"}, {"ya", "я"}, {"yo", "е"}, {"ye", "е"},
+	{"a", "а"}, {"b", "б"}, {"c", "к"}, {"d", "д"}, {"e", "е"}, {"f", "ф"},
+	{"g", "г"}, {"h", "х"}, {"i", "и"}, {"j", "дж"}, {"k", "к"}, {"l", "л"},
+	{"m", "м"}, {"n", "н"}, {"o", "о"}, {"p", "п"}, {"q", "к"}, {"r", "р"},
+	{"s", "с"}, {"t", "т"}, {"u", "у"}, {"v", "в"}, {"w", "в"}, {"x", "кс"},
+	{"y", "ы"}, {"z", "з"},
+}
+
+// PayeeStore persists payees.
+type PayeeStore interface {
+	Load() ([]models.Payee, error)
+	Save(payees []models.Payee) error
+}
+
+// PayeeService keeps payees and matches transaction descriptions to them.
+type PayeeService struct {
+	store              PayeeStore
+	transactionService *TransactionService
+	mutationHooks
+	mu sync.Mutex
+}
+
+func NewPayeeService(store PayeeStore, transactionService *TransactionService) *PayeeService {
+	return &PayeeService{
+		store:              store,
+		transactionService: transactionService,
+	}
+}
+
+// CategoryRenamed moves the payees that default to a renamed category to its new name.
+func (ps *PayeeService) CategoryRenamed(oldName, newName string) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	return renameCategoryIn(ps.store, func(p *models.Payee) *string { return &p.Category }, oldName, newName)
+}
+
+func (ps *PayeeService) Payees() ([]models.Payee, error) {
+	payees, err := ps.store.Load()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(payees, func(i, j int) bool {
+		return payees[i].Name < payees[j].Name
+	})
+	return payees, nil
+}
+
+// FindPayee looks a payee up by ID or by name, ignoring case and ё.
+func (ps *PayeeService) FindPayee(ref string) (models.Payee, error) {
+	payees, err := ps.store.Load()
+	if err != nil {
+		return models.Payee{}, err
+	}
+	i := findPayee(payees, ref)
+	if i < 0 {
+		return models.Payee{}, ErrPayeeNotFound
+	}
+	return payees[i], nil
+}
+
+// Match returns the payee whose name or alias the description starts with,
+// preferring the longest match.
+func (ps *PayeeService) Match(description string) (models.Payee, bool, error) {
+	payees, err := ps.store.Load()
+	if err != nil {
+		return models.Payee{}, false, err
+	}
+	p, ok := matchPayee(payees, description)
+	return p, ok, nil
+}
+
+// Apply names the entry after the payee its description matches, keeping
+// the rest of the description, and uses the payee's default category when it
+// fits the entry's type.
+func (ps *PayeeService) Apply(entry models.QuickEntry) (models.QuickEntry, error) {
+	p, ok, err := ps.Match(entry.Description)
+	if err != nil || !ok {
+		return entry, err
+	}
+	entry.Payee, entry.Description = p.Name, Describe(p, entry.Description)
+	if p.Category == "" {
+		return entry, nil
+	}
+
+	categories, err := ps.transactionService.storage.GetCategories()
+	if err != nil {
+		return entry, err
+	}
+	for _, c := range categories {
+		if c.Name == p.Category && c.Type == string(entry.Type) {
+			entry.Category = c.Name
+		}
+	}
+	return entry, nil
+}
+
+// Describe puts the payee's name in place of the spelling of it the text
+// starts with and keeps the rest: "PYATEROCHKA продукты" becomes
+// "Пятёрочка продукты". Text that does not name the payee follows the name.
+func Describe(p models.Payee, text string) string {
+	words := strings.Fields(text)
+	keys := payeeKeys(p)
+	for n := len(words); n > 0; n-- {
+		if slices.Contains(keys, normalizePayee(strings.Join(words[:n], " "))) {
+			words = words[n:]
+			break
+		}
+	}
+	return strings.Join(append([]string{p.Name}, words...), " ")
+}
+
+// CreatePayee validates and saves a new payee.
+func (ps *PayeeService) CreatePayee(name, category string, aliases []string) (models.Payee, error) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	name = strings.TrimSpace(name)
+	if normalizePayee(name) == "" {
+		return models.Payee{}, validationError("название получателя не может быть пустым")
+	}
+	category, err := ps.category(category)
+	if err != nil {
+		return models.Payee{}, err
+	}
+
+	payees, err := ps.store.Load()
+	if err != nil {
+		return models.Payee{}, err
+	}
+	if findPayee(payees, name) >= 0 {
+		return models.Payee{}, validationError("получатель %s уже существует", name)
+	}
+
+	payee := models.Payee{
+		ID:        fmt.Sprintf("payee_%d", time.Now().UnixNano()),
+		Name:      name,
+		Category:  category,
+		CreatedAt: time.Now(),
+	}
+	for _, alias := range aliases {
+		if payee, err = addAlias(payees, payee, alias); err != nil {
+			return models.Payee{}, err
+		}
+	}
+	if err := checkPayeeKey(payees, payee, name); err != nil {
+		return models.Payee{}, err
+	}
+
+	if err := ps.store.Save(append(payees, payee)); err != nil {
+		return models.Payee{}, err
+	}
+	return payee, ps.changed(models.AuditCreate, entityPayee, payee.ID, nil, payee)
+}
+
+// AddAlias adds another spelling that belongs to the payee.
+func (ps *PayeeService) AddAlias(ref, alias string) (models.Payee, error) {
+	return ps.update(ref, func(payees []models.Payee, p models.Payee) (models.Payee, error) {
+		return addAlias(payees, p, alias)
+	})
+}
+
+// SetCategory sets the default category of the payee; an empty one clears it.
+func (ps *PayeeService) SetCategory(ref, category string) (models.Payee, error) {
+	return ps.update(ref, func(payees []models.Payee, p models.Payee) (models.Payee, error) {
+		category, err := ps.category(category)
+		if err != nil {
+			return models.Payee{}, err
+		}
+		p.Category = category
+		return p, nil
+	})
+}
+
+func (ps *PayeeService) DeletePayee(ref string) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	payees, err := ps.store.Load()
+	if err != nil {
+		return err
+	}
+	i := findPayee(payees, ref)
+	if i < 0 {
+		return ErrPayeeNotFound
+	}
+	if err := ps.destructive("удаление получателя " + payees[i].Name); err != nil {
+		return err
+	}
+
+	removed := payees[i]
+	if err := ps.store.Save(append(payees[:i], payees[i+1:]...)); err != nil {
+		return err
+	}
+	return ps.changed(models.AuditDelete, entityPayee, removed.ID, removed, nil)
+}
+
+// Top sums the expenses made since from by payee, the biggest spend first,
+// or the most frequent first when byCount is set. Descriptions that match no
+// payee are grouped by their normalized spelling.
+func (ps *PayeeService) Top(from time.Time, byCount bool) ([]models.PayeeStat, error) {
+	payees, err := ps.store.Load()
+	if err != nil {
+		return nil, err
+	}
+	transactions, err := ps.transactionService.GetAllTransactions()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(transactions, func(i, j int) bool {
+		return transactions[i].Date.Before(transactions[j].Date)
+	})
+
+	stats := map[string]*models.PayeeStat{}
+	categories := map[string]map[string]int{}
+	var keys []string
+	for _, t := range transactions {
+		if t.Type != models.TransactionExpense || t.Date.Before(from) {
+			continue
+		}
+		key, name, known := normalizePayee(t.Description), strings.TrimSpace(t.Description), false
+		if p, ok := matchPayee(payees, t.Description); ok {
+			key, name, known = p.ID, p.Name, true
+		}
+		s, ok := stats[key]
+		if !ok {
+			s = &models.PayeeStat{Payee: name, Known: known}
+			stats[key] = s
+			categories[key] = map[string]int{}
+			keys = append(keys, key)
+		}
+		if !known {
+			// the latest spelling names an unknown payee
+			s.Payee = name
+		}
+		s.Spent += t.Amount
+		s.Count++
+		s.Last = t.Date
+		categories[key][t.Category]++
+	}
+
+	result := make([]models.PayeeStat, 0, len(keys))
+	for _, key := range keys {
+		s := stats[key]
+		s.Spent = roundMoney(s.Spent)
+		s.Average = roundMoney(s.Spent / float64(s.Count))
+		s.Category = mostUsed(categories[key])
+		result = append(result, *s)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if byCount && result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Spent > result[j].Spent
+	})
+	return result, nil
+}
+
+func (ps *PayeeService) update(ref string, fn func([]models.Payee, models.Payee) (models.Payee, error)) (models.Payee, error) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	payees, err := ps.store.Load()
+	if err != nil {
+		return models.Payee{}, err
+	}
+	i := findPayee(payees, ref)
+	if i < 0 {
+		return models.Payee{}, ErrPayeeNotFound
+	}
+
+	before := payees[i]
+	before.Aliases = append([]string(nil), payees[i].Aliases...)
+	updated, err := fn(payees, payees[i])
+	if err != nil {
+		return models.Payee{}, err
+	}
+	payees[i] = updated
+	if err := ps.store.Save(payees); err != nil {
+		return models.Payee{}, err
+	}
+	return updated, ps.changed(models.AuditUpdate, entityPayee, updated.ID, before, updated)
+}
+
+// category returns the stored name of an existing category, or "" for none.
+func (ps *PayeeService) category(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", nil
+	}
+	categories, err := ps.transactionService.storage.GetCategories()
+	if err != nil {
+		return "", err
+	}
+	for _, c := range categories {
+		if strings.EqualFold(c.Name, name) {
+			return c.Name, nil
+		}
+	}
+	return "", validationError("категория не найдена")
+}
+
+func addAlias(payees []models.Payee, p models.Payee, alias string) (models.Payee, error) {
+	alias = strings.TrimSpace(alias)
+	if normalizePayee(alias) == "" {
+		return models.Payee{}, validationError("псевдоним не может быть пустым")
+	}
+	if err := checkPayeeKey(payees, p, alias); err != nil {
+		return models.Payee{}, err
+	}
+	for _, key := range payeeKeys(p) {
+		if key == normalizePayee(alias) {
+			// the spelling already matches, e.g. a transliterated name
+			return p, nil
+		}
+	}
+	p.Aliases = append(p.Aliases, alias)
+	return p, nil
+}
+
+// checkPayeeKey makes sure no other payee already answers to the spelling.
+func checkPayeeKey(payees []models.Payee, p models.Payee, spelling string) error {
+	key := normalizePayee(spelling)
+	for _, other := range payees {
+		if other.ID == p.ID {
+			continue
+		}
+		for _, k := range payeeKeys(other) {
+			if k == key {
+				return validationError("«%s» уже относится к получателю %s", spelling, other.Name)
+			}
+		}
+	}
+	return nil
+}
+
+func matchPayee(payees []models.Payee, description string) (models.Payee, bool) {
+	text := normalizePayee(description)
+	best, bestLen := -1, 0
+	for i, p := range payees {
+		for _, key := range payeeKeys(p) {
+			if (text == key || strings.HasPrefix(text, key+" ")) && len(key) > bestLen {
+				best, bestLen = i, len(key)
+			}
+		}
+	}
+	if best < 0 {
+		return models.Payee{}, false
+	}
+	return payees[best], true
+}
+
+func payeeKeys(p models.Payee) []string {
+	keys := []string{normalizePayee(p.Name)}
+	for _, a := range p.Aliases {
+		keys = append(keys, normalizePayee(a))
+	}
+	return keys
+}
+
+// normalizePayee reduces a spelling to a matching key: lower case, ё as е,
+// Latin letters transliterated, no digits or punctuation.
+func normalizePayee(s string) string {
+	s = normalizeDescription(strings.ReplaceAll(strings.ToLower(s), "ё", "е"))
+	var sb strings.Builder
+next:
+	for len(s) > 0 {
+		for _, t := range payeeTranslit {
+			if strings.HasPrefix(s, t.latin) {
+				sb.WriteString(t.cyrillic)
+				s = s[len(t.latin):]
+				continue next
+			}
+		}
+		r := []rune(s)[0]
+		sb.WriteRune(r)
+		s = s[len(string(r)):]
+	}
+	return sb.String()
+}
+
+// findPayee looks a payee up by ID or by a spelling of its name.
+func findPayee(payees []models.Payee, ref string) int {
+	ref = strings.TrimSpace(ref)
+	key := normalizePayee(ref)
+	for i, p := range payees {
+		if p.ID == ref || strings.EqualFold(p.Name, ref) || key != "" && normalizePayee(p.Name) == key {
+			return i
+		}
+	}
+	return -1
+}
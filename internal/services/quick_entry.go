@@ -0,0 +1,221 @@
+package services
+
+import (
+	"fintrack/internal/models"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// relative day words understood by ParseQuickEntry, as days before today
+var quickEntryDays = map[string]int{
+	"сегодня":   0,
+	"вчера":     1,
+	"позавчера": 2,
+}
+
+// ParseQuickEntry parses a one-line transaction such as "кофе 250 вчера
+// #работа" or "+50000 зарплата 05.10". The first number is the amount; a
+// leading plus makes it an income. A date is "сегодня", "вчера",
+// "позавчера", ДД.ММ or ДД.ММ.ГГГГ; a day without a year that would be in
+// the future is taken from the last year. Words starting with # are tags and
+// everything else is the description. The category is left for the caller.
+func ParseQuickEntry(input string, now time.Time) (models.QuickEntry, error) {
+	entry := models.QuickEntry{
+		Input: strings.TrimSpace(input),
+		Type:  models.TransactionExpense,
+		Date:  now,
+	}
+
+	var words []string
+	amountFound, dateFound := false, false
+	for _, word := range strings.Fields(input) {
+		if tag := strings.TrimLeft(word, "#"); strings.HasPrefix(word, "#") && tag != "" {
+			entry.Tags = append(entry.Tags, strings.TrimRight(tag, ".,;:!?"))
+			continue
+		}
+		if !dateFound {
+			if date, ok := parseQuickDate(word, now); ok {
+				entry.Date, dateFound = date, true
+				continue
+			}
+		}
+		if !amountFound {
+			if amount, income, ok := parseQuickAmount(word); ok {
+				entry.Amount, amountFound = amount, true
+				if income {
+					entry.Type = models.TransactionIncome
+				}
+				continue
+			}
+		}
+		words = append(words, word)
+	}
+
+	if !amountFound {
+		return models.QuickEntry{}, validationError("не найдена сумма, например: кофе 250 вчера")
+	}
+	if entry.Amount <= 0 {
+		return models.QuickEntry{}, validationError("сумма не может быть <= 0")
+	}
+	if entry.Date.After(now) {
+		return models.QuickEntry{}, validationError("дата %s еще не наступила", entry.Date.Format("02.01.2006"))
+	}
+	entry.Description = strings.Join(words, " ")
+	return entry, nil
+}
+
+// parseQuickAmount accepts 250, 250.50, 250,50, +50000 and -300 with an
+// optional ₽, р or руб suffix.
+func parseQuickAmount(word string) (float64, bool, bool) {
+	word = strings.ToLower(word)
+	for _, suffix := range []string{"₽", "руб.", "руб", "р.", "р"} {
+		if strings.HasSuffix(word, suffix) {
+			word = strings.TrimSuffix(word, suffix)
+			break
+		}
+	}
+	income := strings.HasPrefix(word, "+")
+	word = strings.TrimLeft(word, "+-")
+	if word == "" || word[0] < '0' || word[0] > '9' {
+		return 0, false, false
+	}
+	amount, err := strconv.ParseFloat(strings.Replace(word, ",", ".", 1), 64)
+	if err != nil {
+		return 0, false, false
+	}
+	return roundMoney(amount), income, true
+}
+
+func parseQuickDate(word string, now time.Time) (time.Time, bool) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if days, ok := quickEntryDays[strings.ToLower(word)]; ok {
+		if days == 0 {
+			return now, true
+		}
+		return today.AddDate(0, 0, -days), true
+	}
+
+	for _, layout := range []string{"02.01.2006", "2.1.2006", "02.01.06", "2.1.06"} {
+		if day, err := time.ParseInLocation(layout, word, now.Location()); err == nil {
+			return day, true
+		}
+	}
+	// without a year only ДД.ММ counts as a date, so 2.5 stays an amount
+	day, err := time.ParseInLocation("02.01", word, now.Location())
+	if err != nil {
+		return time.Time{}, false
+	}
+	day = time.Date(now.Year(), day.Month(), day.Day(), 0, 0, 0, 0, now.Location())
+	if day.After(today) {
+		day = day.AddDate(-1, 0, 0)
+	}
+	return day, true
+}
+
+// QuickEntry parses the input and guesses its category.
+func (ts *TransactionService) QuickEntry(input string, now time.Time) (models.QuickEntry, error) {
+	entry, err := ParseQuickEntry(input, now)
+	if err != nil {
+		return models.QuickEntry{}, err
+	}
+	entry.Category, err = ts.GuessCategory(entry)
+	if err != nil {
+		return models.QuickEntry{}, err
+	}
+	return entry, nil
+}
+
+// GuessCategory picks a category of the entry's type: a tag or word naming
+// one wins, then the category most used for the same description, then the
+// one most used for its words. It returns "" when nothing matches.
+func (ts *TransactionService) GuessCategory(entry models.QuickEntry) (string, error) {
+	transactions, err := ts.GetAllTransactions()
+	if err != nil {
+		return "", err
+	}
+	categories, err := ts.storage.GetCategories()
+	if err != nil {
+		return "", err
+	}
+	return guessCategory(entry, transactions, categories), nil
+}
+
+// AddQuickEntry saves a parsed entry; the tags are kept in the description
+// as #tag words, the way goals and filters look for them.
+func (ts *TransactionService) AddQuickEntry(entry models.QuickEntry) (models.Transaction, error) {
+	categories, err := ts.storage.GetCategories()
+	if err != nil {
+		return models.Transaction{}, err
+	}
+	entry.Category = canonicalCategory(categories, strings.TrimSpace(entry.Category))
+
+	description := entry.Description
+	if description == "" {
+		description = entry.Category
+	}
+	for _, tag := range entry.Tags {
+		description = strings.TrimSpace(description + " #" + tag)
+	}
+	return ts.AddTransactionAt(entry.Amount, entry.Category, description, string(entry.Type), entry.Date)
+}
+
+func guessCategory(entry models.QuickEntry, transactions []models.Transaction, categories []models.Category) string {
+	income := entry.Type == models.TransactionIncome
+	allowed := map[string]string{}
+	for _, c := range categories {
+		if c.IsIncome == income {
+			allowed[strings.ToLower(c.Name)] = c.Name
+		}
+	}
+
+	words := append(append([]string{}, entry.Tags...), strings.Fields(normalizeDescription(entry.Description))...)
+	for _, word := range words {
+		if name, ok := allowed[strings.ToLower(word)]; ok {
+			return name
+		}
+	}
+
+	description := normalizeDescription(entry.Description)
+	exact := map[string]int{}
+	partial := map[string]int{}
+	for _, t := range transactions {
+		name, ok := allowed[strings.ToLower(t.Category)]
+		if !ok || t.Type != entry.Type {
+			continue
+		}
+		known := normalizeDescription(t.Description)
+		if description != "" && known == description {
+			exact[name]++
+		}
+		for _, word := range strings.Fields(description) {
+			if len([]rune(word)) >= 3 && hasWord(known, word) {
+				partial[name]++
+			}
+		}
+	}
+	if name := mostUsed(exact); name != "" {
+		return name
+	}
+	return mostUsed(partial)
+}
+
+func hasWord(text, word string) bool {
+	for _, w := range strings.Fields(text) {
+		if w == word {
+			return true
+		}
+	}
+	return false
+}
+
+// mostUsed returns the key with the highest count, the first in name order on a tie.
+func mostUsed(counts map[string]int) string {
+	best := ""
+	for name, n := range counts {
+		if best == "" || n > counts[best] || (n == counts[best] && name < best) {
+			best = name
+		}
+	}
+	return best
+}
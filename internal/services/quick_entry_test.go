@@ -0,0 +1,153 @@
+package services
+
+import (
+	"fintrack/internal/models"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseQuickEntry(t *testing.T) {
+	now := time.Date(2025, time.March, 15, 14, 30, 0, 0, time.Local)
+
+	tests := []struct {
+		input           string
+		wantAmount      float64
+		wantType        models.TransactionType
+		wantDate        time.Time
+		wantDescription string
+		wantTags        []string
+		wantErr         bool
+	}{
+		{
+			input:           "кофе 250 вчера #работа",
+			wantAmount:      250,
+			wantType:        models.TransactionExpense,
+			wantDate:        date(2025, time.March, 14),
+			wantDescription: "кофе",
+			wantTags:        []string{"работа"},
+		},
+		{
+			input:           "+50000 зарплата 05.10",
+			wantAmount:      50000,
+			wantType:        models.TransactionIncome,
+			wantDate:        date(2024, time.October, 5),
+			wantDescription: "зарплата",
+		},
+		{
+			input:           "такси 350,50р 10.03",
+			wantAmount:      350.5,
+			wantType:        models.TransactionExpense,
+			wantDate:        date(2025, time.March, 10),
+			wantDescription: "такси",
+		},
+		{
+			input:           "яблоки 2.5 кг",
+			wantAmount:      2.5,
+			wantType:        models.TransactionExpense,
+			wantDate:        now,
+			wantDescription: "яблоки кг",
+		},
+		{
+			input:           "12.03.2025 книга 700руб.",
+			wantAmount:      700,
+			wantType:        models.TransactionExpense,
+			wantDate:        date(2025, time.March, 12),
+			wantDescription: "книга",
+		},
+		{
+			input:           "подарок 1.2.25 1500₽ #семья #праздник!",
+			wantAmount:      1500,
+			wantType:        models.TransactionExpense,
+			wantDate:        date(2025, time.February, 1),
+			wantDescription: "подарок",
+			wantTags:        []string{"семья", "праздник"},
+		},
+		{
+			input:           "-300 такси позавчера",
+			wantAmount:      300,
+			wantType:        models.TransactionExpense,
+			wantDate:        date(2025, time.March, 13),
+			wantDescription: "такси",
+		},
+		{
+			input:           "вчера 100 сегодня 200",
+			wantAmount:      100,
+			wantType:        models.TransactionExpense,
+			wantDate:        date(2025, time.March, 14),
+			wantDescription: "сегодня 200",
+		},
+		{
+			input:      "  сегодня 99.999  ",
+			wantAmount: 100,
+			wantType:   models.TransactionExpense,
+			wantDate:   now,
+		},
+		{input: "кофе", wantErr: true},
+		{input: "кофе 0", wantErr: true},
+		{input: "кофе 250 20.03.2025", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			entry, err := ParseQuickEntry(tt.input, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if entry.Amount != tt.wantAmount {
+				t.Errorf("amount = %.2f, want %.2f", entry.Amount, tt.wantAmount)
+			}
+			if entry.Type != tt.wantType {
+				t.Errorf("type = %s, want %s", entry.Type, tt.wantType)
+			}
+			if !entry.Date.Equal(tt.wantDate) {
+				t.Errorf("date = %s, want %s", entry.Date, tt.wantDate)
+			}
+			if entry.Description != tt.wantDescription {
+				t.Errorf("description = %q, want %q", entry.Description, tt.wantDescription)
+			}
+			if !reflect.DeepEqual(entry.Tags, tt.wantTags) {
+				t.Errorf("tags = %v, want %v", entry.Tags, tt.wantTags)
+			}
+		})
+	}
+}
+
+func TestGuessCategory(t *testing.T) {
+	categories := []models.Category{{Name: "Продукты"}, {Name: "Кафе"}, {Name: "Зарплата", IsIncome: true}}
+	transactions := []models.Transaction{
+		{Description: "Магазин у дома", Category: "Продукты", Type: models.TransactionExpense},
+		{Description: "МАГАЗИН У ДОМА 24", Category: "продукты", Type: models.TransactionExpense},
+		{Description: "Магазин кофе", Category: "Кафе", Type: models.TransactionExpense},
+		{Description: "Кофейня", Category: "Кафе", Type: models.TransactionExpense},
+		{Description: "Кофейня", Category: "Удаленная", Type: models.TransactionExpense},
+		{Description: "Аванс", Category: "Зарплата", Type: models.TransactionIncome},
+	}
+
+	tests := []struct {
+		name  string
+		entry models.QuickEntry
+		want  string
+	}{
+		{"tag names a category", models.QuickEntry{Description: "обед", Tags: []string{"кафе"}, Type: models.TransactionExpense}, "Кафе"},
+		{"word names a category", models.QuickEntry{Description: "продукты на неделю", Type: models.TransactionExpense}, "Продукты"},
+		{"same description", models.QuickEntry{Description: "кофейня", Type: models.TransactionExpense}, "Кафе"},
+		{"most used for a word", models.QuickEntry{Description: "магазин", Type: models.TransactionExpense}, "Продукты"},
+		{"short words are ignored", models.QuickEntry{Description: "у", Type: models.TransactionExpense}, ""},
+		{"only categories of the same type", models.QuickEntry{Description: "кафе", Type: models.TransactionIncome}, ""},
+		{"income", models.QuickEntry{Description: "аванс", Type: models.TransactionIncome}, "Зарплата"},
+		{"nothing matches", models.QuickEntry{Description: "билеты", Type: models.TransactionExpense}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := guessCategory(tt.entry, transactions, categories); got != tt.want {
+				t.Errorf("guessCategory() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
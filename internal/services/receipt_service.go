@@ -0,0 +1,192 @@
+package services
+
+import (
+	"errors"
+	"fintrack/internal/models"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const entityReceipt = "receipt"
+
+// ReceiptStore persists the receipts entered from QR codes.
+type ReceiptStore interface {
+	Load() ([]models.Receipt, error)
+	Save(receipts []models.Receipt) error
+}
+
+// ReceiptService turns fiscal receipt QR codes into transactions and keeps
+// the fiscal data of each one, so the same receipt is not entered twice.
+type ReceiptService struct {
+	store              ReceiptStore
+	transactionService *TransactionService
+	mutationHooks
+	mu sync.Mutex
+}
+
+func NewReceiptService(store ReceiptStore, transactionService *TransactionService) *ReceiptService {
+	return &ReceiptService{
+		store:              store,
+		transactionService: transactionService,
+	}
+}
+
+// ParseReceiptQR parses a QR string such as
+// "t=20261017T1230&s=1250.00&fn=...&i=...&fp=...&n=1". The receipt ID is
+// made of the fiscal drive, document number and fiscal sign, which together
+// identify a receipt.
+func ParseReceiptQR(raw string) (models.Receipt, error) {
+	raw = strings.TrimSpace(raw)
+	values, err := url.ParseQuery(raw)
+	if err != nil {
+		return models.Receipt{}, validationError("не удалось разобрать QR-код чека: %v", err)
+	}
+	for _, key := range []string{"t", "s", "fn", "i", "fp"} {
+		if strings.TrimSpace(values.Get(key)) == "" {
+			return models.Receipt{}, validationError("в QR-коде чека нет поля %s", key)
+		}
+	}
+
+	receipt := models.Receipt{
+		FN:        strings.TrimSpace(values.Get("fn")),
+		FD:        strings.TrimSpace(values.Get("i")),
+		FP:        strings.TrimSpace(values.Get("fp")),
+		Operation: 1,
+		Raw:       raw,
+	}
+	receipt.ID = receipt.FN + "-" + receipt.FD + "-" + receipt.FP
+
+	var parsed bool
+	for _, layout := range []string{"20060102T150405", "20060102T1504"} {
+		if t, err := time.ParseInLocation(layout, values.Get("t"), time.Local); err == nil {
+			receipt.Time, parsed = t, true
+			break
+		}
+	}
+	if !parsed {
+		return models.Receipt{}, validationError("некорректное время чека: %s", values.Get("t"))
+	}
+
+	amount, err := strconv.ParseFloat(strings.Replace(values.Get("s"), ",", ".", 1), 64)
+	if err != nil || amount <= 0 {
+		return models.Receipt{}, validationError("некорректная сумма чека: %s", values.Get("s"))
+	}
+	receipt.Amount = roundMoney(amount)
+
+	if n := values.Get("n"); n != "" {
+		receipt.Operation, err = strconv.Atoi(n)
+		if err != nil || receipt.Operation < 1 || receipt.Operation > 4 {
+			return models.Receipt{}, validationError("неизвестный тип операции чека: %s", n)
+		}
+	}
+	return receipt, nil
+}
+
+// ReceiptType is the transaction type for the buyer: a purchase and a
+// refund of an expense are expenses, a refund of a purchase is an income.
+func ReceiptType(receipt models.Receipt) models.TransactionType {
+	if receipt.Operation == 2 || receipt.Operation == 3 {
+		return models.TransactionIncome
+	}
+	return models.TransactionExpense
+}
+
+func (rs *ReceiptService) Receipts() ([]models.Receipt, error) {
+	return rs.store.Load()
+}
+
+// Duplicate returns the transaction already created from the same receipt.
+// A receipt whose transaction was deleted can be entered again.
+func (rs *ReceiptService) Duplicate(receipt models.Receipt) (models.Transaction, bool, error) {
+	receipts, err := rs.store.Load()
+	if err != nil {
+		return models.Transaction{}, false, err
+	}
+	for _, r := range receipts {
+		if r.ID != receipt.ID {
+			continue
+		}
+		t, err := rs.transactionService.GetTransactionByID(r.TransactionID)
+		if err == nil {
+			return t, true, nil
+		}
+	}
+	return models.Transaction{}, false, nil
+}
+
+// Similar returns transactions of the receipt's type, amount and day that
+// were not entered from a receipt: probably the same purchase typed by hand.
+func (rs *ReceiptService) Similar(receipt models.Receipt) ([]models.Transaction, error) {
+	receipts, err := rs.store.Load()
+	if err != nil {
+		return nil, err
+	}
+	transactions, err := rs.transactionService.GetAllTransactions()
+	if err != nil {
+		return nil, err
+	}
+	linked := make(map[string]bool, len(receipts))
+	for _, r := range receipts {
+		linked[r.TransactionID] = true
+	}
+
+	var result []models.Transaction
+	for _, t := range transactions {
+		if !linked[t.ID] && t.Type == ReceiptType(receipt) && t.Amount == receipt.Amount && sameDay(t.Date.Local(), receipt.Time) {
+			result = append(result, t)
+		}
+	}
+	return result, nil
+}
+
+// Add creates the transaction for the receipt and stores its fiscal data.
+// An empty description becomes "Чек от ДД.ММ.ГГГГ"; an empty category is
+// guessed from the description.
+func (rs *ReceiptService) Add(receipt models.Receipt, category, description string) (models.Transaction, error) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	if t, ok, err := rs.Duplicate(receipt); err != nil {
+		return models.Transaction{}, err
+	} else if ok {
+		return models.Transaction{}, validationError("этот чек уже внесен: %s от %s", t.Description, t.Date.Format("02.01.2006"))
+	}
+
+	receipts, err := rs.store.Load()
+	if err != nil {
+		return models.Transaction{}, err
+	}
+
+	if strings.TrimSpace(description) == "" {
+		description = "Чек от " + receipt.Time.Format("02.01.2006")
+	}
+	if strings.TrimSpace(category) == "" {
+		category, err = rs.transactionService.GuessCategory(models.QuickEntry{Type: ReceiptType(receipt), Description: description})
+		if err != nil {
+			return models.Transaction{}, err
+		}
+	}
+	transaction, addErr := rs.transactionService.AddTransactionAt(receipt.Amount, category, description, string(ReceiptType(receipt)), receipt.Time)
+	if transaction.ID == "" {
+		return models.Transaction{}, addErr
+	}
+
+	// the transaction is saved even when addErr is set, so the receipt is
+	// kept too and the error reported after it
+	receipt.TransactionID = transaction.ID
+	receipt.CreatedAt = time.Now()
+	kept := receipts[:0]
+	for _, r := range receipts {
+		if r.ID != receipt.ID {
+			kept = append(kept, r)
+		}
+	}
+	if err := rs.store.Save(append(kept, receipt)); err != nil {
+		return transaction, errors.Join(addErr, fmt.Errorf("транзакция сохранена, но данные чека не записаны: %w", err))
+	}
+	return transaction, errors.Join(addErr, rs.changed(models.AuditCreate, entityReceipt, receipt.ID, nil, receipt))
+}
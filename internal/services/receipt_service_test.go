@@ -0,0 +1,97 @@
+package services
+
+import (
+	"fintrack/internal/models"
+	"testing"
+	"time"
+)
+
+func TestParseReceiptQR(t *testing.T) {
+	tests := []struct {
+		name          string
+		raw           string
+		wantID        string
+		wantTime      time.Time
+		wantAmount    float64
+		wantOperation int
+		wantErr       bool
+	}{
+		{
+			name:          "purchase",
+			raw:           "t=20261017T1230&s=1250.00&fn=9287440300090728&i=12345&fp=3522207165&n=1",
+			wantID:        "9287440300090728-12345-3522207165",
+			wantTime:      time.Date(2026, time.October, 17, 12, 30, 0, 0, time.Local),
+			wantAmount:    1250,
+			wantOperation: 1,
+		},
+		{
+			name:          "seconds, comma and no operation",
+			raw:           "  t=20261017T123045&s=99,9&fn=1&i=2&fp=3  ",
+			wantID:        "1-2-3",
+			wantTime:      time.Date(2026, time.October, 17, 12, 30, 45, 0, time.Local),
+			wantAmount:    99.9,
+			wantOperation: 1,
+		},
+		{
+			name:          "refund of a purchase",
+			raw:           "t=20261017T1230&s=500.00&fn=1&i=2&fp=3&n=2",
+			wantID:        "1-2-3",
+			wantTime:      time.Date(2026, time.October, 17, 12, 30, 0, 0, time.Local),
+			wantAmount:    500,
+			wantOperation: 2,
+		},
+		{name: "missing fiscal sign", raw: "t=20261017T1230&s=1250.00&fn=1&i=2", wantErr: true},
+		{name: "blank field", raw: "t=20261017T1230&s=1250.00&fn=%20&i=2&fp=3", wantErr: true},
+		{name: "bad time", raw: "t=17.10.2026&s=1250.00&fn=1&i=2&fp=3", wantErr: true},
+		{name: "zero amount", raw: "t=20261017T1230&s=0&fn=1&i=2&fp=3", wantErr: true},
+		{name: "bad amount", raw: "t=20261017T1230&s=abc&fn=1&i=2&fp=3", wantErr: true},
+		{name: "unknown operation", raw: "t=20261017T1230&s=1250.00&fn=1&i=2&fp=3&n=5", wantErr: true},
+		{name: "malformed query", raw: "t=%zz&s=1&fn=1&i=2&fp=3", wantErr: true},
+		{name: "not a receipt", raw: "https://example.com", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receipt, err := ParseReceiptQR(tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !IsValidationError(err) {
+					t.Errorf("error %v is not a validation error", err)
+				}
+				return
+			}
+			if receipt.ID != tt.wantID {
+				t.Errorf("ID = %q, want %q", receipt.ID, tt.wantID)
+			}
+			if !receipt.Time.Equal(tt.wantTime) {
+				t.Errorf("time = %s, want %s", receipt.Time, tt.wantTime)
+			}
+			if receipt.Amount != tt.wantAmount {
+				t.Errorf("amount = %.2f, want %.2f", receipt.Amount, tt.wantAmount)
+			}
+			if receipt.Operation != tt.wantOperation {
+				t.Errorf("operation = %d, want %d", receipt.Operation, tt.wantOperation)
+			}
+		})
+	}
+}
+
+func TestReceiptType(t *testing.T) {
+	tests := []struct {
+		operation int
+		want      models.TransactionType
+	}{
+		{1, models.TransactionExpense},
+		{2, models.TransactionIncome},
+		{3, models.TransactionIncome},
+		{4, models.TransactionExpense},
+	}
+
+	for _, tt := range tests {
+		if got := ReceiptType(models.Receipt{Operation: tt.operation}); got != tt.want {
+			t.Errorf("ReceiptType(%d) = %s, want %s", tt.operation, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"errors"
+	"fintrack/internal/models"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrRecurringNotFound is returned when no recurring item has the given ID or description.
+var ErrRecurringNotFound = errors.New("регулярный платеж не найден")
+
+const entityRecurring = "recurring"
+
+// RecurringStore persists recurring income and expense templates.
+type RecurringStore interface {
+	Load() ([]models.RecurringItem, error)
+	Save(items []models.RecurringItem) error
+}
+
+type RecurringService struct {
+	store              RecurringStore
+	transactionService *TransactionService
+	mutationHooks
+	mu sync.Mutex
+}
+
+func NewRecurringService(store RecurringStore, transactionService *TransactionService) *RecurringService {
+	return &RecurringService{
+		store:              store,
+		transactionService: transactionService,
+	}
+}
+
+// CategoryRenamed moves the recurring items of a renamed category to its new name.
+func (rs *RecurringService) CategoryRenamed(oldName, newName string) error {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	return renameCategoryIn(rs.store, func(item *models.RecurringItem) *string { return &item.Category }, oldName, newName)
+}
+
+func (rs *RecurringService) Items() ([]models.RecurringItem, error) {
+	return rs.store.Load()
+}
+
+// AddItem validates and saves a new recurring item; ID and creation time are set here.
+func (rs *RecurringService) AddItem(item models.RecurringItem) (models.RecurringItem, error) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	item.Description = strings.TrimSpace(item.Description)
+	if err := rs.transactionService.checkInput(item.Amount, item.Category, item.Description, string(item.Type)); err != nil {
+		return models.RecurringItem{}, err
+	}
+	switch item.Frequency {
+	case models.FrequencyWeekly, models.FrequencyMonthly, models.FrequencyYearly:
+	default:
+		return models.RecurringItem{}, validationError("периодичность должна быть %s, %s или %s",
+			models.FrequencyWeekly, models.FrequencyMonthly, models.FrequencyYearly)
+	}
+	if item.Start.IsZero() {
+		item.Start = time.Now()
+	}
+	if !item.Until.IsZero() && item.Until.Before(item.Start) {
+		return models.RecurringItem{}, validationError("дата окончания раньше даты начала")
+	}
+
+	items, err := rs.store.Load()
+	if err != nil {
+		return models.RecurringItem{}, err
+	}
+
+	item.ID = fmt.Sprintf("rec_%d", time.Now().UnixNano())
+	item.CreatedAt = time.Now()
+	if err := rs.store.Save(append(items, item)); err != nil {
+		return models.RecurringItem{}, err
+	}
+	return item, rs.changed(models.AuditCreate, entityRecurring, item.ID, nil, item)
+}
+
+func (rs *RecurringService) DeleteItem(ref string) error {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	items, err := rs.store.Load()
+	if err != nil {
+		return err
+	}
+	i := findRecurring(items, ref)
+	if i < 0 {
+		return ErrRecurringNotFound
+	}
+
+	deleted := items[i]
+	if err := rs.destructive("удаление регулярного платежа " + deleted.Description); err != nil {
+		return err
+	}
+	if err := rs.store.Save(append(items[:i], items[i+1:]...)); err != nil {
+		return err
+	}
+	return rs.changed(models.AuditDelete, entityRecurring, deleted.ID, deleted, nil)
+}
+
+// Occurrences returns the dates of the item falling within [from, to).
+func Occurrences(item models.RecurringItem, from, to time.Time) []time.Time {
+	var dates []time.Time
+	for n := 0; ; n++ {
+		d := nthOccurrence(item, n)
+		if !d.Before(to) || (!item.Until.IsZero() && d.After(item.Until)) {
+			return dates
+		}
+		if !d.Before(from) {
+			dates = append(dates, d)
+		}
+	}
+}
+
+// nthOccurrence counts from Start rather than from the previous date, so
+// monthly items started on the 31st do not drift to earlier days.
+func nthOccurrence(item models.RecurringItem, n int) time.Time {
+	switch item.Frequency {
+	case models.FrequencyWeekly:
+		return item.Start.AddDate(0, 0, 7*n)
+	case models.FrequencyYearly:
+		return addMonths(item.Start, 12*n)
+	default:
+		return addMonths(item.Start, n)
+	}
+}
+
+// findRecurring looks an item up by ID or by description, ignoring case.
+func findRecurring(items []models.RecurringItem, ref string) int {
+	ref = strings.TrimSpace(ref)
+	for i, item := range items {
+		if item.ID == ref || strings.EqualFold(item.Description, ref) {
+			return i
+		}
+	}
+	return -1
+}
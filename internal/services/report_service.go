@@ -0,0 +1,309 @@
+package services
+
+import (
+	"fintrack/internal/models"
+	"fmt"
+	"sort"
+	"time"
+)
+
+type ReportService struct {
+	transactionService *TransactionService
+}
+
+func NewReportService(transactionService *TransactionService) *ReportService {
+	return &ReportService{
+		transactionService: transactionService,
+	}
+}
+
+// ParsePeriod converts user input (month/quarter/year or their russian names) to a models.Period.
+func ParsePeriod(s string) (models.Period, error) {
+	switch s {
+	case "month", "m", "месяц":
+		return models.PeriodMonth, nil
+	case "quarter", "q", "квартал":
+		return models.PeriodQuarter, nil
+	case "year", "y", "год":
+		return models.PeriodYear, nil
+	}
+	return "", fmt.Errorf("неизвестный период: %s", s)
+}
+
+// PeriodStart returns the beginning of the period that contains t.
+func PeriodStart(period models.Period, t time.Time) time.Time {
+	switch period {
+	case models.PeriodQuarter:
+		month := time.Month((int(t.Month())-1)/3*3 + 1)
+		return time.Date(t.Year(), month, 1, 0, 0, 0, 0, t.Location())
+	case models.PeriodYear:
+		return time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, t.Location())
+	default:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	}
+}
+
+// nextPeriod returns the beginning of the period following the one starting at start.
+func nextPeriod(period models.Period, start time.Time) time.Time {
+	switch period {
+	case models.PeriodQuarter:
+		return start.AddDate(0, 3, 0)
+	case models.PeriodYear:
+		return start.AddDate(1, 0, 0)
+	default:
+		return start.AddDate(0, 1, 0)
+	}
+}
+
+func periodLabel(period models.Period, start time.Time) string {
+	switch period {
+	case models.PeriodQuarter:
+		return fmt.Sprintf("%d-Q%d", start.Year(), (int(start.Month())-1)/3+1)
+	case models.PeriodYear:
+		return fmt.Sprintf("%d", start.Year())
+	default:
+		return start.Format("2006-01")
+	}
+}
+
+// Summaries groups all transactions by period and returns one summary per period,
+// from the oldest transaction to the newest, including empty periods in between.
+func (rs *ReportService) Summaries(period models.Period) ([]models.PeriodSummary, error) {
+	transactions, err := rs.transactionService.GetAllTransactions()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(transactions) == 0 {
+		return []models.PeriodSummary{}, nil
+	}
+
+	first, last := transactions[0].Date, transactions[0].Date
+	for _, t := range transactions {
+		if t.Date.Before(first) {
+			first = t.Date
+		}
+		if t.Date.After(last) {
+			last = t.Date
+		}
+	}
+
+	buckets := make(map[string][]models.Transaction)
+	for _, t := range transactions {
+		start := PeriodStart(period, t.Date.Local())
+		key := periodLabel(period, start)
+		buckets[key] = append(buckets[key], t)
+	}
+
+	var summaries []models.PeriodSummary
+	now := time.Now()
+	end := PeriodStart(period, last.Local())
+	for start := PeriodStart(period, first.Local()); !start.After(end); start = nextPeriod(period, start) {
+		label := periodLabel(period, start)
+		summary := summarize(buckets[label], start, nextPeriod(period, start), now)
+		summary.Period = period
+		summary.Label = label
+
+		if n := len(summaries); n > 0 {
+			prev := summaries[n-1]
+			summary.HasPrevious = true
+			summary.IncomeDelta = summary.Income - prev.Income
+			summary.ExpenseDelta = summary.Expense - prev.Expense
+			summary.BalanceDelta = summary.Balance - prev.Balance
+		}
+
+		summaries = append(summaries, summary)
+	}
+
+	return summaries, nil
+}
+
+// Summary returns the summary of the period that contains t.
+func (rs *ReportService) Summary(period models.Period, t time.Time) (models.PeriodSummary, error) {
+	summaries, err := rs.Summaries(period)
+	if err != nil {
+		return models.PeriodSummary{}, err
+	}
+
+	label := periodLabel(period, PeriodStart(period, t))
+	for _, s := range summaries {
+		if s.Label == label {
+			return s, nil
+		}
+	}
+
+	start := PeriodStart(period, t)
+	summary := summarize(nil, start, nextPeriod(period, start), time.Now())
+	summary.Period = period
+	summary.Label = label
+	return summary, nil
+}
+
+func summarize(transactions []models.Transaction, start, end, now time.Time) models.PeriodSummary {
+	summary := models.PeriodSummary{
+		Start: start,
+		End:   end,
+		Count: len(transactions),
+	}
+
+	type key struct {
+		category string
+		typ      models.TransactionType
+	}
+	totals := make(map[key]*models.CategoryTotal)
+	expenseCount := 0
+
+	for _, t := range transactions {
+		if t.Type == models.TransactionExpense {
+			summary.Expense += t.Amount
+			expenseCount++
+		} else {
+			summary.Income += t.Amount
+		}
+
+		k := key{t.Category, t.Type}
+		if totals[k] == nil {
+			totals[k] = &models.CategoryTotal{Category: t.Category, Type: t.Type}
+		}
+		totals[k].Total += t.Amount
+		totals[k].Count++
+	}
+
+	summary.Balance = summary.Income - summary.Expense
+
+	if summary.Income > 0 {
+		summary.SavingsRate = summary.Balance / summary.Income * 100
+	}
+
+	if expenseCount > 0 {
+		summary.AvgExpense = summary.Expense / float64(expenseCount)
+	}
+
+	// the current period is averaged over the days that have already passed
+	last := end
+	if now.Before(end) && now.After(start) {
+		last = now
+	}
+	if days := last.Sub(start).Hours() / 24; days > 0 {
+		if days < 1 {
+			days = 1
+		}
+		summary.AvgDailyExpense = summary.Expense / days
+	}
+
+	for _, total := range totals {
+		base := summary.Income
+		if total.Type == models.TransactionExpense {
+			base = summary.Expense
+		}
+		if base > 0 {
+			total.Share = total.Total / base * 100
+		}
+		summary.Categories = append(summary.Categories, *total)
+	}
+
+	sort.Slice(summary.Categories, func(i, j int) bool {
+		if summary.Categories[i].Type != summary.Categories[j].Type {
+			return summary.Categories[i].Type == models.TransactionIncome
+		}
+		return summary.Categories[i].Total > summary.Categories[j].Total
+	})
+
+	return summary
+}
+
+// ExpensesByCategory sums expenses per category for transactions in [from, to),
+// sorted by the largest total first. Zero times leave the range open.
+func (rs *ReportService) ExpensesByCategory(from, to time.Time) ([]models.CategoryTotal, error) {
+	transactions, err := rs.transactionService.GetAllTransactions()
+	if err != nil {
+		return nil, err
+	}
+
+	var inRange []models.Transaction
+	for _, t := range transactions {
+		if t.Type != models.TransactionExpense {
+			continue
+		}
+		if !from.IsZero() && t.Date.Before(from) {
+			continue
+		}
+		if !to.IsZero() && !t.Date.Before(to) {
+			continue
+		}
+		inRange = append(inRange, t)
+	}
+
+	return summarize(inRange, from, to, time.Now()).Categories, nil
+}
+
+// DailyTotals returns one entry per calendar day in [from, to], including days without transactions.
+func (rs *ReportService) DailyTotals(from, to time.Time) ([]models.DailyTotal, error) {
+	transactions, err := rs.transactionService.GetAllTransactions()
+	if err != nil {
+		return nil, err
+	}
+
+	from = startOfDay(from)
+	to = startOfDay(to)
+
+	byDay := make(map[string]*models.DailyTotal)
+	var days []models.DailyTotal
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		days = append(days, models.DailyTotal{Date: day})
+	}
+	for i := range days {
+		byDay[days[i].Date.Format("2006-01-02")] = &days[i]
+	}
+
+	for _, t := range transactions {
+		total, ok := byDay[t.Date.Local().Format("2006-01-02")]
+		if !ok {
+			continue
+		}
+		if t.Type == models.TransactionExpense {
+			total.Expense += t.Amount
+		} else {
+			total.Income += t.Amount
+		}
+	}
+
+	return days, nil
+}
+
+// DailyExpenses returns daily totals for the last n days, today included.
+func (rs *ReportService) DailyExpenses(n int) ([]models.DailyTotal, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("количество дней должно быть положительным")
+	}
+	today := time.Now()
+	return rs.DailyTotals(today.AddDate(0, 0, -(n-1)), today)
+}
+
+func startOfDay(t time.Time) time.Time {
+	t = t.Local()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// TransactionsOnDay returns all transactions made on the calendar day of t.
+func (rs *ReportService) TransactionsOnDay(t time.Time) ([]models.Transaction, error) {
+	transactions, err := rs.transactionService.GetAllTransactions()
+	if err != nil {
+		return nil, err
+	}
+
+	day := startOfDay(t)
+	next := day.AddDate(0, 0, 1)
+
+	var result []models.Transaction
+	for _, tx := range transactions {
+		if !tx.Date.Before(day) && tx.Date.Before(next) {
+			result = append(result, tx)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Date.Before(result[j].Date)
+	})
+	return result, nil
+}
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"fintrack/internal/models"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// subscription periods: expected interval in days, allowed deviation and
+// the number of charges needed before a series counts as a subscription
+var subscriptionPeriods = []struct {
+	frequency models.Frequency
+	days      float64
+	tolerance float64
+	minCount  int
+	perYear   float64
+}{
+	{models.FrequencyWeekly, 7, 1, 3, 52},
+	{models.FrequencyMonthly, 30.44, 4, 3, 12},
+	{models.FrequencyYearly, 365.25, 12, 2, 1},
+}
+
+// charges of a subscription may differ from their median by this share
+const subscriptionAmountTolerance = 0.15
+
+// SubscriptionService finds periodic charges in the history and turns them
+// into recurring items.
+type SubscriptionService struct {
+	transactionService *TransactionService
+	recurringService   *RecurringService
+}
+
+func NewSubscriptionService(transactionService *TransactionService, recurringService *RecurringService) *SubscriptionService {
+	return &SubscriptionService{
+		transactionService: transactionService,
+		recurringService:   recurringService,
+	}
+}
+
+// Detect groups expenses by category and normalized description and keeps
+// the groups whose charges have a similar amount and a regular weekly,
+// monthly or yearly interval. The most expensive per year come first.
+func (ss *SubscriptionService) Detect(now time.Time) ([]models.Subscription, error) {
+	transactions, err := ss.transactionService.GetAllTransactions()
+	if err != nil {
+		return nil, err
+	}
+	items, err := ss.recurringService.Items()
+	if err != nil {
+		return nil, err
+	}
+
+	groups := map[string][]models.Transaction{}
+	for _, t := range transactions {
+		if t.Type != models.TransactionExpense {
+			continue
+		}
+		key := strings.ToLower(t.Category) + "|" + normalizeDescription(t.Description)
+		groups[key] = append(groups[key], t)
+	}
+
+	result := []models.Subscription{}
+	for _, group := range groups {
+		sort.Slice(group, func(i, j int) bool {
+			return group[i].Date.Before(group[j].Date)
+		})
+		sub, ok := detectSubscription(group, now)
+		if !ok {
+			continue
+		}
+		last := group[len(group)-1]
+		sub.Tracked = isRecurring(last, items)
+		result = append(result, sub)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].AnnualCost != result[j].AnnualCost {
+			return result[i].AnnualCost > result[j].AnnualCost
+		}
+		return result[i].Description < result[j].Description
+	})
+	return result, nil
+}
+
+// Track adds the subscription as a recurring expense starting at its next expected date.
+func (ss *SubscriptionService) Track(sub models.Subscription) (models.RecurringItem, error) {
+	if sub.Tracked {
+		return models.RecurringItem{}, validationError("подписка «%s» уже есть в регулярных платежах", sub.Description)
+	}
+	return ss.recurringService.AddItem(models.RecurringItem{
+		Description: sub.Description,
+		Amount:      sub.Amount,
+		Type:        models.TransactionExpense,
+		Category:    sub.Category,
+		Frequency:   sub.Frequency,
+		Start:       sub.Next,
+	})
+}
+
+// detectSubscription checks one series of charges sorted by date.
+func detectSubscription(charges []models.Transaction, now time.Time) (models.Subscription, bool) {
+	if len(charges) < 2 {
+		return models.Subscription{}, false
+	}
+
+	amounts := make([]float64, len(charges))
+	for i, t := range charges {
+		amounts[i] = t.Amount
+	}
+	amount := median(amounts)
+	for _, a := range amounts {
+		if math.Abs(a-amount) > amount*subscriptionAmountTolerance {
+			return models.Subscription{}, false
+		}
+	}
+
+	intervals := make([]float64, len(charges)-1)
+	for i := 1; i < len(charges); i++ {
+		intervals[i-1] = charges[i].Date.Sub(charges[i-1].Date).Hours() / 24
+	}
+	interval := median(intervals)
+
+	for _, p := range subscriptionPeriods {
+		if len(charges) < p.minCount || math.Abs(interval-p.days) > p.tolerance {
+			continue
+		}
+		for _, d := range intervals {
+			if math.Abs(d-p.days) > p.tolerance {
+				return models.Subscription{}, false
+			}
+		}
+
+		first, last := charges[0], charges[len(charges)-1]
+		sub := models.Subscription{
+			Description: strings.TrimSpace(last.Description),
+			Category:    last.Category,
+			Frequency:   p.frequency,
+			Amount:      last.Amount,
+			Occurrences: len(charges),
+			First:       first.Date,
+			Last:        last.Date,
+			Next:        nthOccurrence(models.RecurringItem{Frequency: p.frequency, Start: last.Date}, 1),
+			AnnualCost:  roundMoney(last.Amount * p.perYear),
+		}
+		// a charge overdue by more than half a period has probably been cancelled
+		sub.Lapsed = now.Sub(sub.Next).Hours()/24 > p.days/2
+		for _, t := range charges {
+			sub.Transactions = append(sub.Transactions, t.ID)
+		}
+		return sub, true
+	}
+	return models.Subscription{}, false
+}
+
+// normalizeDescription lowercases the description and drops digits and
+// punctuation, so "Netflix 03/2026" and "NETFLIX 04/2026" fall together.
+func normalizeDescription(description string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(description) {
+		switch {
+		case unicode.IsLetter(r):
+			sb.WriteRune(r)
+		case unicode.IsSpace(r):
+			sb.WriteRune(' ')
+		}
+	}
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"fintrack/internal/models"
+	"testing"
+	"time"
+)
+
+// charges builds a series of expenses with the same description on the given dates.
+func charges(description string, amounts []float64, dates ...time.Time) []models.Transaction {
+	var transactions []models.Transaction
+	for i, d := range dates {
+		transactions = append(transactions, models.Transaction{
+			ID:          description + d.Format("0201"),
+			Description: description,
+			Category:    "Подписки",
+			Amount:      amounts[i%len(amounts)],
+			Type:        models.TransactionExpense,
+			Date:        d,
+		})
+	}
+	return transactions
+}
+
+func TestDetectSubscription(t *testing.T) {
+	now := date(2025, time.March, 20)
+
+	tests := []struct {
+		name          string
+		charges       []models.Transaction
+		want          bool
+		wantFrequency models.Frequency
+		wantNext      time.Time
+		wantAnnual    float64
+		wantLapsed    bool
+	}{
+		{
+			name:          "monthly",
+			charges:       charges("Netflix ", []float64{499}, date(2025, time.January, 5), date(2025, time.February, 5), date(2025, time.March, 5)),
+			want:          true,
+			wantFrequency: models.FrequencyMonthly,
+			wantNext:      date(2025, time.April, 5),
+			wantAnnual:    5988,
+		},
+		{
+			name:          "weekly",
+			charges:       charges("Кофе", []float64{150}, date(2025, time.March, 1), date(2025, time.March, 8), date(2025, time.March, 15)),
+			want:          true,
+			wantFrequency: models.FrequencyWeekly,
+			wantNext:      date(2025, time.March, 22),
+			wantAnnual:    7800,
+		},
+		{
+			name:          "yearly needs two charges",
+			charges:       charges("Домен", []float64{1200}, date(2024, time.March, 10), date(2025, time.March, 10)),
+			want:          true,
+			wantFrequency: models.FrequencyYearly,
+			wantNext:      date(2026, time.March, 10),
+			wantAnnual:    1200,
+		},
+		{
+			name:          "amounts within the tolerance",
+			charges:       charges("Музыка", []float64{169, 189, 179}, date(2025, time.January, 5), date(2025, time.February, 5), date(2025, time.March, 5)),
+			want:          true,
+			wantFrequency: models.FrequencyMonthly,
+			wantNext:      date(2025, time.April, 5),
+			wantAnnual:    2148,
+		},
+		{
+			name:          "overdue by more than half a period is lapsed",
+			charges:       charges("Кино", []float64{299}, date(2024, time.November, 1), date(2024, time.December, 1), date(2025, time.January, 1)),
+			want:          true,
+			wantFrequency: models.FrequencyMonthly,
+			wantNext:      date(2025, time.February, 1),
+			wantAnnual:    3588,
+			wantLapsed:    true,
+		},
+		{
+			name:    "two monthly charges are not enough",
+			charges: charges("Netflix", []float64{499}, date(2025, time.February, 5), date(2025, time.March, 5)),
+		},
+		{
+			name:    "amounts differ too much",
+			charges: charges("Такси", []float64{499, 800}, date(2025, time.January, 5), date(2025, time.February, 5), date(2025, time.March, 5)),
+		},
+		{
+			name:    "irregular intervals",
+			charges: charges("Аптека", []float64{499}, date(2025, time.January, 5), date(2025, time.February, 5), date(2025, time.February, 20), date(2025, time.March, 20)),
+		},
+		{
+			name:    "single charge",
+			charges: charges("Netflix", []float64{499}, date(2025, time.March, 5)),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub, ok := detectSubscription(tt.charges, now)
+			if ok != tt.want {
+				t.Fatalf("detected = %v, want %v", ok, tt.want)
+			}
+			if !ok {
+				return
+			}
+			if sub.Frequency != tt.wantFrequency {
+				t.Errorf("frequency = %s, want %s", sub.Frequency, tt.wantFrequency)
+			}
+			if !sub.Next.Equal(tt.wantNext) {
+				t.Errorf("next = %s, want %s", sub.Next.Format("02.01.2006"), tt.wantNext.Format("02.01.2006"))
+			}
+			if sub.AnnualCost != tt.wantAnnual {
+				t.Errorf("annual cost = %.2f, want %.2f", sub.AnnualCost, tt.wantAnnual)
+			}
+			if sub.Lapsed != tt.wantLapsed {
+				t.Errorf("lapsed = %v, want %v", sub.Lapsed, tt.wantLapsed)
+			}
+			if sub.Occurrences != len(tt.charges) || len(sub.Transactions) != len(tt.charges) {
+				t.Errorf("%d occurrences and %d transactions, want %d", sub.Occurrences, len(sub.Transactions), len(tt.charges))
+			}
+		})
+	}
+}
+
+func TestNormalizeDescription(t *testing.T) {
+	tests := []struct {
+		description string
+		want        string
+	}{
+		{"Netflix 03/2026", "netflix"},
+		{"NETFLIX  04/2026", "netflix"},
+		{"  Яндекс.Плюс  ", "яндексплюс"},
+		{"Spotify\tFamily", "spotify family"},
+		{"2026", ""},
+	}
+
+	for _, tt := range tests {
+		if got := normalizeDescription(tt.description); got != tt.want {
+			t.Errorf("normalizeDescription(%q) = %q, want %q", tt.description, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"fintrack/internal/models"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TransactionFilter selects transactions; zero-valued fields match everything.
+// AsOf is not a per-transaction condition: it makes FindTransactions search
+// the ledger as it was recorded at that moment.
+type TransactionFilter struct {
+	Type      models.TransactionType
+	Category  string
+	Query     string
+	From      time.Time
+	To        time.Time
+	MinAmount float64
+	MaxAmount float64
+	AsOf      time.Time
+}
+
+// Match reports whether the transaction satisfies every set condition.
+// To is exclusive.
+func (f TransactionFilter) Match(t models.Transaction) bool {
+	if f.Type != "" && t.Type != f.Type {
+		return false
+	}
+	if f.Category != "" && !strings.EqualFold(t.Category, f.Category) {
+		return false
+	}
+	if f.Query != "" && !strings.Contains(strings.ToLower(t.Description), strings.ToLower(f.Query)) {
+		return false
+	}
+	if !f.From.IsZero() && t.Date.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !t.Date.Before(f.To) {
+		return false
+	}
+	if f.MinAmount > 0 && t.Amount < f.MinAmount {
+		return false
+	}
+	if f.MaxAmount > 0 && t.Amount > f.MaxAmount {
+		return false
+	}
+	return true
+}
+
+// FindTransactions returns transactions matching the filter, newest first.
+func (ts *TransactionService) FindTransactions(filter TransactionFilter) ([]models.Transaction, error) {
+	var (
+		transactions []models.Transaction
+		err          error
+	)
+	if filter.AsOf.IsZero() {
+		transactions, err = ts.GetAllTransactions()
+	} else {
+		transactions, _, err = ts.LedgerAt(filter.AsOf)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := []models.Transaction{}
+	for _, t := range transactions {
+		if filter.Match(t) {
+			result = append(result, t)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Date.After(result[j].Date)
+	})
+	return result, nil
+}
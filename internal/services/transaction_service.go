@@ -1,15 +1,24 @@
 package services
 
 import (
+	"errors"
 	"fintrack/internal/models"
 	"fintrack/internal/storage"
 	"fmt"
+	"log"
 	"strings"
 	"time"
 )
 
+// TransactionObserver is told about every transaction added through the service.
+type TransactionObserver interface {
+	TransactionAdded(transaction models.Transaction) error
+}
+
 type TransactionService struct {
 	storage storage.Storage
+	mutationHooks
+	observers []TransactionObserver
 }
 
 func NewTransactionService(storage storage.Storage) *TransactionService {
@@ -18,6 +27,12 @@ func NewTransactionService(storage storage.Storage) *TransactionService {
 	}
 }
 
+// Observe makes the service call o after each added transaction is saved;
+// errors from o are logged as warnings.
+func (ts *TransactionService) Observe(o TransactionObserver) {
+	ts.observers = append(ts.observers, o)
+}
+
 func generateUniqueID() string {
 	// Использование timestamp в нанасекундах для уникальности
 	return fmt.Sprintf("tx_%d", time.Now().UnixNano())
@@ -25,35 +40,146 @@ func generateUniqueID() string {
 
 func validateTransaction(transaction models.Transaction) error {
 	if transaction.Amount <= 0 {
-		return fmt.Errorf("сумма не может быть <= 0")
+		return validationError("сумма не может быть <= 0")
 	}
 
 	if transaction.Category == "" {
-		return fmt.Errorf("категория не может быть пустой")
+		return validationError("категория не может быть пустой")
 	}
 
 	if transaction.Description == "" {
-		return fmt.Errorf("описание не может быть пустым")
+		return validationError("описание не может быть пустым")
 	}
 
 	return nil
 }
 
 func (ts *TransactionService) AddTransaction(amount float64, category string, description string, transactionType string) error {
+	_, err := ts.AddTransactionAt(amount, category, description, transactionType, time.Now())
+	return err
+}
+
+// AddTransactionAt validates and saves a new transaction made at the given date and returns it.
+func (ts *TransactionService) AddTransactionAt(amount float64, category string, description string, transactionType string, date time.Time) (models.Transaction, error) {
+	if err := ts.checkInput(amount, category, description, transactionType); err != nil {
+		return models.Transaction{}, err
+	}
+
+	newTransaction := models.Transaction{
+		ID:          generateUniqueID(),
+		Amount:      amount,
+		Category:    category,
+		Description: description,
+		Type:        models.TransactionType(transactionType),
+		Date:        date,
+	}
+
+	if err := validateTransaction(newTransaction); err != nil {
+		return models.Transaction{}, err
+	}
+
+	if err := ts.storage.SaveTransaction(newTransaction); err != nil {
+		return models.Transaction{}, err
+	}
+	if err := ts.changed(models.AuditCreate, entityTransaction, newTransaction.ID, nil, newTransaction); err != nil {
+		return newTransaction, err
+	}
+	// observers only look at the saved transaction, so their failure is
+	// not a failure to add it
+	for _, o := range ts.observers {
+		if err := o.TransactionAdded(newTransaction); err != nil {
+			log.Printf("предупреждение: транзакция %s сохранена, но не проверена: %v", newTransaction.ID, err)
+		}
+	}
+	return newTransaction, nil
+}
+
+// UpdateTransaction replaces the editable fields of an existing transaction;
+// the ID and the attachments are kept.
+func (ts *TransactionService) UpdateTransaction(id string, amount float64, category string, description string, transactionType string, date time.Time) (models.Transaction, error) {
+	existing, err := ts.GetTransactionByID(id)
+	if err != nil {
+		return models.Transaction{}, err
+	}
+
+	if err := ts.checkInput(amount, category, description, transactionType); err != nil {
+		return models.Transaction{}, err
+	}
+
+	updated := models.Transaction{
+		ID:          id,
+		Amount:      amount,
+		Category:    category,
+		Description: description,
+		Type:        models.TransactionType(transactionType),
+		Date:        date,
+		Attachments: existing.Attachments,
+	}
+
+	if err := validateTransaction(updated); err != nil {
+		return models.Transaction{}, err
+	}
+
+	if err := ts.storage.UpdateTransaction(updated); err != nil {
+		return models.Transaction{}, err
+	}
+	return updated, ts.changed(models.AuditUpdate, entityTransaction, id, existing, updated)
+}
+
+// setAttachments saves the transaction with a new list of attachments.
+func (ts *TransactionService) setAttachments(existing models.Transaction, attachments []models.Attachment) error {
+	updated := existing
+	updated.Attachments = attachments
+	if err := ts.storage.UpdateTransaction(updated); err != nil {
+		return err
+	}
+	return ts.changed(models.AuditUpdate, entityTransaction, existing.ID, existing, updated)
+}
+
+func (ts *TransactionService) DeleteTransaction(id string) error {
+	existing, err := ts.GetTransactionByID(id)
+	if err != nil {
+		return err
+	}
+	if err := ts.destructive("удаление транзакции " + id); err != nil {
+		return err
+	}
+	if err := ts.storage.DeleteTransaction(id); err != nil {
+		return err
+	}
+	return ts.changed(models.AuditDelete, entityTransaction, id, existing, nil)
+}
+
+func (ts *TransactionService) GetTransactionByID(id string) (models.Transaction, error) {
+	transactions, err := ts.GetAllTransactions()
+	if err != nil {
+		return models.Transaction{}, err
+	}
+
+	for _, t := range transactions {
+		if t.ID == id {
+			return t, nil
+		}
+	}
+	return models.Transaction{}, storage.ErrTransactionNotFound
+}
+
+// checkInput validates user-provided fields and that the category exists and matches the type.
+func (ts *TransactionService) checkInput(amount float64, category string, description string, transactionType string) error {
 	if amount <= 0 {
-		return fmt.Errorf("сумма не может быть <= 0")
+		return validationError("сумма не может быть <= 0")
 	}
 
 	if strings.TrimSpace(category) == "" {
-		return fmt.Errorf("категория не может быть пустой")
+		return validationError("категория не может быть пустой")
 	}
 
 	if strings.TrimSpace(description) == "" {
-		return fmt.Errorf("описание не может быть пустым")
+		return validationError("описание не может быть пустым")
 	}
 
 	if transactionType != string(models.TransactionExpense) && transactionType != string(models.TransactionIncome) {
-		return fmt.Errorf("неизвестный тип транзакции: %s", transactionType)
+		return validationError("неизвестный тип транзакции: %s", transactionType)
 	}
 
 	categories, err := ts.storage.GetCategories()
@@ -61,35 +187,28 @@ func (ts *TransactionService) AddTransaction(amount float64, category string, de
 		return fmt.Errorf("ошибка получения категорий: %w", err)
 	}
 
-	categoryFound := false
 	for _, cat := range categories {
 		if strings.EqualFold(cat.Name, category) {
-			categoryFound = true
 			if string(cat.Type) != transactionType {
-				return fmt.Errorf("несоответствие типа категории")
+				return validationError("несоответствие типа категории")
 			}
-			break
+			return nil
 		}
 	}
 
-	if !categoryFound {
-		return fmt.Errorf("категория не найдена")
-	}
+	return validationError("категория не найдена")
+}
 
-	newTransaction := models.Transaction{
-		ID:          generateUniqueID(),
-		Amount:      amount,
-		Category:    category,
-		Description: description,
-		Type:        models.TransactionType(transactionType),
-		Date:        time.Now(),
-	}
+// ErrNoPointInTime is returned for point-in-time queries on a storage that keeps only the current state.
+var ErrNoPointInTime = errors.New("хранилище не хранит историю изменений, выберите storage_backend = events")
 
-	if err := validateTransaction(newTransaction); err != nil {
-		return err
+// LedgerAt returns the transactions and categories as they were recorded at t.
+func (ts *TransactionService) LedgerAt(t time.Time) ([]models.Transaction, []models.Category, error) {
+	pit, ok := ts.storage.(storage.PointInTime)
+	if !ok {
+		return nil, nil, ErrNoPointInTime
 	}
-
-	return ts.storage.SaveTransaction(newTransaction)
+	return pit.StateAt(t)
 }
 
 func (ts *TransactionService) GetAllTransactions() ([]models.Transaction, error) {
@@ -0,0 +1,8 @@
+package storage
+
+import "fintrack/internal/models"
+
+// NewAnomalyFile keeps flagged transactions in a JSON file.
+func NewAnomalyFile(path string, codec Codec) *JSONFile[[]models.Anomaly] {
+	return NewJSONFile(path, codec, "файла аномалий", []models.Anomaly{})
+}
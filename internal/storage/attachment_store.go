@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// AttachmentsDirName is the directory of a profile holding attached files.
+const AttachmentsDirName = "attachments"
+
+// AttachmentStore keeps attached files addressed by the SHA-256 of their
+// content, so a file attached twice is stored once. Files are written
+// through the codec like the rest of the profile data.
+type AttachmentStore struct {
+	dir   string
+	codec Codec
+}
+
+func NewAttachmentStore(dir string, codec Codec) *AttachmentStore {
+	return &AttachmentStore{dir: dir, codec: codec}
+}
+
+// Put stores the content unless it is already there and returns its hash.
+func (as *AttachmentStore) Put(content []byte) (string, error) {
+	sum := sha256.Sum256(content)
+	hash := hex.EncodeToString(sum[:])
+
+	path := as.path(hash)
+	if _, err := os.Stat(path); err == nil {
+		return hash, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return "", err
+	}
+	return hash, WriteFile(as.codec, path, content)
+}
+
+// Get returns the content stored under hash.
+func (as *AttachmentStore) Get(hash string) ([]byte, error) {
+	content, err := ReadFile(as.codec, as.path(hash))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("файл вложения %s не найден", hash)
+	}
+	return content, err
+}
+
+// Remove deletes the content stored under hash.
+func (as *AttachmentStore) Remove(hash string) error {
+	err := os.Remove(as.path(hash))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// Hashes lists the hashes of all stored files.
+func (as *AttachmentStore) Hashes() ([]string, error) {
+	var hashes []string
+	err := filepath.WalkDir(as.dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() && filepath.Ext(d.Name()) != ".tmp" {
+			hashes = append(hashes, d.Name())
+		}
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	sort.Strings(hashes)
+	return hashes, err
+}
+
+// Files lists the paths of all stored files, for re-encryption.
+func (as *AttachmentStore) Files() ([]string, error) {
+	hashes, err := as.Hashes()
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]string, len(hashes))
+	for i, hash := range hashes {
+		paths[i] = as.path(hash)
+	}
+	return paths, nil
+}
+
+// path spreads files over subdirectories named by the first two hex digits.
+func (as *AttachmentStore) path(hash string) string {
+	if len(hash) < 2 {
+		return filepath.Join(as.dir, hash)
+	}
+	return filepath.Join(as.dir, hash[:2], hash)
+}
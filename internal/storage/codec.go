@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Codec transforms file contents on their way to and from disk. File-backed
+// storages read and write through a codec, which lets an encryption layer wrap
+// them without knowing their format.
+type Codec interface {
+	Encode(plain []byte) ([]byte, error)
+	Decode(data []byte) ([]byte, error)
+}
+
+// PlainCodec stores data as is.
+type PlainCodec struct{}
+
+func (PlainCodec) Encode(plain []byte) ([]byte, error) { return plain, nil }
+func (PlainCodec) Decode(data []byte) ([]byte, error)  { return data, nil }
+
+// ReadFile reads and decodes a file.
+func ReadFile(codec Codec, path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return codec.Decode(data)
+}
+
+// WriteFile encodes data and replaces the file atomically, readable only by the owner.
+func WriteFile(codec Codec, path string, plain []byte) error {
+	data, err := codec.Encode(plain)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,8 @@
+package storage
+
+import "fintrack/internal/models"
+
+// NewDebtFile keeps debts and loans in a JSON file.
+func NewDebtFile(path string, codec Codec) *JSONFile[[]models.Debt] {
+	return NewJSONFile(path, codec, "файла долгов", []models.Debt{})
+}
@@ -0,0 +1,8 @@
+package storage
+
+import "fintrack/internal/models"
+
+// NewEnvelopeFile keeps envelope allocations in a JSON file.
+func NewEnvelopeFile(path string, codec Codec) *JSONFile[[]models.EnvelopeAllocation] {
+	return NewJSONFile(path, codec, "файла конвертов", []models.EnvelopeAllocation{})
+}
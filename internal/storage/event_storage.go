@@ -0,0 +1,281 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fintrack/internal/models"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const EventsFileName = "events.jsonl"
+
+// EventStorage keeps the ledger as an append-only stream of events in JSON
+// lines. Transactions and categories are projections rebuilt by replaying the
+// stream, which also makes it possible to see the ledger at any past moment.
+type EventStorage struct {
+	path  string
+	codec Codec
+	mu    sync.RWMutex
+}
+
+var _ Storage = (*EventStorage)(nil)
+
+// NewEventStorage opens the event stream at path. A new stream is seeded
+// from the current state of seed, so switching an existing ledger to events
+// keeps its data. Seed transactions are dated with their own dates, so past
+// states of the migrated ledger can be queried too; transactions dated in
+// the future are recorded at the moment of migration to keep the stream in
+// time order. The stream does not write back to seed.
+func NewEventStorage(path string, codec Codec, seed Storage) (*EventStorage, error) {
+	es := &EventStorage{path: path, codec: codec}
+
+	if _, err := os.Stat(path); err == nil || seed == nil {
+		return es, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+
+	categories, err := seed.GetCategories()
+	if err != nil {
+		return nil, fmt.Errorf("ошибка переноса категорий в журнал событий: %w", err)
+	}
+	transactions, err := seed.GetAllTransactions()
+	if err != nil {
+		return nil, fmt.Errorf("ошибка переноса транзакций в журнал событий: %w", err)
+	}
+	sort.SliceStable(transactions, func(i, j int) bool {
+		return transactions[i].Date.Before(transactions[j].Date)
+	})
+
+	now := time.Now()
+	dated := func(t time.Time) time.Time {
+		if t.IsZero() || t.After(now) {
+			return now
+		}
+		return t
+	}
+	first := now
+	if len(transactions) > 0 {
+		first = dated(transactions[0].Date)
+	}
+
+	var events []models.Event
+	for i := range categories {
+		events = append(events, models.Event{Time: first, Type: models.EventCategoryAdded, Category: &categories[i]})
+	}
+	for i := range transactions {
+		events = append(events, models.Event{Time: dated(transactions[i].Date), Type: models.EventTransactionAdded, Transaction: &transactions[i]})
+	}
+
+	// an empty stream is still written so the migration happens only once
+	if err := es.append(nil, events...); err != nil {
+		return nil, err
+	}
+	return es, nil
+}
+
+func (es *EventStorage) SaveTransaction(transaction models.Transaction) error {
+	return es.append(nil, models.Event{Type: models.EventTransactionAdded, Transaction: &transaction})
+}
+
+func (es *EventStorage) GetAllTransactions() ([]models.Transaction, error) {
+	transactions, _, err := es.StateAt(time.Time{})
+	return transactions, err
+}
+
+func (es *EventStorage) UpdateTransaction(transaction models.Transaction) error {
+	return es.append(hasTransaction(transaction.ID), models.Event{Type: models.EventTransactionAmended, Transaction: &transaction})
+}
+
+func (es *EventStorage) DeleteTransaction(id string) error {
+	return es.append(hasTransaction(id), models.Event{Type: models.EventTransactionRemoved, ID: id})
+}
+
+func (es *EventStorage) GetCategories() ([]models.Category, error) {
+	_, categories, err := es.StateAt(time.Time{})
+	return categories, err
+}
+
+func (es *EventStorage) SaveCategory(category models.Category) error {
+	return es.append(nil, models.Event{Type: models.EventCategoryAdded, Category: &category})
+}
+
+func (es *EventStorage) DeleteCategory(name string) error {
+	return es.append(hasCategory(name), models.Event{Type: models.EventCategoryRemoved, OldName: name})
+}
+
+func (es *EventStorage) RenameCategory(oldName, newName string) error {
+	return es.append(hasCategory(oldName), models.Event{Type: models.EventCategoryRenamed, OldName: oldName, NewName: newName})
+}
+
+// Events returns the whole stream from the first event to the last.
+func (es *EventStorage) Events() ([]models.Event, error) {
+	es.mu.RLock()
+	defer es.mu.RUnlock()
+
+	events, _, err := es.read()
+	return events, err
+}
+
+// StateAt replays the events recorded up to and including t; a zero t
+// replays the whole stream and gives the current state.
+func (es *EventStorage) StateAt(t time.Time) ([]models.Transaction, []models.Category, error) {
+	events, err := es.Events()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !t.IsZero() {
+		n := 0
+		for n < len(events) && !events[n].Time.After(t) {
+			n++
+		}
+		events = events[:n]
+	}
+
+	transactions, categories := Project(events)
+	return transactions, categories, nil
+}
+
+// Project applies events in order and returns the resulting transactions and categories.
+func Project(events []models.Event) ([]models.Transaction, []models.Category) {
+	transactions := []models.Transaction{}
+	categories := []models.Category{}
+
+	for _, e := range events {
+		switch e.Type {
+		case models.EventTransactionAdded:
+			if e.Transaction != nil {
+				transactions = append(transactions, *e.Transaction)
+			}
+		case models.EventTransactionAmended:
+			if e.Transaction == nil {
+				continue
+			}
+			for i := range transactions {
+				if transactions[i].ID == e.Transaction.ID {
+					transactions[i] = *e.Transaction
+					break
+				}
+			}
+		case models.EventTransactionRemoved:
+			for i := range transactions {
+				if transactions[i].ID == e.ID {
+					transactions = append(transactions[:i], transactions[i+1:]...)
+					break
+				}
+			}
+		case models.EventCategoryAdded:
+			if e.Category != nil {
+				categories = append(categories, *e.Category)
+			}
+		case models.EventCategoryRemoved:
+			for i := range categories {
+				if categories[i].Name == e.OldName {
+					categories = append(categories[:i], categories[i+1:]...)
+					break
+				}
+			}
+		case models.EventCategoryRenamed:
+			renameCategory(categories, transactions, e.OldName, e.NewName)
+		}
+	}
+	return transactions, categories
+}
+
+// precondition checks the current state before new events are appended.
+type precondition func(transactions []models.Transaction, categories []models.Category) error
+
+func hasTransaction(id string) precondition {
+	return func(transactions []models.Transaction, _ []models.Category) error {
+		for _, t := range transactions {
+			if t.ID == id {
+				return nil
+			}
+		}
+		return ErrTransactionNotFound
+	}
+}
+
+func hasCategory(name string) precondition {
+	return func(_ []models.Transaction, categories []models.Category) error {
+		for _, c := range categories {
+			if c.Name == name {
+				return nil
+			}
+		}
+		return ErrCategoryNotFound
+	}
+}
+
+// append numbers, timestamps and writes new events after the existing ones,
+// provided check passes against the current projection. Events that already
+// have a time keep it.
+func (es *EventStorage) append(check precondition, events ...models.Event) error {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	existing, data, err := es.read()
+	if err != nil {
+		return err
+	}
+	if check != nil {
+		if err := check(Project(existing)); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	for i := range events {
+		events[i].Seq = len(existing) + i + 1
+		if events[i].Time.IsZero() {
+			events[i].Time = now
+		}
+
+		line, err := json.Marshal(events[i])
+		if err != nil {
+			return err
+		}
+		data = append(data, line...)
+		data = append(data, '\n')
+	}
+	return WriteFile(es.codec, es.path, data)
+}
+
+// read returns the parsed events and the raw decoded stream. A damaged
+// stream is an error: replaying it partially would silently lose data.
+func (es *EventStorage) read() ([]models.Event, []byte, error) {
+	data, err := ReadFile(es.codec, es.path)
+	if os.IsNotExist(err) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var events []models.Event
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var e models.Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, nil, fmt.Errorf("журнал событий поврежден в строке %d: %w", line, err)
+		}
+		events = append(events, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	return events, data, nil
+}
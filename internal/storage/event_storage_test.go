@@ -0,0 +1,208 @@
+package storage
+
+import (
+	"errors"
+	"fintrack/internal/models"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.Local)
+}
+
+func tx(id, category string, amount float64, date time.Time) *models.Transaction {
+	return &models.Transaction{ID: id, Category: category, Amount: amount, Type: models.TransactionExpense, Date: date}
+}
+
+// ledger summarizes a projection as "id:category:amount" and category names.
+func ledger(transactions []models.Transaction, categories []models.Category) ([]string, []string) {
+	txs := []string{}
+	for _, t := range transactions {
+		txs = append(txs, t.ID+":"+t.Category+":"+formatAmount(t.Amount))
+	}
+	names := []string{}
+	for _, c := range categories {
+		names = append(names, c.Name)
+	}
+	return txs, names
+}
+
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', -1, 64)
+}
+
+func TestProject(t *testing.T) {
+	date := day(2025, time.March, 1)
+	food := &models.Category{ID: "1", Name: "Еда"}
+	taxi := &models.Category{ID: "2", Name: "Такси"}
+
+	tests := []struct {
+		name           string
+		events         []models.Event
+		wantTx         []string
+		wantCategories []string
+	}{
+		{
+			name:           "empty stream",
+			wantTx:         []string{},
+			wantCategories: []string{},
+		},
+		{
+			name: "added transactions keep their order",
+			events: []models.Event{
+				{Type: models.EventTransactionAdded, Transaction: tx("a", "Еда", 100, date)},
+				{Type: models.EventTransactionAdded, Transaction: tx("b", "Такси", 200, date)},
+			},
+			wantTx:         []string{"a:Еда:100", "b:Такси:200"},
+			wantCategories: []string{},
+		},
+		{
+			name: "amended transaction is replaced",
+			events: []models.Event{
+				{Type: models.EventTransactionAdded, Transaction: tx("a", "Еда", 100, date)},
+				{Type: models.EventTransactionAmended, Transaction: tx("a", "Такси", 150, date)},
+			},
+			wantTx:         []string{"a:Такси:150"},
+			wantCategories: []string{},
+		},
+		{
+			name: "amending or removing an unknown transaction changes nothing",
+			events: []models.Event{
+				{Type: models.EventTransactionAdded, Transaction: tx("a", "Еда", 100, date)},
+				{Type: models.EventTransactionAmended, Transaction: tx("x", "Такси", 150, date)},
+				{Type: models.EventTransactionRemoved, ID: "y"},
+			},
+			wantTx:         []string{"a:Еда:100"},
+			wantCategories: []string{},
+		},
+		{
+			name: "removed transaction disappears",
+			events: []models.Event{
+				{Type: models.EventTransactionAdded, Transaction: tx("a", "Еда", 100, date)},
+				{Type: models.EventTransactionAdded, Transaction: tx("b", "Еда", 200, date)},
+				{Type: models.EventTransactionRemoved, ID: "a"},
+			},
+			wantTx:         []string{"b:Еда:200"},
+			wantCategories: []string{},
+		},
+		{
+			name: "renamed category moves its transactions",
+			events: []models.Event{
+				{Type: models.EventCategoryAdded, Category: food},
+				{Type: models.EventCategoryAdded, Category: taxi},
+				{Type: models.EventTransactionAdded, Transaction: tx("a", "еда", 100, date)},
+				{Type: models.EventTransactionAdded, Transaction: tx("b", "Такси", 200, date)},
+				{Type: models.EventCategoryRenamed, OldName: "Еда", NewName: "Продукты"},
+			},
+			wantTx:         []string{"a:Продукты:100", "b:Такси:200"},
+			wantCategories: []string{"Продукты", "Такси"},
+		},
+		{
+			name: "renaming an unknown category changes nothing",
+			events: []models.Event{
+				{Type: models.EventCategoryAdded, Category: food},
+				{Type: models.EventTransactionAdded, Transaction: tx("a", "Кафе", 100, date)},
+				{Type: models.EventCategoryRenamed, OldName: "Кафе", NewName: "Рестораны"},
+			},
+			wantTx:         []string{"a:Кафе:100"},
+			wantCategories: []string{"Еда"},
+		},
+		{
+			name: "removed category",
+			events: []models.Event{
+				{Type: models.EventCategoryAdded, Category: food},
+				{Type: models.EventCategoryAdded, Category: taxi},
+				{Type: models.EventCategoryRemoved, OldName: "Еда"},
+			},
+			wantTx:         []string{},
+			wantCategories: []string{"Такси"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotTx, gotCategories := ledger(Project(tt.events))
+			if !reflect.DeepEqual(gotTx, tt.wantTx) {
+				t.Errorf("transactions = %v, want %v", gotTx, tt.wantTx)
+			}
+			if !reflect.DeepEqual(gotCategories, tt.wantCategories) {
+				t.Errorf("categories = %v, want %v", gotCategories, tt.wantCategories)
+			}
+		})
+	}
+}
+
+func TestStateAt(t *testing.T) {
+	dir := t.TempDir()
+	seed := NewFileStorage(filepath.Join(dir, "transactions.json"), filepath.Join(dir, "categories.json"))
+	for _, tr := range []*models.Transaction{
+		tx("b", "Транспорт", 200, day(2025, time.March, 10)),
+		tx("a", "Продукты", 100, day(2025, time.March, 1)),
+		tx("future", "Продукты", 300, day(2099, time.January, 1)),
+	} {
+		if err := seed.SaveTransaction(*tr); err != nil {
+			t.Fatal(err)
+		}
+	}
+	categories, err := seed.GetCategories()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	es, err := NewEventStorage(filepath.Join(dir, EventsFileName), PlainCodec{}, seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := es.DeleteTransaction("a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := es.DeleteTransaction("a"); !errors.Is(err, ErrTransactionNotFound) {
+		t.Errorf("deleting a removed transaction: %v, want %v", err, ErrTransactionNotFound)
+	}
+
+	endOf := func(t time.Time) time.Time { return t.Add(24*time.Hour - time.Nanosecond) }
+	tests := []struct {
+		name           string
+		at             time.Time
+		wantTx         []string
+		wantCategories int
+	}{
+		{"before the first transaction", endOf(day(2025, time.February, 28)), []string{}, 0},
+		{"day of the first transaction", endOf(day(2025, time.March, 1)), []string{"a:Продукты:100"}, len(categories)},
+		{"after both seeded transactions", endOf(day(2025, time.March, 31)), []string{"a:Продукты:100", "b:Транспорт:200"}, len(categories)},
+		{"current state", time.Time{}, []string{"b:Транспорт:200", "future:Продукты:300"}, len(categories)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transactions, categories, err := es.StateAt(tt.at)
+			if err != nil {
+				t.Fatal(err)
+			}
+			gotTx, _ := ledger(transactions, nil)
+			if !reflect.DeepEqual(gotTx, tt.wantTx) {
+				t.Errorf("transactions = %v, want %v", gotTx, tt.wantTx)
+			}
+			if len(categories) != tt.wantCategories {
+				t.Errorf("%d categories, want %d", len(categories), tt.wantCategories)
+			}
+		})
+	}
+
+	events, err := es.Events()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i < len(events); i++ {
+		if events[i].Time.Before(events[i-1].Time) {
+			t.Errorf("event %d at %s is before event %d at %s", events[i].Seq, events[i].Time, events[i-1].Seq, events[i-1].Time)
+		}
+		if events[i].Seq != i+1 {
+			t.Errorf("event %d has seq %d", i+1, events[i].Seq)
+		}
+	}
+}
@@ -2,19 +2,33 @@ package storage
 
 import (
 	"encoding/json"
+	"errors"
 	"fintrack/internal/models"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 )
 
+// ErrTransactionNotFound is returned when a transaction with the given ID does not exist.
+var ErrTransactionNotFound = errors.New("транзакция не найдена")
+
+// ErrCategoryNotFound is returned when a category with the given name does not exist.
+var ErrCategoryNotFound = errors.New("категория не найдена")
+
 type FileStorage struct {
 	transactionFile string
 	categoryFile    string
+	codec           Codec
 	mu              sync.RWMutex
 }
 
 func NewFileStorage(transactionFile, categoryFile string) *FileStorage {
+	return NewFileStorageWithCodec(transactionFile, categoryFile, PlainCodec{})
+}
+
+// NewFileStorageWithCodec creates a file storage whose files pass through codec, e.g. an encryption layer.
+func NewFileStorageWithCodec(transactionFile, categoryFile string, codec Codec) *FileStorage {
 	// ensure directory for transactionFile exists
 	if dir := filepath.Dir(transactionFile); dir != "" {
 		_ = os.MkdirAll(dir, 0755)
@@ -26,7 +40,7 @@ func NewFileStorage(transactionFile, categoryFile string) *FileStorage {
 	// create transaction file if missing
 	if _, err := os.Stat(transactionFile); err != nil {
 		// write empty array
-		_ = os.WriteFile(transactionFile, []byte("[]\n"), 0644)
+		_ = WriteFile(codec, transactionFile, []byte("[]\n"))
 	}
 
 	// create category file if missing — write default categories
@@ -35,15 +49,16 @@ func NewFileStorage(transactionFile, categoryFile string) *FileStorage {
 		all = append(all, models.DefaultExpenseCategories...)
 		all = append(all, models.DefaultIncomeCategories...)
 		if data, err := json.MarshalIndent(all, "", "  "); err == nil {
-			_ = os.WriteFile(categoryFile, append(data, '\n'), 0644)
+			_ = WriteFile(codec, categoryFile, append(data, '\n'))
 		} else {
-			_ = os.WriteFile(categoryFile, []byte("[]\n"), 0644)
+			_ = WriteFile(codec, categoryFile, []byte("[]\n"))
 		}
 	}
 
 	return &FileStorage{
 		transactionFile: transactionFile,
 		categoryFile:    categoryFile,
+		codec:           codec,
 	}
 }
 
@@ -51,7 +66,10 @@ func (fs *FileStorage) SaveTransaction(transaction models.Transaction) error {
 	fs.mu.Lock()
 	defer fs.mu.Unlock()
 
-	transactions, _ := fs.readTransactions()
+	transactions, err := fs.readTransactions()
+	if err != nil {
+		return err
+	}
 	transactions = append(transactions, transaction)
 	return fs.writeTransactions(transactions)
 }
@@ -62,6 +80,40 @@ func (fs *FileStorage) GetAllTransactions() ([]models.Transaction, error) {
 	return fs.readTransactions()
 }
 
+func (fs *FileStorage) UpdateTransaction(transaction models.Transaction) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	transactions, err := fs.readTransactions()
+	if err != nil {
+		return err
+	}
+	for i := range transactions {
+		if transactions[i].ID == transaction.ID {
+			transactions[i] = transaction
+			return fs.writeTransactions(transactions)
+		}
+	}
+	return ErrTransactionNotFound
+}
+
+func (fs *FileStorage) DeleteTransaction(id string) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	transactions, err := fs.readTransactions()
+	if err != nil {
+		return err
+	}
+	for i := range transactions {
+		if transactions[i].ID == id {
+			transactions = append(transactions[:i], transactions[i+1:]...)
+			return fs.writeTransactions(transactions)
+		}
+	}
+	return ErrTransactionNotFound
+}
+
 func (fs *FileStorage) GetCategories() ([]models.Category, error) {
 	fs.mu.RLock()
 	defer fs.mu.RUnlock()
@@ -72,16 +124,94 @@ func (fs *FileStorage) SaveCategory(category models.Category) error {
 	fs.mu.Lock()
 	defer fs.mu.Unlock()
 
-	categories, _ := fs.readCategories()
+	categories, err := fs.readCategories()
+	if err != nil {
+		return err
+	}
 	categories = append(categories, category)
 	return fs.writeCategories(categories)
 }
 
-func (fs *FileStorage) readTransactions() ([]models.Transaction, error) {
-	data, err := os.ReadFile(fs.transactionFile)
+func (fs *FileStorage) DeleteCategory(name string) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	categories, err := fs.readCategories()
+	if err != nil {
+		return err
+	}
+	for i := range categories {
+		if categories[i].Name == name {
+			categories = append(categories[:i], categories[i+1:]...)
+			return fs.writeCategories(categories)
+		}
+	}
+	return ErrCategoryNotFound
+}
+
+func (fs *FileStorage) RenameCategory(oldName, newName string) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	categories, err := fs.readCategories()
+	if err != nil {
+		return err
+	}
+	transactions, err := fs.readTransactions()
 	if err != nil {
+		return err
+	}
+
+	original := append([]models.Category(nil), categories...)
+	if !renameCategory(categories, transactions, oldName, newName) {
+		return ErrCategoryNotFound
+	}
+
+	// categories go first and are put back if the transactions cannot be
+	// written, so no transaction is left under a category that does not exist
+	if err := fs.writeCategories(categories); err != nil {
+		return err
+	}
+	if err := fs.writeTransactions(transactions); err != nil {
+		if restoreErr := fs.writeCategories(original); restoreErr != nil {
+			return errors.Join(err, restoreErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// renameCategory renames the category in place and moves its transactions,
+// which refer to categories by case-insensitive name.
+func renameCategory(categories []models.Category, transactions []models.Transaction, oldName, newName string) bool {
+	found := false
+	for i := range categories {
+		if categories[i].Name == oldName {
+			categories[i].Name = newName
+			found = true
+		}
+	}
+	if !found {
+		return false
+	}
+	for i := range transactions {
+		if strings.EqualFold(transactions[i].Category, oldName) {
+			transactions[i].Category = newName
+		}
+	}
+	return true
+}
+
+// readTransactions treats a missing or malformed file as empty, but reports
+// codec failures so that undecryptable data is never overwritten.
+func (fs *FileStorage) readTransactions() ([]models.Transaction, error) {
+	data, err := ReadFile(fs.codec, fs.transactionFile)
+	if os.IsNotExist(err) {
 		return []models.Transaction{}, nil
 	}
+	if err != nil {
+		return nil, err
+	}
 
 	var transactions []models.Transaction
 	if err := json.Unmarshal(data, &transactions); err != nil {
@@ -95,14 +225,17 @@ func (fs *FileStorage) writeTransactions(transactions []models.Transaction) erro
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(fs.transactionFile, data, 0644)
+	return WriteFile(fs.codec, fs.transactionFile, data)
 }
 
 func (fs *FileStorage) readCategories() ([]models.Category, error) {
-	data, err := os.ReadFile(fs.categoryFile)
-	if err != nil {
+	data, err := ReadFile(fs.codec, fs.categoryFile)
+	if os.IsNotExist(err) {
 		return []models.Category{}, nil
 	}
+	if err != nil {
+		return nil, err
+	}
 
 	var categories []models.Category
 	if err := json.Unmarshal(data, &categories); err != nil {
@@ -116,5 +249,5 @@ func (fs *FileStorage) writeCategories(categories []models.Category) error {
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(fs.categoryFile, data, 0644)
+	return WriteFile(fs.codec, fs.categoryFile, data)
 }
@@ -0,0 +1,8 @@
+package storage
+
+import "fintrack/internal/models"
+
+// NewGoalFile keeps savings goals in a JSON file.
+func NewGoalFile(path string, codec Codec) *JSONFile[[]models.Goal] {
+	return NewJSONFile(path, codec, "файла целей", []models.Goal{})
+}
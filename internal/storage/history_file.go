@@ -0,0 +1,8 @@
+package storage
+
+import "fintrack/internal/models"
+
+// NewHistoryFile persists the undo/redo stacks in a JSON file.
+func NewHistoryFile(path string, codec Codec) *JSONFile[models.History] {
+	return NewJSONFile(path, codec, "истории действий", models.History{})
+}
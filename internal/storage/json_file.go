@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// JSONFile keeps a value of type T in a JSON file written through the codec.
+// A missing file loads as the empty value given to NewJSONFile.
+type JSONFile[T any] struct {
+	path  string
+	codec Codec
+	what  string
+	empty T
+	mu    sync.Mutex
+}
+
+// NewJSONFile creates a store for the file at path; what names its contents
+// in parse errors, e.g. "файла целей".
+func NewJSONFile[T any](path string, codec Codec, what string, empty T) *JSONFile[T] {
+	return &JSONFile[T]{path: path, codec: codec, what: what, empty: empty}
+}
+
+func (jf *JSONFile[T]) Load() (T, error) {
+	jf.mu.Lock()
+	defer jf.mu.Unlock()
+
+	data, err := ReadFile(jf.codec, jf.path)
+	if os.IsNotExist(err) {
+		return jf.empty, nil
+	}
+	if err != nil {
+		return jf.empty, err
+	}
+
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return jf.empty, fmt.Errorf("ошибка разбора %s: %w", jf.what, err)
+	}
+	return value, nil
+}
+
+func (jf *JSONFile[T]) Save(value T) error {
+	jf.mu.Lock()
+	defer jf.mu.Unlock()
+
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	return WriteFile(jf.codec, jf.path, data)
+}
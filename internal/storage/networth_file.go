@@ -0,0 +1,8 @@
+package storage
+
+import "fintrack/internal/models"
+
+// NewNetWorthFile keeps manually valued assets and monthly net worth snapshots.
+func NewNetWorthFile(path string, codec Codec) *JSONFile[models.NetWorthData] {
+	return NewJSONFile(path, codec, "файла капитала", models.NetWorthData{Assets: []models.Asset{}, Snapshots: []models.NetWorthSnapshot{}})
+}
@@ -0,0 +1,8 @@
+package storage
+
+import "fintrack/internal/models"
+
+// NewPayeeFile keeps payees in a JSON file.
+func NewPayeeFile(path string, codec Codec) *JSONFile[[]models.Payee] {
+	return NewJSONFile(path, codec, "файла получателей", []models.Payee{})
+}
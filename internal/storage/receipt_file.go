@@ -0,0 +1,8 @@
+package storage
+
+import "fintrack/internal/models"
+
+// NewReceiptFile keeps fiscal receipts in a JSON file.
+func NewReceiptFile(path string, codec Codec) *JSONFile[[]models.Receipt] {
+	return NewJSONFile(path, codec, "файла чеков", []models.Receipt{})
+}
@@ -0,0 +1,8 @@
+package storage
+
+import "fintrack/internal/models"
+
+// NewRecurringFile keeps recurring income and expense templates in a JSON file.
+func NewRecurringFile(path string, codec Codec) *JSONFile[[]models.RecurringItem] {
+	return NewJSONFile(path, codec, "файла регулярных платежей", []models.RecurringItem{})
+}
@@ -1,10 +1,24 @@
 package storage
 
-import "fintrack/internal/models"
+import (
+	"fintrack/internal/models"
+	"time"
+)
 
 type Storage interface {
 	SaveTransaction(transaction models.Transaction) error
 	GetAllTransactions() ([]models.Transaction, error)
+	UpdateTransaction(transaction models.Transaction) error
+	DeleteTransaction(id string) error
 	GetCategories() ([]models.Category, error)
 	SaveCategory(category models.Category) error
+	DeleteCategory(name string) error
+	// RenameCategory renames a category and every transaction filed under it.
+	RenameCategory(oldName, newName string) error
+}
+
+// PointInTime is implemented by storages that keep the full history of
+// changes and can reconstruct the ledger as of a past moment.
+type PointInTime interface {
+	StateAt(t time.Time) ([]models.Transaction, []models.Category, error)
 }
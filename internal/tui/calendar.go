@@ -0,0 +1,89 @@
+package tui
+
+import (
+	"fintrack/internal/charts"
+	"fintrack/internal/models"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// calendar shows the monthly spending heatmap with a movable day cursor.
+type calendar struct {
+	selected time.Time
+}
+
+func newCalendar() calendar {
+	now := time.Now()
+	return calendar{selected: time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)}
+}
+
+func (m *model) updateCalendar(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	c := &m.calendar
+	switch keyMsg.String() {
+	case "esc", "q", "c":
+		m.view = viewTable
+	case "left", "h":
+		c.selected = c.selected.AddDate(0, 0, -1)
+	case "right", "l":
+		c.selected = c.selected.AddDate(0, 0, 1)
+	case "up", "k":
+		c.selected = c.selected.AddDate(0, 0, -7)
+	case "down", "j":
+		c.selected = c.selected.AddDate(0, 0, 7)
+	case "[", "pgup":
+		c.selected = c.selected.AddDate(0, -1, 0)
+	case "]", "pgdown":
+		c.selected = c.selected.AddDate(0, 1, 0)
+	}
+	return m, nil
+}
+
+func (m *model) calendarView() string {
+	day := m.calendar.selected
+	from := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.Local)
+	to := from.AddDate(0, 1, -1)
+
+	totals, err := m.reportService.DailyTotals(from, to)
+	if err != nil {
+		return errorStyle.Render(fmt.Sprintf("Ошибка построения календаря: %v", err))
+	}
+
+	days := make([]charts.HeatmapDay, len(totals))
+	for i, t := range totals {
+		days[i] = charts.HeatmapDay{Date: t.Date, Value: t.Expense}
+	}
+
+	opts := charts.DetectOptions()
+	opts.Width = m.width
+
+	var sb strings.Builder
+	sb.WriteString(charts.MonthHeatmap(days, day, opts))
+	sb.WriteString("\n" + selectedStyle.Render(fmt.Sprintf("Транзакции за %s:", day.Format("02.01.2006"))) + "\n")
+
+	transactions, err := m.reportService.TransactionsOnDay(day)
+	if err != nil {
+		sb.WriteString(errorStyle.Render(err.Error()))
+		return sb.String()
+	}
+
+	if len(transactions) == 0 {
+		sb.WriteString(hintStyle.Render("Нет транзакций за этот день."))
+	}
+	for _, t := range transactions {
+		sign := "+"
+		if t.Type == models.TransactionExpense {
+			sign = "-"
+		}
+		sb.WriteString(fmt.Sprintf("%s %s%10.2f  %-15s %s\n", t.Date.Local().Format("15:04"), sign, t.Amount, t.Category, t.Description))
+	}
+
+	return sb.String()
+}
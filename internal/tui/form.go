@@ -0,0 +1,289 @@
+package tui
+
+import (
+	"fintrack/internal/models"
+	"fintrack/internal/services"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type formField int
+
+const (
+	fieldAmount formField = iota
+	fieldType
+	fieldCategory
+	fieldDate
+	fieldDescription
+	fieldCount
+)
+
+var fieldLabels = []string{"Сумма", "Тип", "Категория", "Дата", "Описание"}
+
+// form is the add/edit dialog. Fields are validated on every change,
+// errors are shown for fields the user has already visited.
+type form struct {
+	categoryService *services.CategoryService
+
+	editingID string
+	isIncome  bool
+	category  string
+
+	amount      textinput.Model
+	date        textinput.Model
+	description textinput.Model
+
+	focus     formField
+	touched   map[formField]bool
+	errors    map[formField]string
+	submitErr string
+}
+
+func newForm(t *models.Transaction, categoryService *services.CategoryService) form {
+	f := form{
+		categoryService: categoryService,
+		amount:          newInput("0.00"),
+		date:            newInput("ДД.ММ.ГГГГ ЧЧ:ММ"),
+		description:     newInput("Без описания"),
+		touched:         make(map[formField]bool),
+		errors:          make(map[formField]string),
+	}
+
+	f.date.SetValue(time.Now().Format(dateTimeLayout))
+
+	if t != nil {
+		f.editingID = t.ID
+		f.isIncome = t.Type == models.TransactionIncome
+		f.category = t.Category
+		f.amount.SetValue(strconv.FormatFloat(t.Amount, 'f', 2, 64))
+		f.date.SetValue(t.Date.Local().Format(dateTimeLayout))
+		f.description.SetValue(t.Description)
+	}
+
+	f.validate()
+	return f
+}
+
+func newInput(placeholder string) textinput.Model {
+	input := textinput.New()
+	input.Placeholder = placeholder
+	input.Prompt = ""
+	input.CharLimit = 120
+	input.Width = 40
+	return input
+}
+
+func (f *form) focusCmd() tea.Cmd {
+	f.amount.Blur()
+	f.date.Blur()
+	f.description.Blur()
+
+	switch f.focus {
+	case fieldAmount:
+		return f.amount.Focus()
+	case fieldDate:
+		return f.date.Focus()
+	case fieldDescription:
+		return f.description.Focus()
+	}
+	return nil
+}
+
+func (f *form) move(delta int) tea.Cmd {
+	f.touched[f.focus] = true
+	f.focus = formField((int(f.focus) + delta + int(fieldCount)) % int(fieldCount))
+	f.validate()
+	return f.focusCmd()
+}
+
+func (f *form) transactionType() string {
+	if f.isIncome {
+		return string(models.TransactionIncome)
+	}
+	return string(models.TransactionExpense)
+}
+
+// validate checks every field and stores the messages in f.errors.
+func (f *form) validate() {
+	f.errors = make(map[formField]string)
+
+	amount, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(f.amount.Value()), ",", "."), 64)
+	if err != nil {
+		f.errors[fieldAmount] = "введите число"
+	} else if amount <= 0 {
+		f.errors[fieldAmount] = "сумма должна быть положительной"
+	}
+
+	if f.category == "" {
+		f.errors[fieldCategory] = "выберите категорию (enter)"
+	}
+
+	if _, err := parseFormDate(f.date.Value()); err != nil {
+		f.errors[fieldDate] = "формат ДД.ММ.ГГГГ ЧЧ:ММ"
+	}
+}
+
+func (f *form) values() (amount float64, date time.Time, description string) {
+	amount, _ = strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(f.amount.Value()), ",", "."), 64)
+	date, _ = parseFormDate(f.date.Value())
+	description = strings.TrimSpace(f.description.Value())
+	if description == "" {
+		description = "Без описания"
+	}
+	return amount, date, description
+}
+
+func parseFormDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.ParseInLocation(dateTimeLayout, s, time.Local); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation("02.01.2006", s, time.Local)
+}
+
+func (f form) View() string {
+	title := "Новая транзакция"
+	if f.editingID != "" {
+		title = "Редактирование транзакции"
+	}
+
+	typeValue := "[ Расход ]   Доход  "
+	if f.isIncome {
+		typeValue = "  Расход   [ Доход ]"
+	}
+
+	category := f.category
+	if category == "" {
+		category = hintStyle.Render("не выбрана")
+	}
+
+	values := []string{
+		f.amount.View(),
+		typeValue,
+		category,
+		f.date.View(),
+		f.description.View(),
+	}
+
+	lines := []string{selectedStyle.Render(title), ""}
+	for i, value := range values {
+		field := formField(i)
+		label := labelStyle.Render(fieldLabels[i])
+		if field == f.focus {
+			label = focusedLabelStyle.Render("› " + fieldLabels[i])
+		}
+
+		line := label + value
+		if msg, ok := f.errors[field]; ok && f.touched[field] {
+			line += "  " + errorStyle.Render(msg)
+		}
+		lines = append(lines, line)
+	}
+
+	if f.submitErr != "" {
+		lines = append(lines, "", errorStyle.Render(f.submitErr))
+	}
+
+	return boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
+
+func (m *model) updateForm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	f := &m.form
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "esc":
+			m.view = viewTable
+			return m, nil
+		case "tab", "down":
+			return m, f.move(1)
+		case "shift+tab", "up":
+			return m, f.move(-1)
+		case "ctrl+s":
+			return m, m.submitForm()
+		case "enter":
+			switch f.focus {
+			case fieldCategory:
+				m.openPicker()
+				return m, m.picker.filter.Focus()
+			case fieldDescription:
+				return m, m.submitForm()
+			default:
+				return m, f.move(1)
+			}
+		}
+
+		if f.focus == fieldType {
+			switch keyMsg.String() {
+			case "left", "right", " ", "h", "l":
+				f.isIncome = !f.isIncome
+				// the chosen category belongs to the other type now
+				f.category = ""
+				f.touched[fieldCategory] = true
+				f.validate()
+			}
+			return m, nil
+		}
+
+		if f.focus == fieldCategory {
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	switch f.focus {
+	case fieldAmount:
+		f.amount, cmd = f.amount.Update(msg)
+	case fieldDate:
+		f.date, cmd = f.date.Update(msg)
+	case fieldDescription:
+		f.description, cmd = f.description.Update(msg)
+	}
+	f.touched[f.focus] = true
+	f.validate()
+	return m, cmd
+}
+
+func (m *model) submitForm() tea.Cmd {
+	f := &m.form
+	for field := formField(0); field < fieldCount; field++ {
+		f.touched[field] = true
+	}
+
+	f.validate()
+	if len(f.errors) > 0 {
+		f.submitErr = "Исправьте ошибки в форме"
+		return nil
+	}
+
+	amount, date, description := f.values()
+
+	var err error
+	if f.editingID == "" {
+		_, err = m.transactionService.AddTransactionAt(amount, f.category, description, f.transactionType(), date)
+	} else {
+		_, err = m.transactionService.UpdateTransaction(f.editingID, amount, f.category, description, f.transactionType(), date)
+	}
+	if err != nil {
+		f.submitErr = fmt.Sprintf("Ошибка при сохранении: %v", err)
+		return nil
+	}
+
+	if f.editingID == "" {
+		m.setStatus("Транзакция добавлена")
+	} else {
+		m.setStatus("Транзакция изменена")
+	}
+
+	if err := m.reload(); err != nil {
+		m.setError(err.Error())
+	}
+	m.view = viewTable
+	return nil
+}
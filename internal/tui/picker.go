@@ -0,0 +1,114 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// picker lets the user choose a category of the form's transaction type.
+type picker struct {
+	options []string
+	filter  textinput.Model
+	cursor  int
+	err     string
+}
+
+func (m *model) openPicker() {
+	p := picker{filter: newInput("фильтр")}
+
+	categories, err := m.categoryService.GetCategoriesByType(m.form.isIncome)
+	if err != nil {
+		p.err = "Ошибка загрузки категорий: " + err.Error()
+	}
+	for _, c := range categories {
+		p.options = append(p.options, c.Name)
+	}
+
+	for i, name := range p.options {
+		if strings.EqualFold(name, m.form.category) {
+			p.cursor = i
+		}
+	}
+
+	m.picker = p
+	m.view = viewPicker
+}
+
+func (p picker) visible() []string {
+	query := strings.ToLower(strings.TrimSpace(p.filter.Value()))
+	if query == "" {
+		return p.options
+	}
+
+	var result []string
+	for _, name := range p.options {
+		if strings.Contains(strings.ToLower(name), query) {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+func (p picker) View() string {
+	lines := []string{selectedStyle.Render("Выбор категории"), "", "Фильтр: " + p.filter.View(), ""}
+
+	options := p.visible()
+	if len(options) == 0 {
+		lines = append(lines, hintStyle.Render("Нет подходящих категорий"))
+	}
+	for i, name := range options {
+		if i == p.cursor {
+			lines = append(lines, selectedStyle.Render("› "+name))
+		} else {
+			lines = append(lines, "  "+name)
+		}
+	}
+
+	if p.err != "" {
+		lines = append(lines, "", errorStyle.Render(p.err))
+	}
+
+	return boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
+
+func (m *model) updatePicker(msg tea.Msg) (tea.Model, tea.Cmd) {
+	p := &m.picker
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "esc":
+			m.view = viewForm
+			return m, m.form.focusCmd()
+		case "up":
+			if p.cursor > 0 {
+				p.cursor--
+			}
+			return m, nil
+		case "down":
+			if p.cursor < len(p.visible())-1 {
+				p.cursor++
+			}
+			return m, nil
+		case "enter":
+			options := p.visible()
+			if p.cursor < len(options) {
+				m.form.category = options[p.cursor]
+				m.form.touched[fieldCategory] = true
+				m.form.validate()
+				m.form.focus = fieldDate
+			}
+			m.view = viewForm
+			return m, m.form.focusCmd()
+		}
+	}
+
+	var cmd tea.Cmd
+	p.filter, cmd = p.filter.Update(msg)
+	if p.cursor >= len(p.visible()) {
+		p.cursor = max(len(p.visible())-1, 0)
+	}
+	return m, cmd
+}
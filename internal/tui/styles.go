@@ -0,0 +1,32 @@
+package tui
+
+import "github.com/charmbracelet/lipgloss"
+
+var (
+	colorCyan  = lipgloss.Color("#3bdddd")
+	colorGreen = lipgloss.Color("#00ff0d")
+	colorRed   = lipgloss.Color("#ff0000")
+	colorGray  = lipgloss.Color("#777777")
+	colorBar   = lipgloss.Color("#303030")
+
+	titleStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#000000")).Background(colorCyan)
+	hintStyle  = lipgloss.NewStyle().Foreground(colorGray)
+	boxStyle   = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(colorCyan).Padding(0, 1)
+
+	statusStyle = lipgloss.NewStyle().Background(colorBar).Foreground(lipgloss.Color("#FFFFFF"))
+	statusGood  = lipgloss.NewStyle().Background(colorBar).Foreground(colorGreen).Bold(true)
+	statusBad   = lipgloss.NewStyle().Background(colorBar).Foreground(colorRed).Bold(true)
+
+	labelStyle        = lipgloss.NewStyle().Width(12)
+	focusedLabelStyle = labelStyle.Foreground(colorCyan).Bold(true)
+	errorStyle        = lipgloss.NewStyle().Foreground(colorRed)
+	selectedStyle     = lipgloss.NewStyle().Foreground(colorCyan).Bold(true)
+)
+
+const (
+	tableHints    = "a добавить • e изменить • d удалить • s сортировка • r порядок • c календарь • u отмена • ? помощь • q выход"
+	formHints     = "tab следующее поле • ←/→ тип • enter категория/сохранить • ctrl+s сохранить • esc отмена"
+	pickerHints   = "↑/↓ выбор • ввод текста — фильтр • enter выбрать • esc назад"
+	confirmHints  = "y удалить • n отмена"
+	calendarHints = "←/→ день • ↑/↓ неделя • [/] месяц • esc назад"
+)
@@ -0,0 +1,445 @@
+package tui
+
+import (
+	"fintrack/internal/models"
+	"fintrack/internal/services"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type view int
+
+const (
+	viewTable view = iota
+	viewForm
+	viewPicker
+	viewConfirmDelete
+	viewCalendar
+	viewHelp
+)
+
+type sortColumn int
+
+const (
+	sortByDate sortColumn = iota
+	sortByType
+	sortByCategory
+	sortByAmount
+	sortByDescription
+	sortColumnCount
+)
+
+const dateTimeLayout = "02.01.2006 15:04"
+
+type model struct {
+	transactionService *services.TransactionService
+	categoryService    *services.CategoryService
+	reportService      *services.ReportService
+	history            *services.History
+
+	view   view
+	width  int
+	height int
+
+	table    table.Model
+	items    []models.Transaction
+	sortBy   sortColumn
+	sortDesc bool
+
+	form     form
+	picker   picker
+	calendar calendar
+
+	income  float64
+	expense float64
+
+	status    string
+	statusErr bool
+}
+
+// Run starts the full-screen interface and blocks until the user quits.
+// history may be nil when undo is disabled.
+func Run(transactionService *services.TransactionService, categoryService *services.CategoryService, reportService *services.ReportService, history *services.History) error {
+	m := newModel(transactionService, categoryService, reportService)
+	m.history = history
+	if err := m.reload(); err != nil {
+		return err
+	}
+
+	_, err := tea.NewProgram(m, tea.WithAltScreen()).Run()
+	return err
+}
+
+func newModel(transactionService *services.TransactionService, categoryService *services.CategoryService, reportService *services.ReportService) *model {
+	t := table.New(
+		table.WithColumns(tableColumns(80, sortByDate, true)),
+		table.WithFocused(true),
+		table.WithHeight(10),
+	)
+
+	styles := table.DefaultStyles()
+	styles.Header = styles.Header.
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(colorCyan).
+		BorderBottom(true).
+		Bold(true)
+	styles.Selected = styles.Selected.
+		Foreground(lipgloss.Color("#000000")).
+		Background(colorCyan)
+	t.SetStyles(styles)
+
+	return &model{
+		transactionService: transactionService,
+		categoryService:    categoryService,
+		reportService:      reportService,
+		table:              t,
+		sortBy:             sortByDate,
+		sortDesc:           true,
+		width:              80,
+		height:             24,
+	}
+}
+
+func (m *model) Init() tea.Cmd {
+	return nil
+}
+
+func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.resize()
+		return m, nil
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+	}
+
+	switch m.view {
+	case viewForm:
+		return m.updateForm(msg)
+	case viewPicker:
+		return m.updatePicker(msg)
+	case viewConfirmDelete:
+		return m.updateConfirmDelete(msg)
+	case viewCalendar:
+		return m.updateCalendar(msg)
+	case viewHelp:
+		if _, ok := msg.(tea.KeyMsg); ok {
+			m.view = viewTable
+		}
+		return m, nil
+	default:
+		return m.updateTable(msg)
+	}
+}
+
+func (m *model) View() string {
+	var body, hints string
+
+	switch m.view {
+	case viewForm:
+		body, hints = m.form.View(), formHints
+	case viewPicker:
+		body, hints = m.picker.View(), pickerHints
+	case viewConfirmDelete:
+		body, hints = m.confirmDeleteView(), confirmHints
+	case viewCalendar:
+		body, hints = m.calendarView(), calendarHints
+	case viewHelp:
+		body, hints = helpView(), "любая клавиша — назад"
+	default:
+		body, hints = m.table.View(), tableHints
+	}
+
+	title := titleStyle.Render(" FinTrack ")
+	return lipgloss.JoinVertical(lipgloss.Left,
+		title,
+		body,
+		m.statusBar(),
+		hintStyle.Render(hints),
+	)
+}
+
+func (m *model) updateTable(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "q", "esc":
+			return m, tea.Quit
+		case "a", "n":
+			m.form = newForm(nil, m.categoryService)
+			m.view = viewForm
+			return m, m.form.focusCmd()
+		case "e", "enter":
+			if t, ok := m.selected(); ok {
+				m.form = newForm(&t, m.categoryService)
+				m.view = viewForm
+				return m, m.form.focusCmd()
+			}
+			return m, nil
+		case "d", "delete":
+			if _, ok := m.selected(); ok {
+				m.view = viewConfirmDelete
+			}
+			return m, nil
+		case "s":
+			m.sortBy = (m.sortBy + 1) % sortColumnCount
+			m.applySort()
+			return m, nil
+		case "r":
+			m.sortDesc = !m.sortDesc
+			m.applySort()
+			return m, nil
+		case "c":
+			m.calendar = newCalendar()
+			m.view = viewCalendar
+			return m, nil
+		case "u":
+			m.undo()
+			return m, nil
+		case "U":
+			m.redo()
+			return m, nil
+		case "?":
+			m.view = viewHelp
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.table, cmd = m.table.Update(msg)
+	return m, cmd
+}
+
+func (m *model) undo() {
+	if m.history == nil {
+		m.setError("История действий отключена")
+		return
+	}
+	entry, err := m.history.Undo()
+	if err != nil {
+		m.setError(err.Error())
+		return
+	}
+	m.setStatus("Отменено: " + entry.Label)
+	if err := m.reload(); err != nil {
+		m.setError(err.Error())
+	}
+}
+
+func (m *model) redo() {
+	if m.history == nil {
+		m.setError("История действий отключена")
+		return
+	}
+	entry, err := m.history.Redo()
+	if err != nil {
+		m.setError(err.Error())
+		return
+	}
+	m.setStatus("Повторено: " + entry.Label)
+	if err := m.reload(); err != nil {
+		m.setError(err.Error())
+	}
+}
+
+func (m *model) updateConfirmDelete(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "y", "н", "enter":
+		if t, ok := m.selected(); ok {
+			if err := m.transactionService.DeleteTransaction(t.ID); err != nil {
+				m.setError(fmt.Sprintf("Ошибка при удалении: %v", err))
+			} else {
+				m.setStatus("Транзакция удалена")
+			}
+			if err := m.reload(); err != nil {
+				m.setError(err.Error())
+			}
+		}
+		m.view = viewTable
+	case "n", "т", "esc", "q":
+		m.view = viewTable
+	}
+	return m, nil
+}
+
+func (m *model) confirmDeleteView() string {
+	t, ok := m.selected()
+	if !ok {
+		return ""
+	}
+	return boxStyle.Render(fmt.Sprintf("Удалить транзакцию?\n\n%s  %s  %.2f\n%s\n\n(y — да, n — нет)",
+		t.Date.Local().Format(dateTimeLayout), t.Category, t.Amount, t.Description))
+}
+
+// reload reads transactions from the service and refreshes the table and totals.
+func (m *model) reload() error {
+	transactions, err := m.transactionService.GetAllTransactions()
+	if err != nil {
+		return fmt.Errorf("ошибка при получении транзакций: %v", err)
+	}
+
+	m.items = transactions
+	m.income, m.expense = 0, 0
+	for _, t := range transactions {
+		if t.Type == models.TransactionExpense {
+			m.expense += t.Amount
+		} else {
+			m.income += t.Amount
+		}
+	}
+
+	m.applySort()
+	return nil
+}
+
+func (m *model) applySort() {
+	less := func(a, b models.Transaction) bool {
+		switch m.sortBy {
+		case sortByType:
+			return a.Type < b.Type
+		case sortByCategory:
+			return strings.ToLower(a.Category) < strings.ToLower(b.Category)
+		case sortByAmount:
+			return a.Amount < b.Amount
+		case sortByDescription:
+			return strings.ToLower(a.Description) < strings.ToLower(b.Description)
+		default:
+			return a.Date.Before(b.Date)
+		}
+	}
+
+	sort.SliceStable(m.items, func(i, j int) bool {
+		if m.sortDesc {
+			return less(m.items[j], m.items[i])
+		}
+		return less(m.items[i], m.items[j])
+	})
+
+	rows := make([]table.Row, len(m.items))
+	for i, t := range m.items {
+		typ := "Доход"
+		if t.Type == models.TransactionExpense {
+			typ = "Расход"
+		}
+		rows[i] = table.Row{
+			t.Date.Local().Format(dateTimeLayout),
+			typ,
+			t.Category,
+			fmt.Sprintf("%.2f", t.Amount),
+			t.Description,
+		}
+	}
+
+	m.table.SetColumns(tableColumns(m.width, m.sortBy, m.sortDesc))
+	m.table.SetRows(rows)
+	if m.table.Cursor() >= len(rows) {
+		m.table.SetCursor(len(rows) - 1)
+	}
+}
+
+func (m *model) resize() {
+	// title, status bar and hints take one line each, the table header two
+	m.table.SetHeight(max(m.height-5, 3))
+	m.table.SetWidth(m.width)
+	m.table.SetColumns(tableColumns(m.width, m.sortBy, m.sortDesc))
+}
+
+func (m *model) selected() (models.Transaction, bool) {
+	i := m.table.Cursor()
+	if i < 0 || i >= len(m.items) {
+		return models.Transaction{}, false
+	}
+	return m.items[i], true
+}
+
+func (m *model) setStatus(s string) {
+	m.status, m.statusErr = s, false
+}
+
+func (m *model) setError(s string) {
+	m.status, m.statusErr = s, true
+}
+
+func (m *model) statusBar() string {
+	balance := m.income - m.expense
+	balanceStyle := statusGood
+	if balance < 0 {
+		balanceStyle = statusBad
+	}
+
+	left := statusStyle.Render(fmt.Sprintf(" Доход: %.2f  Расход: %.2f  ", m.income, m.expense)) +
+		balanceStyle.Render(fmt.Sprintf(" Баланс: %.2f ", balance)) +
+		statusStyle.Render(fmt.Sprintf("  Транзакций: %d ", len(m.items)))
+
+	message := m.status
+	style := statusStyle
+	if m.statusErr {
+		style = statusBad
+	}
+	right := ""
+	if message != "" {
+		right = style.Render(" " + message + " ")
+	}
+
+	gap := max(m.width-lipgloss.Width(left)-lipgloss.Width(right), 0)
+	return left + statusStyle.Render(strings.Repeat(" ", gap)) + right
+}
+
+func tableColumns(width int, sortBy sortColumn, desc bool) []table.Column {
+	titles := []string{"Дата", "Тип", "Категория", "Сумма", "Описание"}
+	widths := []int{16, 7, 16, 12, 0}
+
+	used := 0
+	for _, w := range widths {
+		used += w + 2
+	}
+	widths[len(widths)-1] = max(width-used, 12)
+
+	arrow := " ▲"
+	if desc {
+		arrow = " ▼"
+	}
+
+	columns := make([]table.Column, len(titles))
+	for i, title := range titles {
+		if sortColumn(i) == sortBy {
+			title += arrow
+		}
+		columns[i] = table.Column{Title: title, Width: widths[i]}
+	}
+	return columns
+}
+
+func helpView() string {
+	return boxStyle.Render(strings.Join([]string{
+		"Таблица:",
+		"  ↑/↓, pgup/pgdn  прокрутка",
+		"  a               добавить транзакцию",
+		"  e, enter        редактировать выбранную",
+		"  d               удалить выбранную",
+		"  s               сменить столбец сортировки",
+		"  r               обратный порядок сортировки",
+		"  c               календарь расходов",
+		"  u / U           отменить / повторить действие",
+		"  q               выход",
+		"",
+		"Форма:",
+		"  tab/shift+tab   следующее/предыдущее поле",
+		"  ←/→, пробел     сменить тип транзакции",
+		"  enter           выбрать категорию / сохранить",
+		"  ctrl+s          сохранить",
+		"  esc             отмена",
+	}, "\n"))
+}
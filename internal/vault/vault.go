@@ -0,0 +1,300 @@
+package vault
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fintrack/internal/storage"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	FileName = "vault.json"
+
+	// journalName holds a re-encryption in progress: the header that takes
+	// effect and the files whose new contents are staged next to them.
+	journalName = "vault.json.new"
+	stagedExt   = ".recode.tmp"
+
+	keyLength = 32
+	saltSize  = 16
+
+	// scrypt parameters recommended for interactive logins
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+var (
+	ErrWrongPassphrase = errors.New("неверная парольная фраза")
+	ErrNotEncrypted    = errors.New("файл не зашифрован")
+	ErrEmptyPassphrase = errors.New("парольная фраза не может быть пустой")
+
+	magic      = []byte("FTENC1\n")
+	checkPlain = []byte("fintrack-vault-check")
+)
+
+// header is stored next to the encrypted files. It holds the KDF parameters
+// and a known plaintext encrypted with the derived key, so a wrong
+// passphrase is detected before any data file is touched.
+type header struct {
+	Version int    `json:"version"`
+	KDF     string `json:"kdf"`
+	N       int    `json:"n"`
+	R       int    `json:"r"`
+	P       int    `json:"p"`
+	Salt    []byte `json:"salt"`
+	Check   []byte `json:"check"`
+}
+
+// journal is written once every file has been staged, so from then on the
+// change can always be finished. Header is nil when encryption is removed.
+type journal struct {
+	Header *header  `json:"header,omitempty"`
+	Files  []string `json:"files"`
+}
+
+// Cipher encrypts files with AES-256-GCM and implements storage.Codec.
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+var _ storage.Codec = (*Cipher)(nil)
+
+// Exists reports whether the directory holds an encrypted ledger.
+func Exists(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, FileName))
+	return err == nil
+}
+
+// Open derives the key from the passphrase and verifies it against the vault header.
+func Open(dir, passphrase string) (*Cipher, error) {
+	data, err := os.ReadFile(filepath.Join(dir, FileName))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения %s: %w", FileName, err)
+	}
+
+	var h header
+	if err := json.Unmarshal(data, &h); err != nil {
+		return nil, fmt.Errorf("ошибка разбора %s: %w", FileName, err)
+	}
+	if h.KDF != "scrypt" {
+		return nil, fmt.Errorf("неподдерживаемый алгоритм ключа: %s", h.KDF)
+	}
+
+	c, err := newCipher(passphrase, h)
+	if err != nil {
+		return nil, err
+	}
+
+	plain, err := c.Decode(h.Check)
+	if err != nil || !bytes.Equal(plain, checkPlain) {
+		return nil, ErrWrongPassphrase
+	}
+	return c, nil
+}
+
+// Enable encrypts the given plaintext files in dir with a new passphrase.
+func Enable(dir, passphrase string, files []string) (*Cipher, error) {
+	if Exists(dir) {
+		return nil, fmt.Errorf("данные уже зашифрованы")
+	}
+
+	c, h, err := newHeader(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if err := recode(dir, files, storage.PlainCodec{}, c, &h); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Rotate re-encrypts the files with a key derived from a new passphrase.
+func Rotate(dir, oldPassphrase, newPassphrase string, files []string) (*Cipher, error) {
+	old, err := Open(dir, oldPassphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	c, h, err := newHeader(newPassphrase)
+	if err != nil {
+		return nil, err
+	}
+	if err := recode(dir, files, old, c, &h); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Disable decrypts the files and removes the vault header.
+func Disable(dir, passphrase string, files []string) error {
+	c, err := Open(dir, passphrase)
+	if err != nil {
+		return err
+	}
+	return recode(dir, files, c, storage.PlainCodec{}, nil)
+}
+
+// Recover finishes a re-encryption that was interrupted after its journal
+// was written, so the files and the header agree again. It must run before
+// the profile is opened.
+func Recover(dir string) error {
+	data, err := os.ReadFile(filepath.Join(dir, journalName))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("ошибка чтения %s: %w", journalName, err)
+	}
+	var j journal
+	if err := json.Unmarshal(data, &j); err != nil {
+		return fmt.Errorf("ошибка разбора %s: %w", journalName, err)
+	}
+	return commit(dir, j)
+}
+
+func newHeader(passphrase string) (*Cipher, header, error) {
+	if passphrase == "" {
+		return nil, header{}, ErrEmptyPassphrase
+	}
+
+	h := header{Version: 1, KDF: "scrypt", N: scryptN, R: scryptR, P: scryptP, Salt: make([]byte, saltSize)}
+	if _, err := io.ReadFull(rand.Reader, h.Salt); err != nil {
+		return nil, header{}, err
+	}
+
+	c, err := newCipher(passphrase, h)
+	if err != nil {
+		return nil, header{}, err
+	}
+
+	if h.Check, err = c.Encode(checkPlain); err != nil {
+		return nil, header{}, err
+	}
+	return c, h, nil
+}
+
+func newCipher(passphrase string, h header) (*Cipher, error) {
+	key, err := scrypt.Key([]byte(passphrase), h.Salt, h.N, h.R, h.P, keyLength)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка вычисления ключа: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Cipher{aead: aead}, nil
+}
+
+func writeHeader(dir string, h header) error {
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	return storage.WriteFile(storage.PlainCodec{}, filepath.Join(dir, FileName), data)
+}
+
+// recode decodes every existing file with from and re-encodes it with to,
+// then switches the header to h, or removes it when h is nil. The new
+// contents are staged in temporary files first and a journal is written
+// before any file is replaced, so an interruption either leaves everything
+// unchanged or is finished by Recover.
+func recode(dir string, files []string, from, to storage.Codec, h *header) error {
+	j := journal{Header: h}
+	staged := func() {
+		for _, path := range j.Files {
+			os.Remove(path + stagedExt)
+		}
+	}
+
+	for _, path := range files {
+		plain, err := storage.ReadFile(from, path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err == nil {
+			err = storage.WriteFile(to, path+stagedExt, plain)
+		}
+		if err != nil {
+			staged()
+			return fmt.Errorf("%s: %w", filepath.Base(path), err)
+		}
+		j.Files = append(j.Files, path)
+	}
+
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err == nil {
+		err = storage.WriteFile(storage.PlainCodec{}, filepath.Join(dir, journalName), data)
+	}
+	if err != nil {
+		staged()
+		return err
+	}
+	return commit(dir, j)
+}
+
+// commit moves the staged files into place, then switches the header and
+// drops the journal. Each step can be repeated after an interruption.
+func commit(dir string, j journal) error {
+	for _, path := range j.Files {
+		err := os.Rename(path+stagedExt, path)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("%s: %w", filepath.Base(path), err)
+		}
+	}
+
+	if j.Header != nil {
+		if err := writeHeader(dir, *j.Header); err != nil {
+			return err
+		}
+	} else if err := os.Remove(filepath.Join(dir, FileName)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return os.Remove(filepath.Join(dir, journalName))
+}
+
+// Encode encrypts plain as magic | nonce | ciphertext.
+func (c *Cipher) Encode(plain []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 0, len(magic)+len(nonce)+len(plain)+c.aead.Overhead())
+	out = append(out, magic...)
+	out = append(out, nonce...)
+	return c.aead.Seal(out, nonce, plain, magic), nil
+}
+
+// Decode verifies and decrypts data produced by Encode.
+func (c *Cipher) Decode(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, magic) {
+		return nil, ErrNotEncrypted
+	}
+	data = data[len(magic):]
+
+	nonceSize := c.aead.NonceSize()
+	if len(data) < nonceSize {
+		return nil, fmt.Errorf("зашифрованный файл поврежден")
+	}
+
+	plain, err := c.aead.Open(nil, data[:nonceSize], data[nonceSize:], magic)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось расшифровать данные: ключ не подходит или файл поврежден")
+	}
+	return plain, nil
+}
@@ -0,0 +1,274 @@
+package vault
+
+import (
+	"encoding/json"
+	"errors"
+	"fintrack/internal/storage"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testCipher builds a cipher with cheap scrypt parameters so the tests run fast.
+func testCipher(t *testing.T, passphrase string) (*Cipher, header) {
+	t.Helper()
+	h := header{Version: 1, KDF: "scrypt", N: 1 << 10, R: 8, P: 1, Salt: []byte("0123456789abcdef")}
+	c, err := newCipher(passphrase, h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.Check, err = c.Encode(checkPlain); err != nil {
+		t.Fatal(err)
+	}
+	return c, h
+}
+
+func writeFiles(t *testing.T, codec storage.Codec, files map[string]string) {
+	t.Helper()
+	for path, content := range files {
+		if err := storage.WriteFile(codec, path, []byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func checkFiles(t *testing.T, codec storage.Codec, files map[string]string) {
+	t.Helper()
+	for path, want := range files {
+		got, err := storage.ReadFile(codec, path)
+		if err != nil {
+			t.Errorf("%s: %v", filepath.Base(path), err)
+			continue
+		}
+		if string(got) != want {
+			t.Errorf("%s = %q, want %q", filepath.Base(path), got, want)
+		}
+	}
+}
+
+// checkClean fails when a journal or a staged file is left in dir.
+func checkClean(t *testing.T, dir string) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if e.Name() == journalName || filepath.Ext(e.Name()) == filepath.Ext(stagedExt) {
+			t.Errorf("left behind: %s", e.Name())
+		}
+	}
+}
+
+func TestRecode(t *testing.T) {
+	c, h := testCipher(t, "секрет")
+	plain := storage.PlainCodec{}
+
+	tests := []struct {
+		name       string
+		from, to   storage.Codec
+		header     *header
+		existing   bool // vault.json is there before the change
+		files      map[string]string
+		missing    []string
+		wantErr    bool
+		wantHeader bool
+	}{
+		{
+			name:       "encrypt",
+			from:       plain,
+			to:         c,
+			header:     &h,
+			files:      map[string]string{"transactions.json": "[1]", "categories.json": "[2]"},
+			wantHeader: true,
+		},
+		{
+			name:     "decrypt",
+			from:     c,
+			to:       plain,
+			existing: true,
+			files:    map[string]string{"transactions.json": "[1]", "goals.json": "[]"},
+		},
+		{
+			name:       "missing files are skipped",
+			from:       plain,
+			to:         c,
+			header:     &h,
+			files:      map[string]string{"transactions.json": "[1]"},
+			missing:    []string{"payees.json"},
+			wantHeader: true,
+		},
+		{
+			name:       "unreadable file changes nothing",
+			from:       c,
+			to:         plain,
+			existing:   true,
+			files:      map[string]string{"transactions.json": "[1]"},
+			wantErr:    true,
+			wantHeader: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.existing {
+				if err := writeHeader(dir, h); err != nil {
+					t.Fatal(err)
+				}
+			}
+			files := map[string]string{}
+			var paths []string
+			for name, content := range tt.files {
+				path := filepath.Join(dir, name)
+				files[path] = content
+				paths = append(paths, path)
+			}
+			writeFiles(t, tt.from, files)
+			if tt.wantErr {
+				// a plaintext file among encrypted ones cannot be decoded
+				writeFiles(t, plain, map[string]string{filepath.Join(dir, "broken.json"): "{}"})
+				paths = append(paths, filepath.Join(dir, "broken.json"))
+			}
+			for _, name := range tt.missing {
+				paths = append(paths, filepath.Join(dir, name))
+			}
+
+			err := recode(dir, paths, tt.from, tt.to, tt.header)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("recode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				checkFiles(t, tt.from, files)
+			} else {
+				checkFiles(t, tt.to, files)
+			}
+			for _, name := range tt.missing {
+				if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+					t.Errorf("%s was created", name)
+				}
+			}
+			if Exists(dir) != tt.wantHeader {
+				t.Errorf("Exists() = %v, want %v", Exists(dir), tt.wantHeader)
+			}
+			checkClean(t, dir)
+		})
+	}
+}
+
+func TestRecover(t *testing.T) {
+	c, h := testCipher(t, "секрет")
+	plain := storage.PlainCodec{}
+
+	tests := []struct {
+		name       string
+		journal    string // "header", "no header" or "damaged"; empty means there is none
+		staged     map[string]string
+		committed  map[string]string // already renamed before the interruption
+		old        map[string]string // still in the previous encoding
+		codec      storage.Codec     // encoding of the files after recovery
+		wantErr    bool
+		wantHeader bool
+	}{
+		{
+			name:  "no journal",
+			old:   map[string]string{"transactions.json": "[1]"},
+			codec: plain,
+		},
+		{
+			name:       "nothing renamed yet",
+			journal:    "header",
+			staged:     map[string]string{"transactions.json": "[1]", "categories.json": "[2]"},
+			codec:      c,
+			wantHeader: true,
+		},
+		{
+			name:       "some files renamed",
+			journal:    "header",
+			staged:     map[string]string{"categories.json": "[2]"},
+			committed:  map[string]string{"transactions.json": "[1]"},
+			codec:      c,
+			wantHeader: true,
+		},
+		{
+			name:      "decryption removes the header",
+			journal:   "no header",
+			committed: map[string]string{"transactions.json": "[1]"},
+			codec:     plain,
+		},
+		{
+			name:    "damaged journal",
+			journal: "damaged",
+			staged:  map[string]string{"transactions.json": "[1]"},
+			codec:   c,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := func(name string) string { return filepath.Join(dir, name) }
+
+			j := journal{}
+			final := map[string]string{}
+			for name, content := range tt.staged {
+				writeFiles(t, plain, map[string]string{path(name): "старое"})
+				writeFiles(t, tt.codec, map[string]string{path(name) + stagedExt: content})
+				j.Files = append(j.Files, path(name))
+				final[path(name)] = content
+			}
+			for name, content := range tt.committed {
+				writeFiles(t, tt.codec, map[string]string{path(name): content})
+				j.Files = append(j.Files, path(name))
+				final[path(name)] = content
+			}
+			for name, content := range tt.old {
+				writeFiles(t, tt.codec, map[string]string{path(name): content})
+				final[path(name)] = content
+			}
+
+			var data []byte
+			switch tt.journal {
+			case "header":
+				j.Header = &h
+				data, _ = json.Marshal(j)
+			case "no header":
+				if err := writeHeader(dir, h); err != nil {
+					t.Fatal(err)
+				}
+				data, _ = json.Marshal(j)
+			case "damaged":
+				data = []byte("{")
+			}
+			if data != nil {
+				if err := os.WriteFile(path(journalName), data, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := Recover(dir)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Recover() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			checkFiles(t, tt.codec, final)
+			if Exists(dir) != tt.wantHeader {
+				t.Errorf("Exists() = %v, want %v", Exists(dir), tt.wantHeader)
+			}
+			checkClean(t, dir)
+			if tt.wantHeader {
+				if _, err := Open(dir, "секрет"); err != nil {
+					t.Errorf("Open() after recovery: %v", err)
+				}
+				if _, err := Open(dir, "другой"); !errors.Is(err, ErrWrongPassphrase) {
+					t.Errorf("Open() with a wrong passphrase: %v, want %v", err, ErrWrongPassphrase)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+"use strict";
+
+const PAGE_SIZE = 20;
+const state = { offset: 0, total: 0 };
+
+const $ = (id) => document.getElementById(id);
+const money = (v) => v.toLocaleString("ru-RU", { minimumFractionDigits: 2, maximumFractionDigits: 2 });
+
+async function api(path, options) {
+  const response = await fetch(path, options);
+  if (response.status === 204) {
+    return null;
+  }
+  const body = await response.json();
+  if (!response.ok) {
+    throw new Error(body.error || response.statusText);
+  }
+  return body;
+}
+
+async function loadCategories() {
+  const type = $("type").value;
+  const categories = await api(`/api/categories?type=${type}`);
+  const select = $("category");
+  select.innerHTML = "";
+  for (const c of categories) {
+    const option = document.createElement("option");
+    option.value = c.name;
+    option.textContent = c.name;
+    select.appendChild(option);
+  }
+}
+
+async function loadTransactions() {
+  const params = new URLSearchParams({ limit: PAGE_SIZE, offset: state.offset });
+  if ($("filter-type").value) params.set("type", $("filter-type").value);
+  if ($("filter-q").value) params.set("q", $("filter-q").value);
+
+  const page = await api(`/api/transactions?${params}`);
+  state.total = page.total;
+
+  const body = $("transactions");
+  body.innerHTML = "";
+  if (page.items.length === 0) {
+    body.innerHTML = `<tr><td colspan="5" class="empty">Нет транзакций</td></tr>`;
+  }
+  for (const t of page.items) {
+    const row = document.createElement("tr");
+    row.className = t.type;
+    const cells = [
+      new Date(t.date).toLocaleString("ru-RU", { dateStyle: "short", timeStyle: "short" }),
+      t.type === "expense" ? "Расход" : "Доход",
+      t.category,
+      (t.type === "expense" ? "−" : "+") + money(t.amount),
+      t.description,
+    ];
+    cells.forEach((text, i) => {
+      const cell = document.createElement("td");
+      cell.textContent = text;
+      if (i === 3) cell.className = "num";
+      row.appendChild(cell);
+    });
+    body.appendChild(row);
+  }
+
+  const last = Math.min(state.offset + PAGE_SIZE, state.total);
+  $("page-info").textContent = state.total ? `${state.offset + 1}–${last} из ${state.total}` : "";
+  $("prev").disabled = state.offset === 0;
+  $("next").disabled = last >= state.total;
+}
+
+async function loadSummaries() {
+  const years = await api("/api/summaries?period=year");
+  let income = 0, expense = 0;
+  for (const y of years) {
+    income += y.income;
+    expense += y.expense;
+  }
+  $("total-income").textContent = money(income);
+  $("total-expense").textContent = money(expense);
+  $("total-balance").textContent = money(income - expense);
+
+  const months = await api("/api/summaries?period=month&last=12");
+  renderMonthlyChart(months);
+
+  const current = months[months.length - 1];
+  renderCategoryChart(current);
+}
+
+function renderMonthlyChart(months) {
+  const container = $("monthly-chart");
+  if (months.length === 0) {
+    container.innerHTML = `<p class="empty">Нет данных</p>`;
+    return;
+  }
+
+  const width = 600, height = 220, padding = 20;
+  const maxValue = Math.max(...months.map((m) => Math.max(m.income, m.expense)), 1);
+  const slot = (width - padding) / months.length;
+  const barWidth = Math.max(slot / 2 - 4, 2);
+  const scale = (v) => (v / maxValue) * (height - padding * 2);
+
+  let svg = `<svg viewBox="0 0 ${width} ${height}" preserveAspectRatio="none">`;
+  months.forEach((m, i) => {
+    const x = padding + i * slot;
+    const hi = scale(m.income), he = scale(m.expense);
+    svg += `<rect class="income" x="${x}" y="${height - padding - hi}" width="${barWidth}" height="${hi}"><title>${m.label}: доход ${money(m.income)}</title></rect>`;
+    svg += `<rect class="expense" x="${x + barWidth + 2}" y="${height - padding - he}" width="${barWidth}" height="${he}"><title>${m.label}: расход ${money(m.expense)}</title></rect>`;
+    svg += `<text x="${x}" y="${height - 5}">${m.label.slice(2)}</text>`;
+  });
+  svg += "</svg>";
+  container.innerHTML = svg;
+}
+
+function renderCategoryChart(summary) {
+  const container = $("category-chart");
+  container.innerHTML = "";
+  const categories = summary ? summary.categories.filter((c) => c.type === "expense") : [];
+  $("category-period").textContent = summary ? summary.label : "";
+
+  if (categories.length === 0) {
+    container.innerHTML = `<p class="empty">Нет расходов</p>`;
+    return;
+  }
+
+  const maxValue = Math.max(...categories.map((c) => c.total));
+  for (const c of categories) {
+    const row = document.createElement("div");
+    row.className = "row";
+    row.innerHTML = `<span></span><div class="bar"></div><span class="value"></span>`;
+    row.children[0].textContent = c.category;
+    row.children[1].style.width = `${(c.total / maxValue) * 100}%`;
+    row.children[2].textContent = money(c.total);
+    container.appendChild(row);
+  }
+}
+
+async function submitTransaction(event) {
+  event.preventDefault();
+  const form = event.target;
+  const message = $("form-message");
+  const data = new FormData(form);
+
+  const payload = {
+    type: data.get("type"),
+    amount: parseFloat(data.get("amount")),
+    category: data.get("category"),
+    description: data.get("description").trim() || "Без описания",
+  };
+  if (data.get("date")) {
+    payload.date = new Date(data.get("date")).toISOString();
+  }
+
+  try {
+    await api("/api/transactions", {
+      method: "POST",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify(payload),
+    });
+    message.className = "message ok";
+    message.textContent = "Транзакция добавлена";
+    form.amount.value = "";
+    form.description.value = "";
+    state.offset = 0;
+    await refresh();
+  } catch (err) {
+    message.className = "message error";
+    message.textContent = err.message;
+  }
+}
+
+async function refresh() {
+  await Promise.all([loadTransactions(), loadSummaries()]);
+}
+
+function init() {
+  $("type").addEventListener("change", loadCategories);
+  $("add-form").addEventListener("submit", submitTransaction);
+  $("filter-type").addEventListener("change", () => { state.offset = 0; loadTransactions(); });
+  $("filter-q").addEventListener("input", () => { state.offset = 0; loadTransactions(); });
+  $("prev").addEventListener("click", () => { state.offset = Math.max(state.offset - PAGE_SIZE, 0); loadTransactions(); });
+  $("next").addEventListener("click", () => { state.offset += PAGE_SIZE; loadTransactions(); });
+
+  loadCategories().catch(showError);
+  refresh().catch(showError);
+}
+
+function showError(err) {
+  const message = $("form-message");
+  message.className = "message error";
+  message.textContent = `Ошибка загрузки: ${err.message}`;
+}
+
+init();
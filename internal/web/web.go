@@ -0,0 +1,20 @@
+package web
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+//go:embed static
+var staticFiles embed.FS
+
+// Handler serves the dashboard's static files; the page itself talks to the JSON API.
+func Handler() http.Handler {
+	root, err := fs.Sub(staticFiles, "static")
+	if err != nil {
+		// the embedded directory is part of the binary, so this cannot happen at runtime
+		panic(err)
+	}
+	return http.FileServer(http.FS(root))
+}